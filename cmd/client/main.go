@@ -1,21 +1,40 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/client"
+	"github.com/desepticon55/gophkeeper/internal/client/importer"
 	"github.com/desepticon55/gophkeeper/pkg/logger"
 	"github.com/desepticon55/gophkeeper/pkg/version"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"os"
+	"strings"
 )
 
 func main() {
 	log := logger.InitLogger()
-	config := client.ParseConfig()
+	config, err := client.ParseConfig()
+	if err != nil {
+		log.Error("Error during parse config", zap.Error(err))
+		os.Exit(1)
+	}
 	rootCmd := &cobra.Command{}
 
-	fmt.Println(version.MakeBuildInfo(log))
+	// binary can be linked as git-credential-gophkeeper or docker-credential-gophkeeper
+	// global flags are parsed by config, commands get the rest of arguments
+	args := flag.Args()
+	if helperArgs, ok := client.CredentialHelperArgs(os.Args[0], args); ok {
+		args = helperArgs
+	}
+	rootCmd.SetArgs(args)
+
+	// build info is printed to stderr, so it does not mix with output of child process.
+	// Credential helpers are called by git and docker on each request, so it is skipped for them
+	if !client.IsCredentialHelper(args) {
+		fmt.Fprintln(os.Stderr, version.MakeBuildInfo(log))
+	}
 
 	authCmd := &cobra.Command{
 		Use:   "auth",
@@ -28,7 +47,7 @@ func main() {
 		{Name: "password", DefaultValue: "", Description: "User password for login"},
 	})
 	authRegistry.Register("login", &client.UserLoginCommandFactory{}, []client.FlagDef{
-		{Name: "username", DefaultValue: "", Description: "User email for login"},
+		{Name: "username", DefaultValue: "", Description: "User email for login, username of profile by default"},
 		{Name: "password", DefaultValue: "", Description: "User password for login"},
 	})
 
@@ -40,10 +59,48 @@ func main() {
 	secretRegistry := client.NewCommandRegistry(config, secretCmd)
 	secretRegistry.Register("read", &client.ReadCommandFactory{}, []client.FlagDef{
 		{Name: "name", DefaultValue: "", Description: "Secret name"},
+		{Name: "field", DefaultValue: "", Description: "Print only raw value of one field, e.g. password"},
+		{Name: "reveal", DefaultValue: "false", Description: "Print card number and code without mask", Bool: true},
+	})
+	secretRegistry.Register("edit", &client.EditCommandFactory{}, []client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name"},
 	})
 	secretRegistry.Register("delete", &client.DeleteCommandFactory{}, []client.FlagDef{
 		{Name: "name", DefaultValue: "", Description: "Secret name"},
 	})
+	secretRegistry.Register("rename", &client.RenameCommandFactory{}, []client.FlagDef{
+		{Name: "from", DefaultValue: "", Description: "Current secret name"},
+		{Name: "to", DefaultValue: "", Description: "New secret name"},
+	})
+	secretRegistry.Register("search", &client.SearchCommandFactory{}, []client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name prefix or glob pattern"},
+		{Name: "type", DefaultValue: "", Description: "Secret type"},
+		{Name: "tag", DefaultValue: "", Description: "Secret tag"},
+		{Name: "folder", DefaultValue: "", Description: "Secret folder, including subfolders"},
+		{Name: "query", DefaultValue: "", Description: "Text to find in decrypted secret data"},
+		{Name: "limit", DefaultValue: "", Description: "Max count of found secrets"},
+	})
+
+	secretRegistry.Register("due", &client.DueCommandFactory{}, []client.FlagDef{
+		{Name: "within", DefaultValue: "", Description: "Also list secrets due within duration from now, e.g. 30d"},
+	})
+	secretRegistry.Register("restore", &client.RestoreCommandFactory{}, []client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name"},
+	})
+	secretRegistry.Register("purge", &client.PurgeCommandFactory{}, []client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name"},
+	})
+	secretRegistry.Register("otp", &client.OTPCommandFactory{}, []client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "OTP secret name"},
+	})
+
+	secretTrashCmd := &cobra.Command{
+		Use:   "trash",
+		Short: "Secret trash commands",
+	}
+
+	secretTrashRegistry := client.NewCommandRegistry(config, secretTrashCmd)
+	secretTrashRegistry.Register("list", &client.TrashListCommandFactory{}, []client.FlagDef{})
 
 	secretCreateCmd := &cobra.Command{
 		Use:   "create",
@@ -51,25 +108,137 @@ func main() {
 	}
 
 	secretCreateRegistry := client.NewCommandRegistry(config, secretCreateCmd)
-	secretCreateRegistry.Register("credentials", &client.SaveCredentialsCommandFactory{}, []client.FlagDef{
+	secretCreateRegistry.Register("credentials", &client.SaveCredentialsCommandFactory{}, append([]client.FlagDef{
 		{Name: "name", DefaultValue: "", Description: "Secret name"},
 		{Name: "username", DefaultValue: "", Description: "User email for login"},
 		{Name: "password", DefaultValue: "", Description: "User password for login"},
-	})
-	secretCreateRegistry.Register("card", &client.SaveCardCommandFactory{}, []client.FlagDef{
+		{Name: "generate", DefaultValue: "false", Description: "Generate password instead of passing it", Bool: true},
+	}, append(client.MetadataFlags, client.PasswordFlags...)...))
+	secretCreateRegistry.Register("card", &client.SaveCardCommandFactory{}, append([]client.FlagDef{
 		{Name: "name", DefaultValue: "", Description: "Secret name"},
 		{Name: "number", DefaultValue: "", Description: "Card number"},
 		{Name: "date", DefaultValue: "", Description: "Card expire date"},
 		{Name: "code", DefaultValue: "", Description: "CVC code"},
 		{Name: "holder", DefaultValue: "", Description: "Holder"},
-	})
-	secretCreateRegistry.Register("text", &client.SaveTextCommandFactory{}, []client.FlagDef{
+	}, client.MetadataFlags...))
+	secretCreateRegistry.Register("text", &client.SaveTextCommandFactory{}, append([]client.FlagDef{
 		{Name: "name", DefaultValue: "", Description: "Secret name"},
 		{Name: "data", DefaultValue: "", Description: "Data"},
+	}, client.MetadataFlags...))
+	secretCreateRegistry.Register("ssh-key", &client.SaveSSHKeyCommandFactory{}, append([]client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name"},
+		{Name: "generate", DefaultValue: "false", Description: "Generate Ed25519 key", Bool: true},
+		{Name: "private-key-file", DefaultValue: "", Description: "Existing private key file in PEM format"},
+		{Name: "comment", DefaultValue: "", Description: "Key comment"},
+		{Name: "passphrase", DefaultValue: "", Description: "Private key passphrase"},
+	}, client.MetadataFlags...))
+	secretCreateRegistry.Register("otp", &client.SaveOTPCommandFactory{}, append([]client.FlagDef{
+		{Name: "name", DefaultValue: "", Description: "Secret name"},
+		{Name: "uri", DefaultValue: "", Description: "otpauth:// URI, e.g. from QR code"},
+		{Name: "secret", DefaultValue: "", Description: "Base32 encoded TOTP secret, if uri is not set"},
+		{Name: "issuer", DefaultValue: "", Description: "TOTP issuer"},
+		{Name: "account", DefaultValue: "", Description: "TOTP account name"},
+		{Name: "algorithm", DefaultValue: "SHA1", Description: "TOTP algorithm: SHA1, SHA256, SHA512"},
+		{Name: "digits", DefaultValue: "6", Description: "TOTP code length"},
+		{Name: "period", DefaultValue: "30", Description: "TOTP code period in seconds"},
+	}, client.MetadataFlags...))
+
+	folderCmd := &cobra.Command{
+		Use:   "folder",
+		Short: "Folder commands",
+	}
+
+	folderRegistry := client.NewCommandRegistry(config, folderCmd)
+	folderRegistry.Register("list", &client.FolderListCommandFactory{}, []client.FlagDef{
+		{Name: "path", DefaultValue: "", Description: "Folder path, root folder by default"},
+	})
+	folderRegistry.Register("move", &client.FolderMoveCommandFactory{}, []client.FlagDef{
+		{Name: "from", DefaultValue: "", Description: "Source folder path"},
+		{Name: "to", DefaultValue: "", Description: "Target folder path"},
+	})
+
+	cardCmd := &cobra.Command{
+		Use:   "card",
+		Short: "Card commands",
+	}
+
+	cardRegistry := client.NewCommandRegistry(config, cardCmd)
+	cardRegistry.Register("expired", &client.ExpiredCardsCommandFactory{}, []client.FlagDef{
+		{Name: "within", DefaultValue: "", Description: "Also list cards expiring within duration from now, e.g. 60d"},
+	})
+
+	accountCmd := &cobra.Command{
+		Use:   "account",
+		Short: "Account commands",
+	}
+
+	accountRegistry := client.NewCommandRegistry(config, accountCmd)
+	accountRegistry.Register("usage", &client.UsageCommandFactory{}, []client.FlagDef{})
+
+	vaultCmd := &cobra.Command{
+		Use:   "vault",
+		Short: "Vault commands",
+	}
+
+	vaultRegistry := client.NewCommandRegistry(config, vaultCmd)
+	vaultRegistry.Register("export", &client.VaultExportCommandFactory{}, []client.FlagDef{
+		{Name: "file", DefaultValue: "", Description: "Archive file"},
+		{Name: "passphrase", DefaultValue: "", Description: "Archive passphrase, encryption key by default"},
 	})
+	vaultRegistry.Register("import", &client.VaultImportCommandFactory{}, []client.FlagDef{
+		{Name: "file", DefaultValue: "", Description: "Archive file or export of another password manager"},
+		{Name: "passphrase", DefaultValue: "", Description: "Archive passphrase, encryption key by default"},
+		{Name: "format", DefaultValue: "gophkeeper", Description: "Import format: gophkeeper, " + strings.Join(importer.Formats, ", ")},
+		{Name: "dry-run", DefaultValue: "false", Description: "Print import report without uploading secrets", Bool: true},
+	})
+
+	rootRegistry := client.NewCommandRegistry(config, rootCmd)
+	rootRegistry.Register("generate", &client.GenerateCommandFactory{}, client.PasswordFlags)
+	rootRegistry.Register("run", &client.RunCommandFactory{}, []client.FlagDef{
+		{Name: "env", Description: "Environment variable filled by secret field, NAME=secret#field", Repeated: true},
+	})
+	rootRegistry.Register("render", &client.RenderCommandFactory{}, []client.FlagDef{
+		{Name: "input", Shorthand: "i", DefaultValue: "", Description: "Go template file"},
+		{Name: "output", Shorthand: "o", DefaultValue: "", Description: "Rendered file, written with 0600 permissions; stdout by default"},
+	})
+	rootRegistry.Register("git-credential", &client.GitCredentialCommandFactory{}, []client.FlagDef{
+		{Name: "folder", DefaultValue: "", Description: "Folder with git credentials, credentials folder of config by default"},
+	})
+	rootRegistry.Register("docker-credential", &client.DockerCredentialCommandFactory{}, []client.FlagDef{
+		{Name: "folder", DefaultValue: "", Description: "Folder with docker credentials, credentials folder of config by default"},
+	})
+	rootRegistry.Register("ssh-agent", &client.SSHAgentCommandFactory{}, []client.FlagDef{
+		{Name: "socket", DefaultValue: "", Description: "Agent unix socket, in XDG_RUNTIME_DIR by default"},
+		{Name: "folder", DefaultValue: "", Description: "Serve only keys from folder"},
+	})
+	rootRegistry.Register("ui", &client.UICommandFactory{}, []client.FlagDef{})
+
+	agentCmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Agent holding unlocked key and token",
+	}
+
+	agentRegistry := client.NewCommandRegistry(config, agentCmd)
+	agentIdleTimeoutFlag := client.FlagDef{Name: "idle-timeout", DefaultValue: "15m", Description: "Lock agent after idle duration, 0 disables auto lock"}
+	agentRegistry.Register(client.AgentStartOperation, &client.AgentCommandFactory{Operation: client.AgentStartOperation}, []client.FlagDef{agentIdleTimeoutFlag})
+	agentRegistry.Register(client.AgentServeOperation, &client.AgentCommandFactory{Operation: client.AgentServeOperation}, []client.FlagDef{agentIdleTimeoutFlag})
+	agentRegistry.Register(client.AgentUnlockOperation, &client.AgentCommandFactory{Operation: client.AgentUnlockOperation}, []client.FlagDef{})
+	agentRegistry.Register(client.AgentLockOperation, &client.AgentCommandFactory{Operation: client.AgentLockOperation}, []client.FlagDef{})
+	agentRegistry.Register(client.AgentStatusOperation, &client.AgentCommandFactory{Operation: client.AgentStatusOperation}, []client.FlagDef{})
+	agentRegistry.Register(client.AgentStopOperation, &client.AgentCommandFactory{Operation: client.AgentStopOperation}, []client.FlagDef{})
+
+	configCmd := &cobra.Command{
+		Use:   "config",
+		Short: "Configuration profiles",
+	}
+
+	configRegistry := client.NewCommandRegistry(config, configCmd)
+	configRegistry.Register(client.ConfigListOperation, &client.ConfigCommandFactory{Operation: client.ConfigListOperation}, []client.FlagDef{})
+	configRegistry.Register(client.ConfigGetOperation, &client.ConfigCommandFactory{Operation: client.ConfigGetOperation}, []client.FlagDef{})
+	configRegistry.Register(client.ConfigSetOperation, &client.ConfigCommandFactory{Operation: client.ConfigSetOperation}, []client.FlagDef{})
 
-	secretCmd.AddCommand(secretCreateCmd)
-	rootCmd.AddCommand(authCmd, secretCmd)
+	secretCmd.AddCommand(secretCreateCmd, secretTrashCmd)
+	rootCmd.AddCommand(authCmd, secretCmd, folderCmd, cardCmd, accountCmd, vaultCmd, agentCmd, configCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		log.Error("Error during execute command", zap.Error(err))
@@ -2,11 +2,19 @@ package main
 
 import (
 	"context"
+	"database/sql"
 	"flag"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/desepticon55/gophkeeper/internal/server/api/account"
 	"github.com/desepticon55/gophkeeper/internal/server/api/auth"
+	"github.com/desepticon55/gophkeeper/internal/server/api/folder"
+	"github.com/desepticon55/gophkeeper/internal/server/api/health"
 	"github.com/desepticon55/gophkeeper/internal/server/api/secret"
+	"github.com/desepticon55/gophkeeper/internal/server/api/trash"
+	"github.com/desepticon55/gophkeeper/internal/server/certificate"
+	"github.com/desepticon55/gophkeeper/internal/server/job"
+	"github.com/desepticon55/gophkeeper/internal/server/lifecycle"
 	customMiddleware "github.com/desepticon55/gophkeeper/internal/server/middleware"
 	secretSrv "github.com/desepticon55/gophkeeper/internal/server/service/secret"
 	"github.com/desepticon55/gophkeeper/internal/server/service/user"
@@ -19,7 +27,11 @@ import (
 	"github.com/jackc/pgx/v4/stdlib"
 	"github.com/pressly/goose/v3"
 	"go.uber.org/zap"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 	"time"
 )
 
@@ -32,7 +44,17 @@ func main() {
 		zap.String("Server address", config.ServerAddress),
 		zap.String("Database connection string", config.DatabaseConnString),
 		zap.String("Auth key", config.AuthKey),
-		zap.Int("Token expired after minutes", config.ExpirationMinutes))
+		zap.Int("Token expired after minutes", config.ExpirationMinutes),
+		zap.Int("Trash retention hours", config.TrashRetentionHours),
+		zap.Int64("Max secrets per user", config.MaxSecretsPerUser),
+		zap.Int64("Max secret size", config.MaxSecretSize),
+		zap.Int64("Max total size per user", config.MaxTotalSize),
+		zap.Int64("Max request size", config.MaxRequestSize),
+		zap.String("TLS certificate file", config.TLSCertFile),
+		zap.String("TLS client CA file", config.TLSClientCAFile),
+		zap.Bool("TLS client certificate required", config.TLSRequireClientCert),
+		zap.Int("Shutdown delay seconds", config.ShutdownDelaySeconds),
+		zap.Int("Shutdown timeout seconds", config.ShutdownTimeoutSeconds))
 
 	router := chi.NewRouter()
 	router.Use(middleware.Recoverer)
@@ -42,18 +64,33 @@ func main() {
 	router.Use(middleware.Timeout(60 * time.Second))
 	router.Use(customMiddleware.CompressingMiddleware())
 	router.Use(customMiddleware.DecompressingMiddleware())
+	router.Use(customMiddleware.LimitRequestSizeMiddleware(config.MaxRequestSize))
 
 	pool, err := createConnectionPool(context.Background(), config.DatabaseConnString)
 	if err != nil {
 		log.Fatal("Error during initialize DB connection", zap.Error(err))
 	}
-	runMigrations(config.DatabaseConnString, log)
+	migrations, migrationsDB := runMigrations(config.DatabaseConnString, log)
 
 	userRepository := storage.NewUserRepository(pool)
 	userService := user.NewUserService(log, userRepository)
 
 	secretRepository := storage.NewSecretRepository(pool)
-	secretService := secretSrv.NewSecretService(log, secretRepository)
+	secretService := secretSrv.NewSecretService(log, secretRepository).WithQuota(secretSrv.Quota{
+		MaxSecrets:    config.MaxSecretsPerUser,
+		MaxSecretSize: config.MaxSecretSize,
+		MaxTotalSize:  config.MaxTotalSize,
+	})
+
+	workers := lifecycle.NewWorkers()
+	trashRetention := time.Duration(config.TrashRetentionHours) * time.Hour
+	workers.Go(func(ctx context.Context) {
+		job.RunTrashPurge(ctx, log, secretService, trashRetention, time.Hour)
+	})
+
+	readiness := &lifecycle.Readiness{}
+	router.Method(http.MethodGet, "/healthz", health.HealthHandler(log, pool, migrations))
+	router.Method(http.MethodGet, "/readyz", health.ReadinessHandler(log, pool, migrations, readiness))
 
 	router.Method(http.MethodPost, "/api/user/register", auth.RegisterHandler(log, config, userService))
 	router.Method(http.MethodPost, "/api/user/login", auth.LoginHandler(log, config, userService))
@@ -61,12 +98,56 @@ func main() {
 	router.Group(func(r chi.Router) {
 		r.Use(customMiddleware.CheckAuthMiddleware(log, config))
 		r.Method(http.MethodPost, "/api/user/secret", secret.UploadSecretHandler(log, secretService))
-		r.Method(http.MethodGet, "/api/user/secret/{name}", secret.ReadOneSecretHandler(log, secretService))
-		r.Method(http.MethodDelete, "/api/user/secret/{name}", secret.DeleteSecretHandler(log, secretService))
+		r.Method(http.MethodPut, "/api/user/secret", secret.UpsertSecretsHandler(log, secretService))
+		r.Method(http.MethodGet, "/api/user/secret/*", secret.ReadOneSecretHandler(log, secretService))
+		r.Method(http.MethodDelete, "/api/user/secret/*", secret.DeleteSecretHandler(log, secretService))
+		r.Method(http.MethodPost, "/api/user/secret/*", secret.RenameSecretHandler(log, secretService))
+		r.Method(http.MethodPut, "/api/user/secret/*", secret.UpdateSecretHandler(log, secretService))
 		r.Method(http.MethodGet, "/api/user/secret", secret.ReadAllSecretsHandler(log, secretService))
+		r.Method(http.MethodGet, "/api/user/folder", folder.ReadFolderHandler(log, secretService))
+		r.Method(http.MethodGet, "/api/user/folder/*", folder.ReadFolderHandler(log, secretService))
+		r.Method(http.MethodPost, "/api/user/folder/move", folder.MoveFolderHandler(log, secretService))
+		r.Method(http.MethodGet, "/api/user/trash", trash.ReadTrashHandler(log, secretService))
+		r.Method(http.MethodPost, "/api/user/trash/*", trash.RestoreSecretHandler(log, secretService))
+		r.Method(http.MethodDelete, "/api/user/trash/*", trash.PurgeSecretHandler(log, secretService))
+		r.Method(http.MethodGet, "/api/user/usage", account.ReadUsageHandler(log, secretService))
 	})
 
-	http.ListenAndServe(config.ServerAddress, router)
+	httpServer := &http.Server{Addr: config.ServerAddress, Handler: router}
+	serve := (*http.Server).Serve
+	if config.TLSCertFile == "" && config.TLSKeyFile == "" {
+		log.Warn("TLS is not configured, passwords and tokens are sent in cleartext")
+	} else {
+		reloader, err := certificate.NewReloader(log, config.TLSCertFile, config.TLSKeyFile)
+		if err != nil {
+			log.Fatal("Error during load server certificate", zap.Error(err))
+		}
+		httpServer.TLSConfig, err = certificate.NewServerTLSConfig(config, reloader)
+		if err != nil {
+			log.Fatal("Error during configure TLS", zap.Error(err))
+		}
+		workers.Go(func(ctx context.Context) {
+			reloader.Run(ctx, time.Duration(config.TLSReloadSeconds)*time.Second)
+		})
+		serve = func(httpServer *http.Server, listener net.Listener) error {
+			return httpServer.ServeTLS(listener, "", "")
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err = lifecycle.NewServer(log, httpServer, readiness).
+		WithWorkers(workers).
+		WithCloser("migrations database", func() { migrationsDB.Close() }).
+		WithCloser("database pool", pool.Close).
+		WithShutdownDelay(time.Duration(config.ShutdownDelaySeconds)*time.Second).
+		WithShutdownTimeout(time.Duration(config.ShutdownTimeoutSeconds)*time.Second).
+		Run(ctx, serve)
+	if err != nil {
+		log.Fatal("Server was stopped with error", zap.Error(err))
+	}
+	log.Info("Server was stopped")
 }
 
 func createConnectionPool(ctx context.Context, connectionString string) (*pgxpool.Pool, error) {
@@ -83,19 +164,52 @@ func createConnectionPool(ctx context.Context, connectionString string) (*pgxpoo
 	return pool, nil
 }
 
-func runMigrations(connectionString string, log *zap.Logger) {
+// Apply migrations and return their status with connection used to check it. Server keeps running
+// when migrations failed, readiness probe checks schema version until it reaches version of migrations
+func runMigrations(connectionString string, log *zap.Logger) (*health.Migrations, *sql.DB) {
 	databaseConfig, err := pgx.ParseConfig(connectionString)
 	if err != nil {
-		log.Error("Error during parse database URL", zap.Error(err))
-		return
+		log.Fatal("Error during parse database URL", zap.Error(err))
 	}
 	db := stdlib.OpenDB(*databaseConfig)
-	defer db.Close()
 
 	goose.SetDialect("postgres")
+	migrations, err := goose.CollectMigrations("migrations", 0, goose.MaxVersion)
+	if err != nil {
+		log.Error("Error during read database migrations", zap.Error(err))
+		return health.NewMigrations(0, fmt.Errorf("error during read database migrations: %w", err)), db
+	}
+	last, err := migrations.Last()
+	if err != nil {
+		log.Error("Error during read database migrations", zap.Error(err))
+		return health.NewMigrations(0, fmt.Errorf("error during read database migrations: %w", err)), db
+	}
+
+	check := func(ctx context.Context) (int64, error) {
+		version, err := goose.GetDBVersionContext(ctx, db)
+		if err != nil {
+			return 0, fmt.Errorf("error during read database version: %w", err)
+		}
+		if version < last.Version {
+			return version, fmt.Errorf("database version %d is older than version of migrations %d", version, last.Version)
+		}
+		return version, nil
+	}
+
 	if err := goose.Up(db, "migrations"); err != nil {
-		log.Error("Error during run database migrations", zap.Error(err))
+		log.Error("Error during run database migrations, server will not become ready until schema is fixed",
+			zap.Error(err))
+		version, _ := goose.GetDBVersion(db)
+		return health.NewMigrations(version, fmt.Errorf("error during run database migrations: %w", err)).WithCheck(check), db
+	}
+
+	version, err := check(context.Background())
+	if err != nil {
+		log.Error("Error during check database version", zap.Error(err))
+	} else {
+		log.Info("Database migrations were applied", zap.Int64("version", version))
 	}
+	return health.NewMigrations(version, err).WithCheck(check), db
 }
 
 func parseConfig() server.Config {
@@ -0,0 +1,236 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// Actions of agent protocol. Each connection carries one JSON request and one JSON response
+const (
+	agentUnlockAction = "unlock"
+	agentLockAction   = "lock"
+	agentGetAction    = "get"
+	agentTokenAction  = "token"
+	agentStatusAction = "status"
+	agentStopAction   = "stop"
+)
+
+var (
+	errAgentNotRunning = errors.New("agent is not running")
+	errAgentLocked     = errors.New("agent is locked, run gophkeeper agent unlock")
+)
+
+type agentRequest struct {
+	Action string `json:"action"`
+	Key    []byte `json:"key,omitempty"`
+	Token  string `json:"token,omitempty"`
+}
+
+type agentResponse struct {
+	Unlocked bool       `json:"unlocked"`
+	Key      []byte     `json:"key,omitempty"`
+	Token    string     `json:"token,omitempty"`
+	LockAt   *time.Time `json:"lock_at,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Agent holding unlocked encryption key and token in memory. Key is wiped after idle timeout
+type agentServer struct {
+	mu          sync.Mutex
+	key         []byte
+	token       string
+	lastUsed    time.Time
+	idleTimeout time.Duration
+	now         func() time.Time
+}
+
+func newAgentServer(idleTimeout time.Duration) *agentServer {
+	return &agentServer{idleTimeout: idleTimeout, now: time.Now}
+}
+
+// Serve agent requests until stop request or listener close
+func (a *agentServer) serve(listener net.Listener) error {
+	done := make(chan struct{})
+	defer close(done)
+	go a.lockOnIdle(done)
+
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error during accept agent connection: %w", err)
+		}
+
+		go func() {
+			if stop := a.handleConn(conn); stop {
+				listener.Close()
+			}
+		}()
+	}
+}
+
+func (a *agentServer) lockOnIdle(done chan struct{}) {
+	period := time.Second
+	if a.idleTimeout > 0 && a.idleTimeout < period {
+		period = a.idleTimeout
+	}
+	ticker := time.NewTicker(period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-done:
+			a.mu.Lock()
+			a.wipe()
+			a.mu.Unlock()
+			return
+		case <-ticker.C:
+			a.mu.Lock()
+			a.lockIfIdle()
+			a.mu.Unlock()
+		}
+	}
+}
+
+// Handle one request, returns true if agent should be stopped
+func (a *agentServer) handleConn(conn net.Conn) bool {
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	var response agentResponse
+	var request agentRequest
+	if err := checkAgentPeer(conn); err != nil {
+		response.Error = err.Error()
+	} else if err := json.NewDecoder(conn).Decode(&request); err != nil {
+		response.Error = fmt.Sprintf("invalid agent request: %s", err.Error())
+	} else {
+		response = a.handle(request)
+	}
+
+	json.NewEncoder(conn).Encode(response)
+	return request.Action == agentStopAction && response.Error == ""
+}
+
+func (a *agentServer) handle(request agentRequest) agentResponse {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.lockIfIdle()
+
+	switch request.Action {
+	case agentUnlockAction:
+		if len(request.Key) != crypto.KeySize || request.Token == "" {
+			return agentResponse{Error: "key and token are required to unlock agent"}
+		}
+		a.wipe()
+		a.key = request.Key
+		a.token = request.Token
+		a.lastUsed = a.now()
+	case agentLockAction, agentStopAction:
+		a.wipe()
+	case agentGetAction:
+		if a.key == nil {
+			return agentResponse{Error: errAgentLocked.Error()}
+		}
+		a.lastUsed = a.now()
+		return agentResponse{Unlocked: true, Key: append([]byte(nil), a.key...), Token: a.token, LockAt: a.lockAt()}
+	case agentTokenAction:
+		if a.key == nil {
+			return agentResponse{Error: errAgentLocked.Error()}
+		}
+		a.token = request.Token
+	case agentStatusAction:
+	default:
+		return agentResponse{Error: fmt.Sprintf("unknown agent action \"%s\"", request.Action)}
+	}
+
+	return agentResponse{Unlocked: a.key != nil, LockAt: a.lockAt()}
+}
+
+func (a *agentServer) lockIfIdle() {
+	if a.key != nil && a.idleTimeout > 0 && a.now().Sub(a.lastUsed) >= a.idleTimeout {
+		a.wipe()
+	}
+}
+
+func (a *agentServer) lockAt() *time.Time {
+	if a.key == nil || a.idleTimeout <= 0 {
+		return nil
+	}
+	lockAt := a.lastUsed.Add(a.idleTimeout)
+	return &lockAt
+}
+
+// Overwrite key in memory before dropping it
+func (a *agentServer) wipe() {
+	for i := range a.key {
+		a.key[i] = 0
+	}
+	a.key = nil
+	a.token = ""
+}
+
+// Send request to agent
+func callAgent(socket string, request agentRequest) (agentResponse, error) {
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return agentResponse{}, errAgentNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err := json.NewEncoder(conn).Encode(request); err != nil {
+		return agentResponse{}, fmt.Errorf("error during send agent request: %w", err)
+	}
+	var response agentResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return agentResponse{}, fmt.Errorf("error during read agent response: %w", err)
+	}
+
+	if response.Error == errAgentLocked.Error() {
+		return response, errAgentLocked
+	}
+	if response.Error != "" {
+		return response, errors.New(response.Error)
+	}
+	return response, nil
+}
+
+// Fill config with key and token of running agent. Config is not changed when agent is not running
+func withAgent(config Config) (Config, error) {
+	if config.AgentSocket == "" {
+		return config, nil
+	}
+	if _, err := os.Stat(config.AgentSocket); err != nil {
+		return config, nil
+	}
+
+	response, err := callAgent(config.AgentSocket, agentRequest{Action: agentGetAction})
+	if errors.Is(err, errAgentNotRunning) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+
+	config.EncryptionKey = string(response.Key)
+	config.token = response.Token
+	return config, nil
+}
+
+// Agent management, authentication and password generation work without unlocked agent
+func needsAgent(command Command) bool {
+	switch command.(type) {
+	case *AgentCommand, *UserLoginCommand, *UserRegisterCommand, *GenerateCommand, *ConfigCommand:
+		return false
+	default:
+		return true
+	}
+}
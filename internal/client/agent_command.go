@@ -0,0 +1,242 @@
+package client
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/golang-jwt/jwt/v4"
+	"golang.org/x/term"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+const (
+	AgentStartOperation  = "start"
+	AgentServeOperation  = "serve"
+	AgentUnlockOperation = "unlock"
+	AgentLockOperation   = "lock"
+	AgentStatusOperation = "status"
+	AgentStopOperation   = "stop"
+)
+
+// Command to manage agent holding unlocked key and token
+type AgentCommand struct {
+	operation   string
+	idleTimeout time.Duration
+}
+
+func NewAgentCommand(operation string, args map[string]string) (*AgentCommand, error) {
+	idleTimeout := 15 * time.Minute
+	if value := args["idle-timeout"]; value != "" {
+		parsedTimeout, err := parseDuration(value)
+		if err != nil || parsedTimeout < 0 {
+			return nil, errors.New("idle-timeout should be a duration, e.g. 15m")
+		}
+		idleTimeout = parsedTimeout
+	}
+
+	return &AgentCommand{operation: operation, idleTimeout: idleTimeout}, nil
+}
+
+func (cmd *AgentCommand) Execute(config Config) error {
+	if config.AgentSocket == "" {
+		return errors.New("agent socket is not configured")
+	}
+
+	switch cmd.operation {
+	case AgentStartOperation:
+		return cmd.start(config)
+	case AgentServeOperation:
+		return cmd.serve(config)
+	case AgentUnlockOperation:
+		return cmd.unlock(config)
+	case AgentLockOperation:
+		if _, err := callAgent(config.AgentSocket, agentRequest{Action: agentLockAction}); err != nil {
+			return err
+		}
+		fmt.Println("Agent is locked")
+		return nil
+	case AgentStatusOperation:
+		response, err := callAgent(config.AgentSocket, agentRequest{Action: agentStatusAction})
+		if errors.Is(err, errAgentNotRunning) {
+			return printOutput(config, agentStatus{}, func(w io.Writer) error {
+				_, err := fmt.Fprintln(w, "Agent is not running")
+				return err
+			})
+		}
+		if err != nil {
+			return err
+		}
+		return printAgentStatus(config, response)
+	case AgentStopOperation:
+		if _, err := callAgent(config.AgentSocket, agentRequest{Action: agentStopAction}); err != nil {
+			return err
+		}
+		fmt.Println("Agent was stopped")
+		return nil
+	default:
+		return fmt.Errorf("unknown agent operation \"%s\"", cmd.operation)
+	}
+}
+
+// Run agent in background process
+func (cmd *AgentCommand) start(config Config) error {
+	if _, err := callAgent(config.AgentSocket, agentRequest{Action: agentStatusAction}); err == nil {
+		return fmt.Errorf("agent is already running on %s", config.AgentSocket)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		return fmt.Errorf("can`t find client executable: %w", err)
+	}
+	process := exec.Command(executable, "agent", AgentServeOperation, "--idle-timeout", cmd.idleTimeout.String())
+	process.Env = append(os.Environ(), "GOPHKEEPER_AGENT_SOCKET="+config.AgentSocket)
+	detachProcess(process)
+	if err := process.Start(); err != nil {
+		return fmt.Errorf("can`t start agent: %w", err)
+	}
+	pid := process.Process.Pid
+	process.Process.Release()
+
+	for i := 0; i < 30; i++ {
+		if _, err := callAgent(config.AgentSocket, agentRequest{Action: agentStatusAction}); err == nil {
+			fmt.Printf("Agent was started with pid %d on %s\n", pid, config.AgentSocket)
+			fmt.Println("Run gophkeeper agent unlock to unlock it")
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return errors.New("agent was started but does not respond")
+}
+
+// Run agent in current process until it is stopped
+func (cmd *AgentCommand) serve(config Config) error {
+	listener, err := listenUnixSocket(config.AgentSocket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(config.AgentSocket)
+
+	return newAgentServer(cmd.idleTimeout).serve(listener)
+}
+
+// Derive key from master password and pass it to agent with current token
+func (cmd *AgentCommand) unlock(config Config) error {
+	token, err := readTokenFromFile(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data, login first: %w", err)
+	}
+	username, err := tokenUsername(token)
+	if err != nil {
+		return err
+	}
+
+	password, err := readMasterPassword()
+	if err != nil {
+		return err
+	}
+	key := crypto.DeriveKey(password, masterKeySalt(username))
+	created, err := checkKeyVerifier(keyVerifierFilePath(config), username, key)
+	if err != nil {
+		return err
+	}
+	if created {
+		fmt.Fprintln(os.Stderr, "Master password was saved for verification on next unlocks")
+	}
+
+	response, err := callAgent(config.AgentSocket, agentRequest{Action: agentUnlockAction, Key: key, Token: token})
+	if err != nil {
+		return err
+	}
+	return printAgentStatus(config, response)
+}
+
+// Salt of key derived from master password, unique per user
+func masterKeySalt(username string) []byte {
+	return []byte("gophkeeper:" + username)
+}
+
+// Read user name from token claims, token signature is checked by server only
+func tokenUsername(token string) (string, error) {
+	claims := &model.Claims{}
+	if _, _, err := jwt.NewParser().ParseUnverified(token, claims); err != nil {
+		return "", fmt.Errorf("can`t parse auth token: %w", err)
+	}
+	if claims.Username == "" {
+		return "", errors.New("auth token has no user name")
+	}
+	return claims.Username, nil
+}
+
+// Read master password without echo from terminal, or one line from piped stdin
+func readMasterPassword() ([]byte, error) {
+	var password []byte
+	if term.IsTerminal(int(os.Stdin.Fd())) {
+		fmt.Fprint(os.Stderr, "Master password: ")
+		value, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return nil, fmt.Errorf("error during read master password: %w", err)
+		}
+		password = value
+	} else {
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return nil, fmt.Errorf("error during read master password: %w", err)
+		}
+		password = []byte(strings.TrimRight(line, "\r\n"))
+	}
+
+	if len(password) == 0 {
+		return nil, errors.New("master password is required")
+	}
+	return password, nil
+}
+
+// State of agent, key and token are never printed
+type agentStatus struct {
+	Running  bool       `json:"running"`
+	Unlocked bool       `json:"unlocked"`
+	LockAt   *time.Time `json:"lock_at,omitempty"`
+}
+
+func printAgentStatus(config Config, response agentResponse) error {
+	status := agentStatus{Running: true, Unlocked: response.Unlocked, LockAt: response.LockAt}
+	return printOutput(config, status, func(w io.Writer) error {
+		if !response.Unlocked {
+			_, err := fmt.Fprintln(w, "Agent is locked")
+			return err
+		}
+		fmt.Fprintln(w, "Agent is unlocked")
+		if response.LockAt != nil {
+			fmt.Fprintf(w, "Lock at: %s\n", response.LockAt.Local().Format(time.DateTime))
+		}
+		return nil
+	})
+}
+
+// Pass new token to unlocked agent after login, agent which is not running or locked is ignored
+func updateAgentToken(config Config, token string) error {
+	if config.AgentSocket == "" {
+		return nil
+	}
+	_, err := callAgent(config.AgentSocket, agentRequest{Action: agentTokenAction, Token: token})
+	if errors.Is(err, errAgentNotRunning) || errors.Is(err, errAgentLocked) {
+		return nil
+	}
+	return err
+}
+
+// Fabric to create agent command for one operation
+type AgentCommandFactory struct {
+	Operation string
+}
+
+func (f *AgentCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewAgentCommand(f.Operation, args)
+}
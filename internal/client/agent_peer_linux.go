@@ -0,0 +1,40 @@
+//go:build linux
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"syscall"
+)
+
+// Check that agent is called by process of the same user
+func checkAgentPeer(conn net.Conn) error {
+	unixConn, ok := conn.(*net.UnixConn)
+	if !ok {
+		return errors.New("agent accepts unix socket connections only")
+	}
+	rawConn, err := unixConn.SyscallConn()
+	if err != nil {
+		return fmt.Errorf("can`t check agent peer: %w", err)
+	}
+
+	var credentials *syscall.Ucred
+	var credentialsErr error
+	err = rawConn.Control(func(fd uintptr) {
+		credentials, credentialsErr = syscall.GetsockoptUcred(int(fd), syscall.SOL_SOCKET, syscall.SO_PEERCRED)
+	})
+	if err != nil {
+		return fmt.Errorf("can`t check agent peer: %w", err)
+	}
+	if credentialsErr != nil {
+		return fmt.Errorf("can`t check agent peer: %w", credentialsErr)
+	}
+
+	if int(credentials.Uid) != os.Getuid() {
+		return fmt.Errorf("agent peer with uid %d is not allowed", credentials.Uid)
+	}
+	return nil
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package client
+
+import "net"
+
+// Peer credentials are not checked, access is restricted by socket file permissions only
+func checkAgentPeer(conn net.Conn) error {
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package client
+
+import "os/exec"
+
+// Process is started as is, terminal sessions are not supported
+func detachProcess(cmd *exec.Cmd) {}
@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+)
+
+// Client of server API working with domain models
+type apiClient struct {
+	client *gophkeeperclient.Client
+}
+
+// Create client of server API with TLS settings of profile, authorized with user token
+func newAPIClient(config Config, token string) *apiClient {
+	client := gophkeeperclient.New(config.ServerAddress).WithToken(token)
+	if config.tlsConfig != nil {
+		client.WithTLSConfig(config.tlsConfig)
+	}
+	return &apiClient{client: client}
+}
+
+func (a *apiClient) Register(ctx context.Context, user model.User) (string, error) {
+	return a.client.Register(ctx, gophkeeperclient.User(user))
+}
+
+func (a *apiClient) Login(ctx context.Context, user model.User) (string, error) {
+	return a.client.Login(ctx, gophkeeperclient.User(user))
+}
+
+func (a *apiClient) CreateSecret(ctx context.Context, secret model.Secret) error {
+	return a.client.CreateSecret(ctx, toAPISecret(secret))
+}
+
+func (a *apiClient) UpdateSecret(ctx context.Context, secret model.Secret) error {
+	return a.client.UpdateSecret(ctx, toAPISecret(secret))
+}
+
+func (a *apiClient) UpsertSecrets(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+	batch := make([]gophkeeperclient.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		batch = append(batch, toAPISecret(secret))
+	}
+	result, err := a.client.UpsertSecrets(ctx, batch)
+	return model.SecretBatchResult(result), err
+}
+
+func (a *apiClient) FindSecret(ctx context.Context, name string) (model.Secret, error) {
+	secret, err := a.client.FindSecret(ctx, name)
+	return fromAPISecret(secret), err
+}
+
+func (a *apiClient) FindSecrets(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+	page, err := a.client.FindSecrets(ctx, gophkeeperclient.SecretFilter(filter))
+	return model.SecretPage{Secrets: fromAPISecrets(page.Secrets), NextCursor: page.NextCursor}, err
+}
+
+func (a *apiClient) FindAllSecrets(ctx context.Context, filter model.SecretFilter) ([]model.Secret, error) {
+	secrets, err := a.client.FindAllSecrets(ctx, gophkeeperclient.SecretFilter(filter))
+	return fromAPISecrets(secrets), err
+}
+
+func (a *apiClient) DeleteSecret(ctx context.Context, name string) error {
+	return a.client.DeleteSecret(ctx, name)
+}
+
+func (a *apiClient) RenameSecret(ctx context.Context, from string, to string) error {
+	return a.client.RenameSecret(ctx, from, to)
+}
+
+func (a *apiClient) ListFolder(ctx context.Context, path string) (model.Folder, error) {
+	folder, err := a.client.ListFolder(ctx, path)
+	return model.Folder{Path: folder.Path, Folders: folder.Folders, Secrets: fromAPISecrets(folder.Secrets)}, err
+}
+
+func (a *apiClient) MoveFolder(ctx context.Context, from string, to string) error {
+	return a.client.MoveFolder(ctx, from, to)
+}
+
+func (a *apiClient) FindTrash(ctx context.Context) ([]model.Secret, error) {
+	secrets, err := a.client.FindTrash(ctx)
+	return fromAPISecrets(secrets), err
+}
+
+func (a *apiClient) RestoreSecret(ctx context.Context, name string) error {
+	return a.client.RestoreSecret(ctx, name)
+}
+
+func (a *apiClient) PurgeSecret(ctx context.Context, name string) error {
+	return a.client.PurgeSecret(ctx, name)
+}
+
+func (a *apiClient) FindUsage(ctx context.Context) (model.Usage, error) {
+	usage, err := a.client.FindUsage(ctx)
+	return model.Usage(usage), err
+}
+
+func toAPISecret(secret model.Secret) gophkeeperclient.Secret {
+	return gophkeeperclient.Secret{
+		Name:             secret.Name,
+		Content:          secret.Content,
+		Type:             secret.Type,
+		Version:          secret.Version,
+		Notes:            secret.Notes,
+		Tags:             secret.Tags,
+		URL:              secret.URL,
+		ExpiresAt:        secret.ExpiresAt,
+		RotateAfter:      secret.RotateAfter,
+		BurnAfterReading: secret.BurnAfterReading,
+		CreatedAt:        secret.CreatedAt,
+		UpdatedAt:        secret.UpdatedAt,
+		LastAccessedAt:   secret.LastAccessedAt,
+		DeletedAt:        secret.DeletedAt,
+	}
+}
+
+func fromAPISecret(secret gophkeeperclient.Secret) model.Secret {
+	return model.Secret{
+		Name:             secret.Name,
+		Content:          secret.Content,
+		Type:             secret.Type,
+		Version:          secret.Version,
+		Notes:            secret.Notes,
+		Tags:             secret.Tags,
+		URL:              secret.URL,
+		ExpiresAt:        secret.ExpiresAt,
+		RotateAfter:      secret.RotateAfter,
+		BurnAfterReading: secret.BurnAfterReading,
+		CreatedAt:        secret.CreatedAt,
+		UpdatedAt:        secret.UpdatedAt,
+		LastAccessedAt:   secret.LastAccessedAt,
+		DeletedAt:        secret.DeletedAt,
+	}
+}
+
+func fromAPISecrets(secrets []gophkeeperclient.Secret) []model.Secret {
+	if secrets == nil {
+		return nil
+	}
+	result := make([]model.Secret, 0, len(secrets))
+	for _, secret := range secrets {
+		result = append(result, fromAPISecret(secret))
+	}
+	return result
+}
+
+// Read names of all user secrets
+func findSecretNames(ctx context.Context, api *apiClient) (map[string]bool, error) {
+	secrets, err := api.FindAllSecrets(ctx, model.SecretFilter{})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]bool, len(secrets))
+	for _, secret := range secrets {
+		names[secret.Name] = true
+	}
+	return names, nil
+}
+
+// Secret was changed by another client or removed since it was read
+var errSecretChanged = errors.New("secret was changed or removed since it was read")
+
+// Save secret read with its version, concurrent change or removal of secret is reported instead of overwritten
+func updateSecret(ctx context.Context, api *apiClient, secret model.Secret) error {
+	err := api.UpdateSecret(ctx, secret)
+	if errors.Is(err, gophkeeperclient.ErrConflict) || errors.Is(err, gophkeeperclient.ErrNotFound) {
+		return fmt.Errorf("%w: %s", errSecretChanged, secret.Name)
+	}
+	return err
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Server API holding secrets in memory. Like real server it rejects update with stale version
+// and deletes burn after reading secret on read
+type fakeSecretServer struct {
+	mu      sync.Mutex
+	secrets map[string]model.Secret
+	creates int
+	updates int
+	upserts int
+	// called before update is applied, returns status code to respond with instead
+	beforeUpdate func(server *fakeSecretServer) int
+}
+
+// Start fake server with secrets, returned config uses it with test token
+func newFakeSecretServer(t *testing.T, secrets ...model.Secret) (*fakeSecretServer, Config) {
+	fake := &fakeSecretServer{secrets: make(map[string]model.Secret)}
+	for _, secret := range secrets {
+		fake.secrets[secret.Name] = secret
+	}
+	testServer := httptest.NewServer(fake)
+	t.Cleanup(testServer.Close)
+	return fake, Config{ServerAddress: testServer.URL, token: "token"}
+}
+
+func (s *fakeSecretServer) secret(name string) (model.Secret, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secret, ok := s.secrets[name]
+	return secret, ok
+}
+
+func (s *fakeSecretServer) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	name := strings.TrimPrefix(strings.TrimPrefix(request.URL.Path, "/api/user/secret"), "/")
+	switch {
+	case request.Method == http.MethodGet && name == "":
+		page := model.SecretPage{Secrets: []model.Secret{}}
+		for _, secret := range s.secrets {
+			page.Secrets = append(page.Secrets, secret)
+		}
+		sort.Slice(page.Secrets, func(i, j int) bool { return page.Secrets[i].Name < page.Secrets[j].Name })
+		json.NewEncoder(writer).Encode(page)
+	case request.Method == http.MethodGet:
+		secret, ok := s.secrets[name]
+		if !ok {
+			http.NotFound(writer, request)
+			return
+		}
+		if secret.BurnAfterReading {
+			delete(s.secrets, name)
+		}
+		json.NewEncoder(writer).Encode(secret)
+	case request.Method == http.MethodPost && name == "":
+		s.creates++
+		var secret model.Secret
+		json.NewDecoder(request.Body).Decode(&secret)
+		if _, ok := s.secrets[secret.Name]; ok {
+			http.Error(writer, "Secret already exists to current user", http.StatusConflict)
+			return
+		}
+		s.secrets[secret.Name] = secret
+	case request.Method == http.MethodPut && name == "":
+		s.upserts++
+		var secrets []model.Secret
+		json.NewDecoder(request.Body).Decode(&secrets)
+		for _, secret := range secrets {
+			s.secrets[secret.Name] = secret
+		}
+		json.NewEncoder(writer).Encode(model.SecretBatchResult{Updated: len(secrets)})
+	case request.Method == http.MethodPut:
+		s.updates++
+		if s.beforeUpdate != nil {
+			if status := s.beforeUpdate(s); status != 0 {
+				writer.WriteHeader(status)
+				return
+			}
+		}
+		var secret model.Secret
+		json.NewDecoder(request.Body).Decode(&secret)
+		stored, ok := s.secrets[name]
+		if !ok {
+			http.NotFound(writer, request)
+			return
+		}
+		if secret.Version != stored.Version {
+			http.Error(writer, "Secret was changed by another request", http.StatusConflict)
+			return
+		}
+		secret.Name = name
+		secret.Version++
+		s.secrets[name] = secret
+	default:
+		http.NotFound(writer, request)
+	}
+}
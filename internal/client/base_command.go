@@ -4,8 +4,13 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
+	"strconv"
+	"strings"
 )
 
+// Separator of repeated flag values in command arguments
+const repeatedFlagSeparator = "\n"
+
 // Base interface to command
 type Command interface {
 	Execute(config Config) error
@@ -16,11 +21,23 @@ type CommandFactory interface {
 	Create(args map[string]string) (Command, error)
 }
 
+// Factory to create command with arguments and positional arguments, e.g. child command after "--"
+type PositionalCommandFactory interface {
+	CommandFactory
+	CreateWithArgs(flags map[string]string, args []string) (Command, error)
+}
+
 // Flag definition
 type FlagDef struct {
 	Name         string
 	DefaultValue string
 	Description  string
+	// One letter flag alias, optional
+	Shorthand string
+	// Flag can be passed several times
+	Repeated bool
+	// Boolean flag, passing it without value means true, e.g. --reveal
+	Bool bool
 }
 
 // Command registry
@@ -48,20 +65,68 @@ func (cr *CommandRegistry) Register(name string, factory CommandFactory, flags [
 		RunE: func(cmd *cobra.Command, args []string) error {
 			flags := make(map[string]string)
 			cmd.Flags().VisitAll(func(f *pflag.Flag) {
+				if sliceValue, ok := f.Value.(pflag.SliceValue); ok {
+					flags[f.Name] = strings.Join(sliceValue.GetSlice(), repeatedFlagSeparator)
+					return
+				}
 				flags[f.Name] = f.Value.String()
 			})
 
-			command, err := factory.Create(flags)
+			var command Command
+			var err error
+			if positionalFactory, ok := factory.(PositionalCommandFactory); ok {
+				command, err = positionalFactory.CreateWithArgs(flags, args)
+			} else {
+				command, err = factory.Create(flags)
+			}
 			if err != nil {
 				return err
 			}
-			return command.Execute(cr.config)
+
+			config := cr.config
+			if err := checkProfile(config, command); err != nil {
+				return err
+			}
+			if needsAgent(command) {
+				if config, err = withAgent(config); err != nil {
+					return err
+				}
+			}
+			return command.Execute(config)
 		},
 	}
 
 	for _, flag := range flags {
-		cmd.Flags().String(flag.Name, flag.DefaultValue, flag.Description)
+		if flag.Repeated {
+			cmd.Flags().StringArrayP(flag.Name, flag.Shorthand, nil, flag.Description)
+			continue
+		}
+		cmd.Flags().StringP(flag.Name, flag.Shorthand, flag.DefaultValue, flag.Description)
+		if flag.Bool {
+			cmd.Flags().Lookup(flag.Name).NoOptDefVal = "true"
+		}
 	}
 
 	cr.rootCmd.AddCommand(cmd)
 }
+
+// Split value of repeated flag
+func splitRepeated(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, repeatedFlagSeparator)
+}
+
+// Parse boolean flag, empty value means false
+func parseBoolFlag(args map[string]string, name string) (bool, error) {
+	value := args[name]
+	if value == "" {
+		return false, nil
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("%s should be true or false", name)
+	}
+	return parsed, nil
+}
@@ -57,3 +57,78 @@ func TestCommandRegistry_RegisterAndExecute(t *testing.T) {
 
 	assert.True(t, factory.executed, "Command was not executed")
 }
+
+func TestCommandRegistry_RepeatedFlag(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "testapp"}
+	registry := NewCommandRegistry(Config{}, rootCmd)
+
+	factory := &testCommandFactory{expectedFlags: map[string]string{
+		"tag": "first" + repeatedFlagSeparator + "second,third",
+	}}
+	registry.Register("test", factory, []FlagDef{
+		{Name: "tag", Description: "Test repeated flag", Repeated: true},
+	})
+
+	rootCmd.SetArgs([]string{"test", "--tag=first", "--tag=second,third"})
+
+	err := rootCmd.Execute()
+	assert.NoError(t, err)
+	assert.True(t, factory.executed, "Command was not executed")
+}
+
+func TestCommandRegistry_BoolFlag(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		expected string
+	}{
+		{name: "Without value", args: []string{"test", "--reveal", "--name=card"}, expected: "true"},
+		{name: "With value", args: []string{"test", "--reveal=false", "--name=card"}, expected: "false"},
+		{name: "Not passed", args: []string{"test", "--name=card"}, expected: "false"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := &cobra.Command{Use: "testapp"}
+			registry := NewCommandRegistry(Config{}, rootCmd)
+			factory := &testCommandFactory{expectedFlags: map[string]string{"reveal": tc.expected, "name": "card"}}
+			registry.Register("test", factory, []FlagDef{
+				{Name: "reveal", DefaultValue: "false", Description: "Test bool flag", Bool: true},
+				{Name: "name", Description: "Test flag"},
+			})
+
+			rootCmd.SetArgs(tc.args)
+			assert.NoError(t, rootCmd.Execute())
+			assert.True(t, factory.executed, "Command was not executed")
+		})
+	}
+}
+
+type testPositionalCommandFactory struct {
+	testCommandFactory
+	args []string
+}
+
+func (f *testPositionalCommandFactory) CreateWithArgs(flags map[string]string, args []string) (Command, error) {
+	f.args = args
+	return f.Create(flags)
+}
+
+func TestCommandRegistry_PositionalArgs(t *testing.T) {
+	rootCmd := &cobra.Command{Use: "testapp"}
+	registry := NewCommandRegistry(Config{}, rootCmd)
+
+	factory := &testPositionalCommandFactory{testCommandFactory: testCommandFactory{expectedFlags: map[string]string{
+		"env": "A=a",
+	}}}
+	registry.Register("test", factory, []FlagDef{
+		{Name: "env", Description: "Test repeated flag", Repeated: true},
+	})
+
+	rootCmd.SetArgs([]string{"test", "--env=A=a", "--", "./app", "--env=B"})
+
+	err := rootCmd.Execute()
+	assert.NoError(t, err)
+	assert.True(t, factory.executed, "Command was not executed")
+	assert.Equal(t, []string{"./app", "--env=B"}, factory.args)
+}
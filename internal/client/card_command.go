@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/card"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Validate card requisites and return them with normalized number and date
+func validateCard(requisites model.Card) (model.Card, error) {
+	brand, err := card.ValidateNumber(requisites.Number)
+	if err != nil {
+		return model.Card{}, err
+	}
+	expiry, err := card.ParseExpiry(requisites.Date)
+	if err != nil {
+		return model.Card{}, err
+	}
+	if err := card.ValidateCode(brand, requisites.Code); err != nil {
+		return model.Card{}, err
+	}
+
+	return model.Card{
+		Number: card.Normalize(requisites.Number),
+		Date:   expiry.String(),
+		Code:   strings.TrimSpace(requisites.Code),
+		Holder: strings.TrimSpace(requisites.Holder),
+	}, nil
+}
+
+// Command to list cards which are expired or expire soon
+type ExpiredCardsCommand struct {
+	within time.Duration
+}
+
+func NewExpiredCardsCommand(args map[string]string) (*ExpiredCardsCommand, error) {
+	within := time.Duration(0)
+	if value := args["within"]; value != "" {
+		parsedWithin, err := parseDuration(value)
+		if err != nil || parsedWithin < 0 {
+			return nil, errors.New("within should be a positive duration, e.g. 60d")
+		}
+		within = parsedWithin
+	}
+
+	return &ExpiredCardsCommand{within: within}, nil
+}
+
+func (cmd *ExpiredCardsCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	secrets, err := newAPIClient(config, token).FindAllSecrets(context.Background(), model.SecretFilter{Type: model.CardSecretType})
+	if err != nil {
+		return fmt.Errorf("can`t read cards. Reason: %w", err)
+	}
+
+	cards, err := findExpiredCards(secrets, []byte(config.EncryptionKey), time.Now(), cmd.within)
+	if err != nil {
+		return err
+	}
+
+	return printOutput(config, cards, func(w io.Writer) error {
+		if len(cards) == 0 {
+			_, err := fmt.Fprintln(w, "There are no expired cards")
+			return err
+		}
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, item := range cards {
+			fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\n", item.Name, item.Brand, item.Number, item.Date, item.Status)
+		}
+		return writer.Flush()
+	})
+}
+
+// Card of expired cards report, number is masked
+type expiredCard struct {
+	Name   string `json:"name"`
+	Brand  string `json:"brand"`
+	Number string `json:"number"`
+	Date   string `json:"date"`
+	Status string `json:"status"`
+}
+
+// Find cards expired before now or expiring within duration, cards with invalid date are reported too
+func findExpiredCards(secrets []model.Secret, key []byte, now time.Time, within time.Duration) ([]expiredCard, error) {
+	cards := make([]expiredCard, 0)
+	for _, secret := range secrets {
+		// content of one-time secrets is not listed
+		if secret.Type != model.CardSecretType || len(secret.Content) == 0 {
+			continue
+		}
+		_, fields, _, err := decodeContentFields(secret, key, now)
+		if err != nil {
+			return nil, fmt.Errorf("secret %s: %w", secret.Name, err)
+		}
+
+		status := ""
+		expiry, err := card.ParseExpiry(fields["date"])
+		switch {
+		case err != nil:
+			status = "invalid date"
+		case expiry.Expired(now):
+			status = "expired"
+		case expiry.Expired(now.Add(within)):
+			status = "expires soon"
+		default:
+			continue
+		}
+
+		cards = append(cards, expiredCard{
+			Name:   secret.Name,
+			Brand:  fields["brand"],
+			Number: card.Mask(fields["number"]),
+			Date:   fields["date"],
+			Status: status,
+		})
+	}
+	return cards, nil
+}
+
+// Fabric to create expired cards report command
+type ExpiredCardsCommandFactory struct{}
+
+func (f *ExpiredCardsCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewExpiredCardsCommand(args)
+}
@@ -0,0 +1,94 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/card"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestValidateCard(t *testing.T) {
+	_, err := validateCard(model.Card{Number: "4242 4242 4242 4242", Date: "3/2030", Code: "123", Holder: " IVAN "})
+	assert.Error(t, err)
+
+	requisites, err := validateCard(model.Card{Number: "4242 4242 4242 4242", Date: "03/2030", Code: "123", Holder: " IVAN "})
+	require.NoError(t, err)
+	assert.Equal(t, model.Card{Number: "4242424242424242", Date: "03/30", Code: "123", Holder: "IVAN"}, requisites)
+
+	_, err = validateCard(model.Card{Number: "4242424242424241", Date: "03/30", Code: "123"})
+	assert.Error(t, err)
+	_, err = validateCard(model.Card{Number: "378282246310005", Date: "03/30", Code: "123"})
+	assert.Error(t, err)
+
+	_, err = NewSaveCardCommand(map[string]string{"name": "card", "number": "1234", "date": "03/30", "code": "123", "holder": "IVAN"})
+	assert.Error(t, err)
+}
+
+func TestFindExpiredCards(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	newCard := func(name string, date string) model.Secret {
+		content, err := json.Marshal(model.Card{Number: "5555555555554444", Date: date, Code: "123", Holder: "IVAN"})
+		require.NoError(t, err)
+		encrypted, err := crypto.EncryptData(content, key)
+		require.NoError(t, err)
+		return model.Secret{Name: name, Type: model.CardSecretType, Content: encrypted}
+	}
+	now := time.Date(2024, 5, 15, 12, 0, 0, 0, time.UTC)
+	secrets := []model.Secret{
+		newCard("expired", "04/24"),
+		newCard("current", "05/24"),
+		newCard("later", "12/24"),
+		newCard("broken", "2024-05"),
+		{Name: "one-time", Type: model.CardSecretType},
+	}
+
+	cards, err := findExpiredCards(secrets, key, now, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []expiredCard{
+		{Name: "expired", Brand: card.MastercardBrand, Number: "**** 4444", Date: "04/24", Status: "expired"},
+		{Name: "broken", Brand: card.MastercardBrand, Number: "**** 4444", Date: "2024-05", Status: "invalid date"},
+	}, cards)
+
+	cards, err = findExpiredCards(secrets, key, now, 30*24*time.Hour)
+	require.NoError(t, err)
+	require.Len(t, cards, 3)
+	assert.Equal(t, "current", cards[1].Name)
+	assert.Equal(t, "expires soon", cards[1].Status)
+}
+
+func TestMaskSecretFields(t *testing.T) {
+	values := map[string]string{"number": "4242424242424242", "code": "123", "holder": "IVAN"}
+	masked := maskSecretFields(model.CardSecretType, values)
+	assert.Equal(t, map[string]string{"number": "**** 4242", "code": "***", "holder": "IVAN"}, masked)
+	assert.Equal(t, "4242424242424242", values["number"])
+
+	credentials := map[string]string{"username": "admin", "password": "secret"}
+	assert.Equal(t, credentials, maskSecretFields(model.CredentialsSecretType, credentials))
+}
+
+func TestMaskSecretField(t *testing.T) {
+	masked, err := maskSecretField(model.CardSecretType, "number", "4242424242424242")
+	require.NoError(t, err)
+	assert.Equal(t, "**** 4242", masked)
+
+	masked, err = maskSecretField(model.CardSecretType, "code", "123")
+	require.NoError(t, err)
+	assert.Equal(t, "***", masked)
+
+	masked, err = maskSecretField(model.CardSecretType, "holder", "IVAN")
+	require.NoError(t, err)
+	assert.Equal(t, "IVAN", masked)
+
+	_, err = maskSecretField(model.CardSecretType, "value", `{"number":"4242424242424242"}`)
+	assert.Error(t, err)
+	_, err = maskSecretField(model.CardSecretType, "", `{"number":"4242424242424242"}`)
+	assert.Error(t, err)
+
+	masked, err = maskSecretField(model.CredentialsSecretType, "", "secret")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", masked)
+}
@@ -1,31 +1,183 @@
 package client
 
 import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
 )
 
+const (
+	defaultServerAddress     = "http://localhost:8080"
+	defaultEncryptionKey     = "WYJcWgkItShq513L21E1CFuz6uQWDy3p"
+	defaultCredentialsFolder = "credentials"
+)
+
+// Default agent socket in user runtime dir, each profile has own agent
+func defaultAgentSocketPath(profileName string) string {
+	if profileName == defaultProfileName {
+		return runtimeSocketPath("agent")
+	}
+	return runtimeSocketPath("agent-" + profileName)
+}
+
 type Config struct {
 	ServerAddress string
 	EncryptionKey string
+	// Folder with CREDENTIALS secrets used by git and docker credential helpers
+	CredentialsFolder string
+	// Unix socket of agent holding unlocked key and token
+	AgentSocket string
+	// Configuration file with profiles
+	ConfigFile string
+	// Selected profile
+	Profile string
+	// User name used by login when it is not passed
+	Username string
+	// PEM file with CA certificates of server
+	CAFile string
+	// Skip verification of server certificate
+	InsecureSkipVerify bool
+	// PEM files with client certificate and key for mutual TLS
+	ClientCertFile string
+	ClientKeyFile  string
+	// Base64 SHA-256 hashes of server public keys, server should present one of them
+	PinnedKeys []string
+	// File with auth token of profile
+	TokenFile string
+	// Output format of command results: text, json, yaml or env
+	Output string
+	// Token received from agent
+	token string
+	// Profile exists in configuration file
+	profileFound bool
+	// TLS settings of server API client, nil for defaults
+	tlsConfig *tls.Config
 }
 
-func ParseConfig() Config {
-	defaultAddress := "http://localhost:8080"
-	if envAddr, exists := os.LookupEnv("ADDRESS"); exists {
-		defaultAddress = envAddr
+// Parse configuration. Values are taken from flags, then environment, then selected profile of configuration file
+func ParseConfig() (Config, error) {
+	address := flag.String("a", "", "Server address")
+	key := flag.String("k", "", "Encryption key")
+	credentialsFolder := flag.String("credentials-folder", "", "Folder with credentials of git and docker helpers")
+	agentSocket := flag.String("agent-socket", "", "Unix socket of agent")
+	configPath := flag.String("config", "", "Client configuration file")
+	profileName := flag.String("profile", "", "Configuration profile")
+	output := flag.String("output", "", "Output format: text, json, yaml or env")
+	flag.Parse()
+
+	config := Config{ConfigFile: firstNonEmpty(*configPath, os.Getenv("GOPHKEEPER_CONFIG"), defaultConfigFilePath())}
+	file, err := loadConfigFile(config.ConfigFile)
+	if err != nil {
+		return Config{}, err
 	}
-	address := flag.String("a", defaultAddress, "Server address")
 
-	defaultEncryptionKey := "WYJcWgkItShq513L21E1CFuz6uQWDy3p"
-	if envEncryptionKey, exists := os.LookupEnv("ENCRYPTION_KEY"); exists {
-		defaultEncryptionKey = envEncryptionKey
+	config.Profile = firstNonEmpty(*profileName, os.Getenv("GOPHKEEPER_PROFILE"), file.CurrentProfile, defaultProfileName)
+	settings, found := file.Profiles[config.Profile]
+	config.profileFound = found
+
+	config.ServerAddress = firstNonEmpty(*address, os.Getenv("ADDRESS"), settings.Server, defaultServerAddress)
+	config.EncryptionKey = firstNonEmpty(*key, os.Getenv("ENCRYPTION_KEY"), defaultEncryptionKey)
+	config.CredentialsFolder = firstNonEmpty(*credentialsFolder, os.Getenv("CREDENTIALS_FOLDER"), settings.CredentialsFolder, defaultCredentialsFolder)
+	config.AgentSocket = firstNonEmpty(*agentSocket, os.Getenv("GOPHKEEPER_AGENT_SOCKET"), defaultAgentSocketPath(config.Profile))
+	config.Username = settings.Username
+	config.CAFile = settings.CAFile
+	config.InsecureSkipVerify = settings.InsecureSkipVerify
+	config.ClientCertFile = settings.ClientCertFile
+	config.ClientKeyFile = settings.ClientKeyFile
+	config.PinnedKeys, err = parsePins(settings.PinSHA256)
+	if err != nil {
+		return Config{}, err
 	}
-	key := flag.String("k", defaultEncryptionKey, "Encryption key")
+	config.TokenFile = firstNonEmpty(settings.TokenFile, defaultTokenFilePath(config.ConfigFile, config.Profile))
 
-	flag.Parse()
-	return Config{
-		ServerAddress: *address,
-		EncryptionKey: *key,
+	config.Output = firstNonEmpty(*output, os.Getenv("GOPHKEEPER_OUTPUT"), settings.Output, TextOutput)
+	if err := validateOutputFormat(config.Output); err != nil {
+		return Config{}, err
+	}
+
+	config.tlsConfig, err = clientTLSConfig(config)
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// TLS settings of server API client, nil when defaults are used
+func clientTLSConfig(config Config) (*tls.Config, error) {
+	if config.CAFile == "" && !config.InsecureSkipVerify && config.ClientCertFile == "" && len(config.PinnedKeys) == 0 {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, InsecureSkipVerify: config.InsecureSkipVerify}
+	if config.CAFile != "" {
+		data, err := os.ReadFile(config.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("error during read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, errors.New("CA file has no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if config.ClientCertFile != "" || config.ClientKeyFile != "" {
+		certificate, err := tls.LoadX509KeyPair(config.ClientCertFile, config.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("error during load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+	if len(config.PinnedKeys) > 0 {
+		tlsConfig.VerifyConnection = verifyPinnedKeys(config.PinnedKeys)
+	}
+	return tlsConfig, nil
+}
+
+// Parse comma separated base64 SHA-256 hashes of public keys
+func parsePins(value string) ([]string, error) {
+	var pins []string
+	for _, pin := range strings.Split(value, ",") {
+		pin = strings.TrimPrefix(strings.TrimSpace(pin), "sha256/")
+		if pin == "" {
+			continue
+		}
+		hash, err := base64.StdEncoding.DecodeString(pin)
+		if err != nil || len(hash) != sha256.Size {
+			return nil, fmt.Errorf("pin \"%s\" should be base64 SHA-256 hash of public key", pin)
+		}
+		pins = append(pins, pin)
+	}
+	return pins, nil
+}
+
+// Accept connection when certificate chain of server contains one of pinned public keys.
+// Pins are checked in addition to usual verification, which can be disabled for self signed certificates
+func verifyPinnedKeys(pins []string) func(state tls.ConnectionState) error {
+	return func(state tls.ConnectionState) error {
+		for _, certificate := range state.PeerCertificates {
+			hash := sha256.Sum256(certificate.RawSubjectPublicKeyInfo)
+			pin := base64.StdEncoding.EncodeToString(hash[:])
+			for _, pinned := range pins {
+				if pin == pinned {
+					return nil
+				}
+			}
+		}
+		return errors.New("server public key does not match pinned keys")
+	}
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
 	}
+	return ""
 }
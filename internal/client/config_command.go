@@ -0,0 +1,161 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+)
+
+const (
+	ConfigListOperation = "list"
+	ConfigGetOperation  = "get"
+	ConfigSetOperation  = "set"
+)
+
+// Setting of configuration file with profile used by default
+const currentProfileKey = "current-profile"
+
+// Command to read and change configuration file
+type ConfigCommand struct {
+	operation string
+	args      []string
+}
+
+func NewConfigCommand(operation string, args []string) (*ConfigCommand, error) {
+	switch operation {
+	case ConfigListOperation:
+		if len(args) != 0 {
+			return nil, errors.New("config list has no arguments")
+		}
+	case ConfigGetOperation:
+		if len(args) > 1 {
+			return nil, errors.New("setting name is expected")
+		}
+	case ConfigSetOperation:
+		if len(args) != 2 {
+			return nil, errors.New("setting name and value are expected")
+		}
+	default:
+		return nil, fmt.Errorf("unknown config operation \"%s\"", operation)
+	}
+
+	return &ConfigCommand{operation: operation, args: args}, nil
+}
+
+func (cmd *ConfigCommand) Execute(config Config) error {
+	file, err := loadConfigFile(config.ConfigFile)
+	if err != nil {
+		return err
+	}
+
+	switch cmd.operation {
+	case ConfigListOperation:
+		return cmd.list(config, file)
+	case ConfigGetOperation:
+		return cmd.get(config, file)
+	default:
+		return cmd.set(config, file)
+	}
+}
+
+// Print profiles of configuration file, selected profile is marked
+func (cmd *ConfigCommand) list(config Config, file configFile) error {
+	output := struct {
+		ConfigFile string             `json:"config_file"`
+		Profile    string             `json:"profile"`
+		Profiles   map[string]profile `json:"profiles"`
+	}{config.ConfigFile, config.Profile, file.Profiles}
+
+	return printOutput(config, output, func(w io.Writer) error {
+		fmt.Fprintf(w, "Config file: %s\n", config.ConfigFile)
+		if len(file.Profiles) == 0 {
+			_, err := fmt.Fprintln(w, "There are no profiles")
+			return err
+		}
+
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, name := range file.profileNames() {
+			marker := " "
+			if name == config.Profile {
+				marker = "*"
+			}
+			settings := file.Profiles[name]
+			fmt.Fprintf(writer, "%s %s\t%s\t%s\n", marker, name, settings.Server, settings.Username)
+		}
+		return writer.Flush()
+	})
+}
+
+// Print one setting of selected profile, or all settings when name is omitted
+func (cmd *ConfigCommand) get(config Config, file configFile) error {
+	settings := file.Profiles[config.Profile]
+	if len(cmd.args) == 0 {
+		values := map[string]string{"profile": config.Profile}
+		for _, key := range profileKeys {
+			values[key.name] = key.get(settings)
+		}
+		return printOutput(config, values, func(w io.Writer) error {
+			fmt.Fprintf(w, "profile=%s\n", config.Profile)
+			for _, key := range profileKeys {
+				fmt.Fprintf(w, "%s=%s\n", key.name, values[key.name])
+			}
+			return nil
+		})
+	}
+
+	// single setting is printed as is, like secret field
+	if cmd.args[0] == currentProfileKey {
+		fmt.Println(firstNonEmpty(file.CurrentProfile, defaultProfileName))
+		return nil
+	}
+	key, err := findProfileKey(cmd.args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Println(key.get(settings))
+	return nil
+}
+
+// Change setting of selected profile, profile is created if it does not exist
+func (cmd *ConfigCommand) set(config Config, file configFile) error {
+	name, value := cmd.args[0], cmd.args[1]
+	if name == currentProfileKey {
+		if _, ok := file.Profiles[value]; !ok && value != defaultProfileName {
+			return fmt.Errorf("profile %s is not configured", value)
+		}
+		file.CurrentProfile = value
+		if err := file.save(config.ConfigFile); err != nil {
+			return err
+		}
+		return printResult(config, commandResult{Status: "saved", Name: currentProfileKey}, fmt.Sprintf("Profile %s is used by default", value))
+	}
+
+	key, err := findProfileKey(name)
+	if err != nil {
+		return err
+	}
+	settings := file.Profiles[config.Profile]
+	if err := key.set(&settings, value); err != nil {
+		return err
+	}
+	file.Profiles[config.Profile] = settings
+
+	if err := file.save(config.ConfigFile); err != nil {
+		return err
+	}
+	return printResult(config, commandResult{Status: "saved", Name: name}, fmt.Sprintf("Setting %s of profile %s was saved", name, config.Profile))
+}
+
+// Fabric to create config command for one operation
+type ConfigCommandFactory struct {
+	Operation string
+}
+
+func (f *ConfigCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewConfigCommand(f.Operation, nil)
+}
+
+func (f *ConfigCommandFactory) CreateWithArgs(flags map[string]string, args []string) (Command, error) {
+	return NewConfigCommand(f.Operation, args)
+}
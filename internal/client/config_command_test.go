@@ -0,0 +1,63 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestConfigCommand_Set(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "gophkeeper", "config.json")
+	config := Config{ConfigFile: configPath, Profile: "work"}
+
+	execute := func(operation string, args ...string) error {
+		command, err := NewConfigCommand(operation, args)
+		require.NoError(t, err)
+		return command.Execute(config)
+	}
+
+	require.NoError(t, execute(ConfigSetOperation, "server", "https://work.example.com"))
+	require.NoError(t, execute(ConfigSetOperation, "insecure-skip-verify", "true"))
+	assert.Error(t, execute(ConfigSetOperation, "insecure-skip-verify", "maybe"))
+	assert.Error(t, execute(ConfigSetOperation, "unknown", "value"))
+	assert.Error(t, execute(ConfigSetOperation, currentProfileKey, "personal"))
+	require.NoError(t, execute(ConfigSetOperation, currentProfileKey, "work"))
+
+	file, err := loadConfigFile(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, "work", file.CurrentProfile)
+	assert.Equal(t, profile{Server: "https://work.example.com", InsecureSkipVerify: true}, file.Profiles["work"])
+
+	info, err := os.Stat(configPath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	assert.NoError(t, execute(ConfigGetOperation, "server"))
+	assert.NoError(t, execute(ConfigListOperation))
+}
+
+func TestNewConfigCommand(t *testing.T) {
+	_, err := NewConfigCommand(ConfigSetOperation, []string{"server"})
+	assert.Error(t, err)
+	_, err = NewConfigCommand(ConfigGetOperation, []string{"server", "username"})
+	assert.Error(t, err)
+	_, err = NewConfigCommand(ConfigListOperation, []string{"server"})
+	assert.Error(t, err)
+	_, err = NewConfigCommand(ConfigGetOperation, nil)
+	assert.NoError(t, err)
+}
+
+func TestSaveTokenToFile(t *testing.T) {
+	config := Config{TokenFile: filepath.Join(t.TempDir(), "tokens", "work.json")}
+	require.NoError(t, saveTokenToFile(config, "token"))
+
+	info, err := os.Stat(config.TokenFile)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	token, err := readToken(config)
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+}
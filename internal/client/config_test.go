@@ -1,9 +1,15 @@
 package client
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"flag"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
 )
 
@@ -50,6 +56,7 @@ func TestParseConfig(t *testing.T) {
 		},
 	}
 
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			if tt.envAddress != "" {
@@ -64,9 +71,122 @@ func TestParseConfig(t *testing.T) {
 			flag.CommandLine = flag.NewFlagSet(tt.name, flag.ExitOnError)
 			os.Args = append([]string{"cmd"}, tt.cmdArgs...)
 
-			config := ParseConfig()
+			config, err := ParseConfig()
+			require.NoError(t, err)
 			assert.Equal(t, tt.expectedAddress, config.ServerAddress)
 			assert.Equal(t, tt.expectedEncryptionKey, config.EncryptionKey)
 		})
 	}
 }
+
+// Parse config with fresh command line flags
+func parseTestConfig(t *testing.T, args ...string) Config {
+	flag.CommandLine = flag.NewFlagSet(t.Name(), flag.ExitOnError)
+	os.Args = append([]string{"cmd"}, args...)
+	config, err := ParseConfig()
+	require.NoError(t, err)
+	return config
+}
+
+func TestParseConfig_CredentialsFolder(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	assert.Equal(t, "credentials", parseTestConfig(t).CredentialsFolder)
+
+	t.Setenv("CREDENTIALS_FOLDER", "dev/tokens")
+	assert.Equal(t, "dev/tokens", parseTestConfig(t).CredentialsFolder)
+
+	assert.Equal(t, "ci", parseTestConfig(t, "-credentials-folder", "ci").CredentialsFolder)
+}
+
+func TestParseConfig_Profiles(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	configPath := filepath.Join(dir, "gophkeeper", "config.json")
+	file := configFile{
+		CurrentProfile: "work",
+		Profiles: map[string]profile{
+			"work":     {Server: "https://work.example.com", Username: "ivan", CredentialsFolder: "ci"},
+			"personal": {Server: "https://home.example.com", TokenFile: filepath.Join(dir, "home.json")},
+		},
+	}
+	require.NoError(t, file.save(configPath))
+
+	config := parseTestConfig(t)
+	assert.Equal(t, configPath, config.ConfigFile)
+	assert.Equal(t, "work", config.Profile)
+	assert.True(t, config.profileFound)
+	assert.Equal(t, "https://work.example.com", config.ServerAddress)
+	assert.Equal(t, "ivan", config.Username)
+	assert.Equal(t, "ci", config.CredentialsFolder)
+	assert.Equal(t, filepath.Join(dir, "gophkeeper", "tokens", "work.json"), config.TokenFile)
+	assert.Equal(t, runtimeSocketPath("agent-work"), config.AgentSocket)
+
+	config = parseTestConfig(t, "--profile", "personal")
+	assert.Equal(t, "https://home.example.com", config.ServerAddress)
+	assert.Equal(t, filepath.Join(dir, "home.json"), config.TokenFile)
+
+	t.Setenv("GOPHKEEPER_PROFILE", "personal")
+	config = parseTestConfig(t, "-a", "http://localhost:9090")
+	assert.Equal(t, "personal", config.Profile)
+	assert.Equal(t, "http://localhost:9090", config.ServerAddress)
+
+	config = parseTestConfig(t, "--profile", "unknown")
+	assert.False(t, config.profileFound)
+	assert.Equal(t, defaultServerAddress, config.ServerAddress)
+	assert.Error(t, checkProfile(config, &ReadCommand{}))
+	assert.NoError(t, checkProfile(config, &ConfigCommand{}))
+}
+
+func TestParseConfig_InvalidFile(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(configPath, []byte("{"), 0600))
+
+	flag.CommandLine = flag.NewFlagSet(t.Name(), flag.ExitOnError)
+	os.Args = []string{"cmd", "-config", configPath}
+	_, err := ParseConfig()
+	assert.Error(t, err)
+}
+
+func TestClientTLSConfig_PinnedKeys(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer testServer.Close()
+
+	hash := sha256.Sum256(testServer.Certificate().RawSubjectPublicKeyInfo)
+	pin := base64.StdEncoding.EncodeToString(hash[:])
+	otherPin := base64.StdEncoding.EncodeToString(make([]byte, sha256.Size))
+
+	pins, err := parsePins(" sha256/" + pin + ", " + otherPin + ",")
+	require.NoError(t, err)
+	assert.Equal(t, []string{pin, otherPin}, pins)
+	_, err = parsePins("not-a-pin")
+	assert.Error(t, err)
+
+	request := func(pins []string) error {
+		tlsConfig, err := clientTLSConfig(Config{InsecureSkipVerify: true, PinnedKeys: pins})
+		require.NoError(t, err)
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+		response, err := client.Get(testServer.URL)
+		if err == nil {
+			response.Body.Close()
+		}
+		return err
+	}
+	assert.NoError(t, request(pins))
+	assert.Error(t, request([]string{otherPin}))
+
+	_, err = clientTLSConfig(Config{ClientCertFile: filepath.Join(t.TempDir(), "missing.crt")})
+	assert.Error(t, err)
+}
+
+func TestReadToken_ClientCertificate(t *testing.T) {
+	config := Config{TokenFile: filepath.Join(t.TempDir(), "token.json")}
+	_, err := readToken(config)
+	assert.Error(t, err)
+
+	config.ClientCertFile = "client.crt"
+	token, err := readToken(config)
+	require.NoError(t, err)
+	assert.Empty(t, token)
+}
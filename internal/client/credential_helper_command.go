@@ -0,0 +1,247 @@
+package client
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gitCredentialCommand    = "git-credential"
+	dockerCredentialCommand = "docker-credential"
+	// Message docker expects from helper when credentials are missing
+	dockerCredentialsNotFound = "credentials not found in native keychain"
+)
+
+// Arguments of root command when binary is called via git-credential-* or docker-credential-* symlink
+func CredentialHelperArgs(program string, args []string) ([]string, bool) {
+	name := strings.TrimSuffix(filepath.Base(program), filepath.Ext(program))
+	for _, command := range []string{gitCredentialCommand, dockerCredentialCommand} {
+		if strings.HasPrefix(name, command+"-") {
+			return append([]string{command}, args...), true
+		}
+	}
+	return nil, false
+}
+
+// Check that command is called by git or docker as credential helper
+func IsCredentialHelper(args []string) bool {
+	return len(args) > 0 && (args[0] == gitCredentialCommand || args[0] == dockerCredentialCommand)
+}
+
+// Command to serve git credential helper protocol: get, store and erase
+type GitCredentialCommand struct {
+	operation string
+	folder    string
+}
+
+// Command to serve docker credential helper protocol: get, store, erase and list
+type DockerCredentialCommand struct {
+	operation string
+	folder    string
+}
+
+func parseCredentialOperation(args []string) (string, error) {
+	if len(args) != 1 {
+		return "", errors.New("credential helper operation is required")
+	}
+	return args[0], nil
+}
+
+func newServerCredentialStore(config Config, folder string) (*serverCredentialStore, error) {
+	token, err := readToken(config)
+	if err != nil {
+		return nil, fmt.Errorf("can`t find auth data: %w", err)
+	}
+	if folder == "" {
+		folder = config.CredentialsFolder
+	}
+
+	return &serverCredentialStore{
+		api:    newAPIClient(config, token),
+		config: config,
+		folder: folder,
+	}, nil
+}
+
+func (cmd *GitCredentialCommand) Execute(config Config) error {
+	store, err := newServerCredentialStore(config, cmd.folder)
+	if err != nil {
+		return err
+	}
+	return gitCredential(cmd.operation, os.Stdin, os.Stdout, store)
+}
+
+// Serve one git credential operation. Unknown operations are ignored as required by git
+func gitCredential(operation string, in io.Reader, out io.Writer, store credentialStore) error {
+	attributes := make(map[string]string)
+	scanner := bufio.NewScanner(in)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, "="); ok {
+			attributes[key] = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error during read git credential request: %w", err)
+	}
+
+	host := attributes["host"]
+	if host == "" {
+		return errors.New("git credential request has no host")
+	}
+	if requestPath := strings.Trim(attributes["path"], "/"); requestPath != "" {
+		host += "/" + requestPath
+	}
+	serverURL := attributes["protocol"] + "://" + host
+
+	switch operation {
+	case "get":
+		credentials, err := store.get(host)
+		if errors.Is(err, gophkeeperclient.ErrNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if username := attributes["username"]; username != "" && username != credentials.Username {
+			return nil
+		}
+		_, err = fmt.Fprintf(out, "username=%s\npassword=%s\n", credentials.Username, credentials.Password)
+		return err
+	case "store":
+		if attributes["username"] == "" || attributes["password"] == "" {
+			return nil
+		}
+		return store.store(host, hostCredentials{ServerURL: serverURL, Username: attributes["username"], Password: attributes["password"]})
+	case "erase":
+		if err := store.erase(host); err != nil && !errors.Is(err, gophkeeperclient.ErrNotFound) {
+			return err
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Credentials in docker credential helper format
+type dockerCredentials struct {
+	ServerURL string
+	Username  string
+	Secret    string
+}
+
+func (cmd *DockerCredentialCommand) Execute(config Config) error {
+	store, err := newServerCredentialStore(config, cmd.folder)
+	if err != nil {
+		return err
+	}
+	return dockerCredential(cmd.operation, os.Stdin, os.Stdout, store)
+}
+
+// Serve one docker credential operation. Docker reads error message from stdout
+func dockerCredential(operation string, in io.Reader, out io.Writer, store credentialStore) error {
+	err := serveDockerCredential(operation, in, out, store)
+	if errors.Is(err, gophkeeperclient.ErrNotFound) {
+		err = errors.New(dockerCredentialsNotFound)
+	}
+	if err != nil {
+		fmt.Fprintln(out, err.Error())
+	}
+	return err
+}
+
+func serveDockerCredential(operation string, in io.Reader, out io.Writer, store credentialStore) error {
+	switch operation {
+	case "get":
+		serverURL, host, err := readDockerServerURL(in)
+		if err != nil {
+			return err
+		}
+		credentials, err := store.get(host)
+		if err != nil {
+			return err
+		}
+		return json.NewEncoder(out).Encode(dockerCredentials{ServerURL: serverURL, Username: credentials.Username, Secret: credentials.Password})
+	case "store":
+		var credentials dockerCredentials
+		if err := json.NewDecoder(in).Decode(&credentials); err != nil {
+			return fmt.Errorf("can`t parse docker credentials: %w", err)
+		}
+		host, err := credentialHost(credentials.ServerURL)
+		if err != nil {
+			return err
+		}
+		return store.store(host, hostCredentials{ServerURL: credentials.ServerURL, Username: credentials.Username, Password: credentials.Secret})
+	case "erase":
+		_, host, err := readDockerServerURL(in)
+		if err != nil {
+			return err
+		}
+		return store.erase(host)
+	case "list":
+		credentials, err := store.list()
+		if err != nil {
+			return err
+		}
+		usernames := make(map[string]string, len(credentials))
+		for _, credential := range credentials {
+			usernames[credential.ServerURL] = credential.Username
+		}
+		return json.NewEncoder(out).Encode(usernames)
+	default:
+		return fmt.Errorf("unknown docker credential operation \"%s\"", operation)
+	}
+}
+
+func readDockerServerURL(in io.Reader) (string, string, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", "", fmt.Errorf("error during read server url: %w", err)
+	}
+	serverURL := strings.TrimSpace(string(data))
+	host, err := credentialHost(serverURL)
+	if err != nil {
+		return "", "", err
+	}
+	return serverURL, host, nil
+}
+
+// Fabric to create git credential helper command
+type GitCredentialCommandFactory struct{}
+
+func (f *GitCredentialCommandFactory) Create(args map[string]string) (Command, error) {
+	return f.CreateWithArgs(args, nil)
+}
+
+func (f *GitCredentialCommandFactory) CreateWithArgs(args map[string]string, positional []string) (Command, error) {
+	operation, err := parseCredentialOperation(positional)
+	if err != nil {
+		return nil, err
+	}
+	return &GitCredentialCommand{operation: operation, folder: args["folder"]}, nil
+}
+
+// Fabric to create docker credential helper command
+type DockerCredentialCommandFactory struct{}
+
+func (f *DockerCredentialCommandFactory) Create(args map[string]string) (Command, error) {
+	return f.CreateWithArgs(args, nil)
+}
+
+func (f *DockerCredentialCommandFactory) CreateWithArgs(args map[string]string, positional []string) (Command, error) {
+	operation, err := parseCredentialOperation(positional)
+	if err != nil {
+		return nil, err
+	}
+	return &DockerCredentialCommand{operation: operation, folder: args["folder"]}, nil
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"bytes"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type memoryCredentialStore struct {
+	credentials map[string]hostCredentials
+}
+
+func (s *memoryCredentialStore) get(host string) (hostCredentials, error) {
+	credentials, ok := s.credentials[host]
+	if !ok {
+		return hostCredentials{}, gophkeeperclient.ErrNotFound
+	}
+	return credentials, nil
+}
+
+func (s *memoryCredentialStore) store(host string, credentials hostCredentials) error {
+	s.credentials[host] = credentials
+	return nil
+}
+
+func (s *memoryCredentialStore) erase(host string) error {
+	if _, ok := s.credentials[host]; !ok {
+		return gophkeeperclient.ErrNotFound
+	}
+	delete(s.credentials, host)
+	return nil
+}
+
+func (s *memoryCredentialStore) list() ([]hostCredentials, error) {
+	var credentials []hostCredentials
+	for _, credential := range s.credentials {
+		credentials = append(credentials, credential)
+	}
+	return credentials, nil
+}
+
+func TestGitCredential(t *testing.T) {
+	store := &memoryCredentialStore{credentials: map[string]hostCredentials{}}
+	run := func(operation string, request string) string {
+		var out bytes.Buffer
+		require.NoError(t, gitCredential(operation, strings.NewReader(request), &out, store))
+		return out.String()
+	}
+
+	assert.Empty(t, run("get", "protocol=https\nhost=github.com\n\n"))
+
+	run("store", "protocol=https\nhost=github.com\nusername=bot\npassword=ghp_token\n\n")
+	assert.Equal(t, hostCredentials{ServerURL: "https://github.com", Username: "bot", Password: "ghp_token"}, store.credentials["github.com"])
+
+	assert.Equal(t, "username=bot\npassword=ghp_token\n", run("get", "protocol=https\nhost=github.com\n\n"))
+	assert.Empty(t, run("get", "protocol=https\nhost=github.com\nusername=other\n\n"))
+	assert.Empty(t, run("get", "protocol=https\nhost=github.com\npath=org/repo.git\n\n"))
+
+	assert.Empty(t, run("capability", "protocol=https\nhost=github.com\n\n"))
+
+	run("erase", "protocol=https\nhost=github.com\n\n")
+	assert.Empty(t, store.credentials)
+	run("erase", "protocol=https\nhost=github.com\n\n")
+
+	err := gitCredential("get", strings.NewReader("protocol=https\n\n"), &bytes.Buffer{}, store)
+	assert.Error(t, err)
+}
+
+func TestDockerCredential(t *testing.T) {
+	store := &memoryCredentialStore{credentials: map[string]hostCredentials{}}
+
+	var out bytes.Buffer
+	err := dockerCredential("get", strings.NewReader("ghcr.io"), &out, store)
+	assert.Error(t, err)
+	assert.Equal(t, dockerCredentialsNotFound+"\n", out.String())
+
+	out.Reset()
+	err = dockerCredential("store", strings.NewReader(`{"ServerURL":"https://index.docker.io/v1/","Username":"bot","Secret":"dckr_pat"}`), &out, store)
+	require.NoError(t, err)
+	assert.Equal(t, "bot", store.credentials["index.docker.io/v1"].Username)
+
+	out.Reset()
+	err = dockerCredential("get", strings.NewReader("https://index.docker.io/v1/\n"), &out, store)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"ServerURL":"https://index.docker.io/v1/","Username":"bot","Secret":"dckr_pat"}`, out.String())
+
+	out.Reset()
+	err = dockerCredential("list", strings.NewReader(""), &out, store)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"https://index.docker.io/v1/":"bot"}`, out.String())
+
+	out.Reset()
+	require.NoError(t, dockerCredential("erase", strings.NewReader("https://index.docker.io/v1/"), &out, store))
+	assert.Empty(t, store.credentials)
+
+	assert.Error(t, dockerCredential("unknown", strings.NewReader(""), &bytes.Buffer{}, store))
+}
+
+func TestCredentialHelperArgs(t *testing.T) {
+	args, ok := CredentialHelperArgs("/usr/local/bin/docker-credential-gophkeeper", []string{"get"})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"docker-credential", "get"}, args)
+
+	args, ok = CredentialHelperArgs("/opt/bin/git-credential-gophkeeper.exe", []string{"store"})
+	assert.True(t, ok)
+	assert.Equal(t, []string{"git-credential", "store"}, args)
+
+	_, ok = CredentialHelperArgs("/usr/local/bin/gophkeeper", []string{"secret", "read"})
+	assert.False(t, ok)
+
+	assert.True(t, IsCredentialHelper([]string{"git-credential", "get"}))
+	assert.False(t, IsCredentialHelper([]string{"secret", "read"}))
+}
+
+func TestCredentialHost(t *testing.T) {
+	for serverURL, expected := range map[string]string{
+		"https://index.docker.io/v1/": "index.docker.io/v1",
+		"ghcr.io":                     "ghcr.io",
+		"localhost:5000":              "localhost:5000",
+		"https://user@gitlab.com/":    "gitlab.com",
+	} {
+		host, err := credentialHost(serverURL)
+		require.NoError(t, err)
+		assert.Equal(t, expected, host)
+	}
+
+	_, err := credentialHost("https://")
+	assert.Error(t, err)
+}
+
+func TestServerCredentialStore_Store(t *testing.T) {
+	key := "WYJcWgkItShq513L21E1CFuz6uQWDy3p"
+	fake, config := newFakeSecretServer(t)
+	config.EncryptionKey = key
+	store := &serverCredentialStore{api: newAPIClient(config, "token"), config: config, folder: "git"}
+
+	credentials := hostCredentials{ServerURL: "https://github.com", Username: "bot", Password: "token"}
+	require.NoError(t, store.store("github.com", credentials))
+	assert.Equal(t, 1, fake.creates)
+
+	secret, ok := fake.secret("git/github.com")
+	require.True(t, ok)
+	secret.Tags = []string{"ci"}
+	secret.Notes = []byte("note")
+	fake.secrets["git/github.com"] = secret
+
+	require.NoError(t, store.store("github.com", credentials))
+	assert.Zero(t, fake.updates, "unchanged credentials should not be saved")
+
+	credentials.Password = "new-token"
+	require.NoError(t, store.store("github.com", credentials))
+	assert.Equal(t, 1, fake.updates)
+	assert.Zero(t, fake.upserts)
+
+	secret, ok = fake.secret("git/github.com")
+	require.True(t, ok)
+	assert.Equal(t, []string{"ci"}, secret.Tags)
+	assert.Equal(t, []byte("note"), secret.Notes)
+	assert.Equal(t, "https://github.com", secret.URL)
+	stored, err := store.get("github.com")
+	require.NoError(t, err)
+	assert.Equal(t, credentials, stored)
+}
@@ -0,0 +1,147 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Credentials of host used by git and docker
+type hostCredentials struct {
+	ServerURL string
+	Username  string
+	Password  string
+}
+
+// Storage of host credentials used by credential helpers
+type credentialStore interface {
+	get(host string) (hostCredentials, error)
+	store(host string, credentials hostCredentials) error
+	erase(host string) error
+	list() ([]hostCredentials, error)
+}
+
+// Credentials stored as CREDENTIALS secrets named by host in one folder
+type serverCredentialStore struct {
+	api    *apiClient
+	config Config
+	folder string
+}
+
+func (s *serverCredentialStore) get(host string) (hostCredentials, error) {
+	secret, err := s.api.FindSecret(context.Background(), s.secretName(host))
+	if err != nil {
+		return hostCredentials{}, err
+	}
+	return s.decrypt(secret)
+}
+
+// Save credentials of host. Helpers call store after each successful auth, so unchanged credentials are not saved,
+// and only content and URL of existing secret are changed keeping its metadata
+func (s *serverCredentialStore) store(host string, credentials hostCredentials) error {
+	secret, err := s.api.FindSecret(context.Background(), s.secretName(host))
+	if errors.Is(err, gophkeeperclient.ErrNotFound) {
+		secret = model.Secret{Name: s.secretName(host), Type: model.CredentialsSecretType, URL: credentials.ServerURL}
+		if secret.Content, err = s.encrypt(credentials); err != nil {
+			return err
+		}
+		return s.api.CreateSecret(context.Background(), secret)
+	}
+	if err != nil {
+		return err
+	}
+
+	existing, err := s.decrypt(secret)
+	if err != nil {
+		return err
+	}
+	urlChanged := credentials.ServerURL != "" && credentials.ServerURL != secret.URL
+	if existing.Username == credentials.Username && existing.Password == credentials.Password && !urlChanged {
+		return nil
+	}
+
+	if secret.Content, err = s.encrypt(credentials); err != nil {
+		return err
+	}
+	if urlChanged {
+		secret.URL = credentials.ServerURL
+	}
+	return updateSecret(context.Background(), s.api, secret)
+}
+
+func (s *serverCredentialStore) encrypt(credentials hostCredentials) ([]byte, error) {
+	content, err := crypto.EncryptData([]byte(credentials.Username+":"+credentials.Password), []byte(s.config.EncryptionKey))
+	if err != nil {
+		return nil, fmt.Errorf("error during encrypt data: %w", err)
+	}
+	return content, nil
+}
+
+func (s *serverCredentialStore) erase(host string) error {
+	return s.api.DeleteSecret(context.Background(), s.secretName(host))
+}
+
+func (s *serverCredentialStore) list() ([]hostCredentials, error) {
+	secrets, err := s.api.FindAllSecrets(context.Background(), model.SecretFilter{Folder: s.folder, Type: model.CredentialsSecretType})
+	if err != nil {
+		return nil, err
+	}
+
+	var credentials []hostCredentials
+	for _, secret := range secrets {
+		if len(secret.Content) == 0 {
+			continue
+		}
+		hostCredential, err := s.decrypt(secret)
+		if err != nil {
+			return nil, err
+		}
+		credentials = append(credentials, hostCredential)
+	}
+	return credentials, nil
+}
+
+func (s *serverCredentialStore) secretName(host string) string {
+	return path.Join(s.folder, host)
+}
+
+func (s *serverCredentialStore) decrypt(secret model.Secret) (hostCredentials, error) {
+	if secret.Type != model.CredentialsSecretType {
+		return hostCredentials{}, fmt.Errorf("secret %s has type %s, CREDENTIALS secret is expected", secret.Name, secret.Type)
+	}
+	content, err := crypto.DecryptData(secret.Content, []byte(s.config.EncryptionKey))
+	if err != nil {
+		return hostCredentials{}, fmt.Errorf("error during decrypt content: %w", err)
+	}
+
+	username, password, _ := strings.Cut(string(content), ":")
+	serverURL := secret.URL
+	if serverURL == "" {
+		serverURL = strings.TrimPrefix(secret.Name, s.folder+"/")
+	}
+	return hostCredentials{ServerURL: serverURL, Username: username, Password: password}, nil
+}
+
+// Host key of server URL: scheme, credentials, query and trailing slashes are dropped
+func credentialHost(serverURL string) (string, error) {
+	value := strings.TrimSpace(serverURL)
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("invalid server url \"%s\"", serverURL)
+	}
+
+	host := parsed.Host
+	if trimmedPath := strings.Trim(parsed.Path, "/"); trimmedPath != "" {
+		host += "/" + trimmedPath
+	}
+	return host, nil
+}
@@ -1,10 +1,9 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"time"
 )
 
 // Command to delete secret
@@ -24,28 +23,18 @@ func NewDeleteCommand(args map[string]string) (*DeleteCommand, error) {
 }
 
 func (cmd *DeleteCommand) Execute(config Config) error {
-	token, err := readTokenFromFile()
+	token, err := readToken(config)
 	if err != nil {
 		return fmt.Errorf("can`t find auth data: %w", err)
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+token).
-		Delete(config.ServerAddress + "/api/user/secret/" + cmd.secretName)
-
+	err = newAPIClient(config, token).DeleteSecret(context.Background(), cmd.secretName)
 	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("can`t delete secret. Reason: %s", resp.String())
+		return fmt.Errorf("can`t delete secret. Reason: %w", err)
 	}
 
-	fmt.Printf("Secret with name \"%s\" was deleted", cmd.secretName)
-	return nil
+	return printResult(config, commandResult{Status: "deleted", Name: cmd.secretName},
+		fmt.Sprintf("Secret with name \"%s\" was moved to trash", cmd.secretName))
 }
 
 // Fabric to create secret delete command
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Command to list secrets which expire or should be rotated soon
+type DueCommand struct {
+	within time.Duration
+}
+
+func NewDueCommand(args map[string]string) (*DueCommand, error) {
+	within := time.Duration(0)
+	if value := args["within"]; value != "" {
+		parsedWithin, err := parseDuration(value)
+		if err != nil || parsedWithin < 0 {
+			return nil, errors.New("within should be a positive duration, e.g. 30d")
+		}
+		within = parsedWithin
+	}
+
+	return &DueCommand{within: within}, nil
+}
+
+func (cmd *DueCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := newAPIClient(config, token)
+
+	now := time.Now()
+	dueBefore := now.Add(cmd.within)
+	filter := model.SecretFilter{DueBefore: &dueBefore}
+
+	found := make([]dueSecret, 0)
+	for {
+		page, err := api.FindSecrets(context.Background(), filter)
+		if err != nil {
+			return err
+		}
+
+		for _, secret := range page.Secrets {
+			reason, moment, ok := dueReason(secret)
+			if !ok {
+				continue
+			}
+			status := "due"
+			if moment.Before(now) {
+				status = "overdue"
+			}
+			found = append(found, dueSecret{Name: secret.Name, Type: secret.Type, Reason: reason, DueAt: moment, Status: status})
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	return printOutput(config, found, func(w io.Writer) error {
+		if len(found) == 0 {
+			_, err := fmt.Fprintln(w, "There are no secrets to rotate")
+			return err
+		}
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, secret := range found {
+			fmt.Fprintf(writer, "%s\t%s\t%s %s\t%s\n", secret.Name, secret.Type, secret.Reason, secret.DueAt.Local().Format(time.DateOnly), secret.Status)
+		}
+		return writer.Flush()
+	})
+}
+
+// Secret which expires or should be rotated
+type dueSecret struct {
+	Name   string    `json:"name"`
+	Type   string    `json:"type"`
+	Reason string    `json:"reason"`
+	DueAt  time.Time `json:"due_at"`
+	Status string    `json:"status"`
+}
+
+// Find the earliest due date of secret: expiry or rotation
+func dueReason(secret model.Secret) (string, time.Time, bool) {
+	reason, moment, ok := "", time.Time{}, false
+	if secret.ExpiresAt != nil {
+		reason, moment, ok = "expires", *secret.ExpiresAt, true
+	}
+	if secret.RotateAfter != nil && (!ok || secret.RotateAfter.Before(moment)) {
+		reason, moment, ok = "rotate", *secret.RotateAfter, true
+	}
+	return reason, moment, ok
+}
+
+// Fabric to create secret due command
+type DueCommandFactory struct{}
+
+func (f *DueCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewDueCommand(args)
+}
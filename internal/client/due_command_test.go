@@ -0,0 +1,66 @@
+package client
+
+import (
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDueReason(t *testing.T) {
+	early := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	late := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		secret         model.Secret
+		expectedReason string
+		expectedMoment time.Time
+		expectedOk     bool
+	}{
+		{
+			name:           "Expiry only",
+			secret:         model.Secret{ExpiresAt: &late},
+			expectedReason: "expires",
+			expectedMoment: late,
+			expectedOk:     true,
+		},
+		{
+			name:           "Rotation is earlier than expiry",
+			secret:         model.Secret{ExpiresAt: &late, RotateAfter: &early},
+			expectedReason: "rotate",
+			expectedMoment: early,
+			expectedOk:     true,
+		},
+		{
+			name:           "Expiry is earlier than rotation",
+			secret:         model.Secret{ExpiresAt: &early, RotateAfter: &late},
+			expectedReason: "expires",
+			expectedMoment: early,
+			expectedOk:     true,
+		},
+		{
+			name:       "No dates",
+			secret:     model.Secret{},
+			expectedOk: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, moment, ok := dueReason(tt.secret)
+			assert.Equal(t, tt.expectedOk, ok)
+			assert.Equal(t, tt.expectedReason, reason)
+			assert.Equal(t, tt.expectedMoment, moment)
+		})
+	}
+}
+
+func TestNewDueCommand(t *testing.T) {
+	cmd, err := NewDueCommand(map[string]string{"within": "30d"})
+	assert.NoError(t, err)
+	assert.Equal(t, 30*24*time.Hour, cmd.within)
+
+	_, err = NewDueCommand(map[string]string{"within": "soon"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,237 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"gopkg.in/yaml.v3"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Editor used when VISUAL and EDITOR are not set
+const defaultEditor = "vi"
+
+// Command to edit decoded secret in external editor and upload the change
+type EditCommand struct {
+	secretName string
+	// Open file in editor, replaced in tests
+	editFile func(path string) error
+}
+
+func NewEditCommand(args map[string]string) (*EditCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name are required")
+	}
+
+	return &EditCommand{secretName: secretName, editFile: runEditor}, nil
+}
+
+func (cmd *EditCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := newAPIClient(config, token)
+	secret, err := api.FindSecret(context.Background(), cmd.secretName)
+	if err != nil {
+		return fmt.Errorf("can`t read secret. Reason: %w", err)
+	}
+
+	// server deleted secret on read, saving it would create it again
+	if secret.BurnAfterReading {
+		return fmt.Errorf("secret %s is deleted after reading and can`t be edited", secret.Name)
+	}
+
+	key := []byte(config.EncryptionKey)
+	document, err := newEditDocument(secret, key)
+	if err != nil {
+		return err
+	}
+
+	edited, err := cmd.edit(document)
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(edited, document) {
+		return printResult(config, commandResult{Status: "unchanged", Name: secret.Name}, "Secret was not changed")
+	}
+
+	updated, err := applyEditDocument(secret, edited, key)
+	if err != nil {
+		return err
+	}
+	if err := updateSecret(context.Background(), api, updated); err != nil {
+		if errors.Is(err, errSecretChanged) {
+			return fmt.Errorf("secret %s was changed or removed while it was edited, re-run edit", secret.Name)
+		}
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
+	}
+	return printResult(config, commandResult{Status: "saved", Name: secret.Name}, "Secret saved successfully")
+}
+
+// Write document to temporary file readable by owner only, open it in editor and read the result
+func (cmd *EditCommand) edit(document []byte) ([]byte, error) {
+	file, err := os.CreateTemp("", "gophkeeper-*.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("error during create temp file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(document)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error during write temp file: %w", err)
+	}
+
+	if err := cmd.editFile(file.Name()); err != nil {
+		return nil, err
+	}
+
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, fmt.Errorf("error during read temp file: %w", err)
+	}
+	return edited, nil
+}
+
+// Run editor from VISUAL or EDITOR, editor command can contain arguments, e.g. "code --wait"
+func runEditor(path string) error {
+	editor := strings.Fields(firstNonEmpty(os.Getenv("VISUAL"), os.Getenv("EDITOR"), defaultEditor))
+	if len(editor) == 0 {
+		editor = []string{defaultEditor}
+	}
+
+	process := exec.Command(editor[0], append(editor[1:], path)...)
+	process.Stdin = os.Stdin
+	process.Stdout = os.Stdout
+	process.Stderr = os.Stderr
+	if err := process.Run(); err != nil {
+		return fmt.Errorf("error during run editor %s: %w", editor[0], err)
+	}
+	return nil
+}
+
+// Build YAML document with editable fields of secret type, URL, note and tags
+func newEditDocument(secret model.Secret, key []byte) ([]byte, error) {
+	fields := editableFields(secret.Type)
+	values, err := decodeSecretFields(secret, fields, key)
+	if err != nil {
+		return nil, err
+	}
+	note, err := secretField(secret, "note", key, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	mapping := &yaml.Node{Kind: yaml.MappingNode}
+	mapping.HeadComment = fmt.Sprintf("Secret %s (%s). Save and close editor to upload changes", secret.Name, secret.Type)
+	for _, field := range fields {
+		appendEditValue(mapping, field.name, values[field.name])
+	}
+	appendEditValue(mapping, "url", secret.URL)
+	appendEditValue(mapping, "note", note)
+
+	tags := &yaml.Node{Kind: yaml.SequenceNode, Style: yaml.FlowStyle}
+	for _, tag := range secret.Tags {
+		tags.Content = append(tags.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: tag})
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: "tags"}, tags)
+
+	var document bytes.Buffer
+	encoder := yaml.NewEncoder(&document)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(mapping); err != nil {
+		return nil, fmt.Errorf("error during marshal secret: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("error during marshal secret: %w", err)
+	}
+	return document.Bytes(), nil
+}
+
+// Add string value to document, multiline values are written as literal blocks
+func appendEditValue(mapping *yaml.Node, name string, value string) {
+	node := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if strings.Contains(value, "\n") {
+		node.Style = yaml.LiteralStyle
+	}
+	mapping.Content = append(mapping.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, node)
+}
+
+// Parse edited document and build encrypted secret, metadata which is not in document is kept
+func applyEditDocument(secret model.Secret, document []byte, key []byte) (model.Secret, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(document, &root); err != nil {
+		return model.Secret{}, fmt.Errorf("can`t parse edited secret: %w", err)
+	}
+	if len(root.Content) == 0 || root.Content[0].Kind != yaml.MappingNode {
+		return model.Secret{}, errors.New("edited secret should be a mapping of fields")
+	}
+
+	values := make(map[string]string)
+	var tags []string
+	mapping := root.Content[0]
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		name, value := mapping.Content[i].Value, mapping.Content[i+1]
+		switch {
+		case name == "tags" && value.Kind == yaml.SequenceNode:
+			for _, tag := range value.Content {
+				tags = append(tags, tag.Value)
+			}
+		case value.Kind == yaml.ScalarNode:
+			values[name] = value.Value
+		default:
+			return model.Secret{}, fmt.Errorf("field %s should be a string", name)
+		}
+	}
+	if value, ok := values["tags"]; ok {
+		tags = append(tags, value)
+	}
+
+	allowed := map[string]bool{"url": true, "note": true, "tags": true}
+	for _, field := range editableFields(secret.Type) {
+		allowed[field.name] = true
+	}
+	for name := range values {
+		if !allowed[name] {
+			return model.Secret{}, fmt.Errorf("secret of type %s has no field \"%s\"", secret.Type, name)
+		}
+	}
+
+	content, err := encodeSecretContent(secret.Type, values)
+	if err != nil {
+		return model.Secret{}, err
+	}
+	secret.Content, err = crypto.EncryptData(content, key)
+	if err != nil {
+		return model.Secret{}, fmt.Errorf("error during encrypt data: %w", err)
+	}
+
+	secret.Notes = nil
+	if values["note"] != "" {
+		secret.Notes, err = crypto.EncryptData([]byte(values["note"]), key)
+		if err != nil {
+			return model.Secret{}, fmt.Errorf("error during encrypt note: %w", err)
+		}
+	}
+	secret.URL = values["url"]
+	secret.Tags = parseTags(tags)
+	return secret, nil
+}
+
+// Fabric to create secret edit command
+type EditCommandFactory struct{}
+
+func (f *EditCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewEditCommand(args)
+}
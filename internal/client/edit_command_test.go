@@ -0,0 +1,215 @@
+package client
+
+import (
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEditDocument(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	encrypt := func(content string) []byte {
+		encrypted, err := crypto.EncryptData([]byte(content), key)
+		require.NoError(t, err)
+		return encrypted
+	}
+	decrypt := func(content []byte) string {
+		decrypted, err := crypto.DecryptData(content, key)
+		require.NoError(t, err)
+		return string(decrypted)
+	}
+
+	secret := model.Secret{
+		Name:    "prod/db",
+		Type:    model.CredentialsSecretType,
+		Content: encrypt("admin:p:ss"),
+		Notes:   encrypt("line one\nline two"),
+		Tags:    []string{"prod"},
+	}
+	document, err := newEditDocument(secret, key)
+	require.NoError(t, err)
+	assert.Equal(t, "# Secret prod/db (CREDENTIALS). Save and close editor to upload changes\n"+
+		"username: admin\npassword: p:ss\nurl: \"\"\nnote: |-\n  line one\n  line two\ntags: [prod]\n", string(document))
+
+	unchanged, err := applyEditDocument(secret, document, key)
+	require.NoError(t, err)
+	assert.Equal(t, "admin:p:ss", decrypt(unchanged.Content))
+	assert.Equal(t, "line one\nline two", decrypt(unchanged.Notes))
+	assert.Equal(t, []string{"prod"}, unchanged.Tags)
+
+	edited := strings.NewReplacer("p:ss", "changed", "url: \"\"", "url: https://db.example.com", "[prod]", "[prod, db]").Replace(string(document))
+	updated, err := applyEditDocument(secret, []byte(edited), key)
+	require.NoError(t, err)
+	assert.Equal(t, "admin:changed", decrypt(updated.Content))
+	assert.Equal(t, "https://db.example.com", updated.URL)
+	assert.Equal(t, []string{"prod", "db"}, updated.Tags)
+
+	_, err = applyEditDocument(secret, []byte("username: admin\npassword: \"\"\n"), key)
+	assert.Error(t, err)
+	_, err = applyEditDocument(secret, []byte("username: admin\npassword: x\nnumber: 1\n"), key)
+	assert.Error(t, err)
+	_, err = applyEditDocument(secret, []byte("- admin\n"), key)
+	assert.Error(t, err)
+}
+
+func TestEditCommandEdit(t *testing.T) {
+	cmd, err := NewEditCommand(map[string]string{"name": "prod/db"})
+	require.NoError(t, err)
+
+	cmd.editFile = func(path string) error {
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+		return os.WriteFile(path, []byte("value: changed\n"), 0600)
+	}
+	edited, err := cmd.edit([]byte("value: text\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "value: changed\n", string(edited))
+
+	_, err = NewEditCommand(map[string]string{})
+	assert.Error(t, err)
+}
+
+func TestSecretCodecs(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	sshKey, err := generateSSHKey("user@host", "")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name       string
+		secretType string
+		values     map[string]string
+	}{
+		{name: "Credentials", secretType: model.CredentialsSecretType, values: map[string]string{"username": "admin", "password": "p:ss"}},
+		{name: "Card", secretType: model.CardSecretType, values: map[string]string{"number": "4111111111111111", "date": "12/30", "code": "123", "holder": "IVAN"}},
+		{name: "Text", secretType: model.TextSecretType, values: map[string]string{"value": "hello"}},
+		{name: "Binary", secretType: model.BinarySecretType, values: map[string]string{"base64": "AAEC/w=="}},
+		{name: "SSH key", secretType: model.SSHKeySecretType, values: map[string]string{"private_key": sshKey.PrivateKey, "comment": "user@host", "passphrase": ""}},
+		{name: "Unknown", secretType: "NOTE", values: map[string]string{"value": "hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content, err := encodeSecretContent(tt.secretType, tt.values)
+			require.NoError(t, err)
+
+			decoded, err := findSecretCodec(tt.secretType).decode(content, time.Now())
+			require.NoError(t, err)
+			for name, value := range tt.values {
+				assert.Equal(t, value, decoded[name])
+			}
+
+			encrypted, err := crypto.EncryptData(content, key)
+			require.NoError(t, err)
+			output, err := newSecretOutput(model.Secret{Name: "secret", Type: tt.secretType, Content: encrypted}, key, time.Now())
+			require.NoError(t, err)
+			assert.Equal(t, decoded, output.Fields)
+		})
+	}
+
+	ssh, err := findSecretCodec(model.SSHKeySecretType).decode(mustEncode(t, model.SSHKeySecretType, map[string]string{"private_key": sshKey.PrivateKey, "comment": "user@host"}), time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, sshKey.PublicKey, ssh["public_key"])
+
+	_, err = encodeSecretContent(model.CredentialsSecretType, map[string]string{"username": "ad:min", "password": "x"})
+	assert.Error(t, err)
+	_, err = encodeSecretContent(model.BinarySecretType, map[string]string{"base64": "not base64"})
+	assert.Error(t, err)
+	_, err = encodeSecretContent(model.SSHKeySecretType, map[string]string{"private_key": "not a key"})
+	assert.Error(t, err)
+}
+
+func mustEncode(t *testing.T, secretType string, values map[string]string) []byte {
+	content, err := encodeSecretContent(secretType, values)
+	require.NoError(t, err)
+	return content
+}
+
+func TestEditCommand_Execute(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	content, err := crypto.EncryptData([]byte("old"), key)
+	require.NoError(t, err)
+	secret := model.Secret{Name: "prod/token", Type: model.TextSecretType, Content: content, Version: 2, Tags: []string{"prod"}}
+	editValue := func(cmd *EditCommand, value string) {
+		cmd.editFile = func(path string) error {
+			return os.WriteFile(path, []byte("value: "+value+"\ntags: [prod]\n"), 0600)
+		}
+	}
+
+	t.Run("should save secret with read version", func(t *testing.T) {
+		fake, config := newFakeSecretServer(t, secret)
+		config.EncryptionKey = string(key)
+		cmd, err := NewEditCommand(map[string]string{"name": "prod/token"})
+		require.NoError(t, err)
+		editValue(cmd, "new")
+
+		require.NoError(t, cmd.Execute(config))
+		saved, ok := fake.secret("prod/token")
+		require.True(t, ok)
+		assert.Equal(t, int64(3), saved.Version)
+		decrypted, err := crypto.DecryptData(saved.Content, key)
+		require.NoError(t, err)
+		assert.Equal(t, "new", string(decrypted))
+		assert.Zero(t, fake.upserts)
+	})
+
+	t.Run("should not overwrite secret changed while it was edited", func(t *testing.T) {
+		fake, config := newFakeSecretServer(t, secret)
+		config.EncryptionKey = string(key)
+		cmd, err := NewEditCommand(map[string]string{"name": "prod/token"})
+		require.NoError(t, err)
+		cmd.editFile = func(path string) error {
+			fake.mu.Lock()
+			changed := fake.secrets["prod/token"]
+			changed.Version++
+			fake.secrets["prod/token"] = changed
+			fake.mu.Unlock()
+			return os.WriteFile(path, []byte("value: new\n"), 0600)
+		}
+
+		err = cmd.Execute(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "re-run edit")
+		saved, _ := fake.secret("prod/token")
+		assert.Equal(t, content, saved.Content)
+	})
+
+	t.Run("should not create secret removed while it was edited", func(t *testing.T) {
+		fake, config := newFakeSecretServer(t, secret)
+		config.EncryptionKey = string(key)
+		cmd, err := NewEditCommand(map[string]string{"name": "prod/token"})
+		require.NoError(t, err)
+		cmd.editFile = func(path string) error {
+			fake.mu.Lock()
+			delete(fake.secrets, "prod/token")
+			fake.mu.Unlock()
+			return os.WriteFile(path, []byte("value: new\n"), 0600)
+		}
+
+		err = cmd.Execute(config)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "re-run edit")
+		_, ok := fake.secret("prod/token")
+		assert.False(t, ok)
+	})
+
+	t.Run("should refuse burn after reading secret", func(t *testing.T) {
+		burned := secret
+		burned.BurnAfterReading = true
+		fake, config := newFakeSecretServer(t, burned)
+		config.EncryptionKey = string(key)
+		cmd, err := NewEditCommand(map[string]string{"name": "prod/token"})
+		require.NoError(t, err)
+		editValue(cmd, "new")
+
+		assert.Error(t, cmd.Execute(config))
+		_, ok := fake.secret("prod/token")
+		assert.False(t, ok)
+		assert.Zero(t, fake.updates)
+	})
+}
@@ -0,0 +1,30 @@
+//go:build !unix
+
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// Run command as child process and exit with its exit code, as exec is not supported
+func execProcess(path string, args []string, env []string) error {
+	child := exec.Command(path, args[1:]...)
+	child.Env = env
+	child.Stdin = os.Stdin
+	child.Stdout = os.Stdout
+	child.Stderr = os.Stderr
+
+	err := child.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		os.Exit(exitErr.ExitCode())
+	}
+	if err != nil {
+		return fmt.Errorf("can`t run command: %w", err)
+	}
+	os.Exit(0)
+	return nil
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package client
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// Replace current process with command, so child gets signals and exit code directly
+func execProcess(path string, args []string, env []string) error {
+	if err := syscall.Exec(path, args, env); err != nil {
+		return fmt.Errorf("can`t run command: %w", err)
+	}
+	return nil
+}
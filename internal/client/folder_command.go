@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+)
+
+// Command to list folder content
+type FolderListCommand struct {
+	path string
+}
+
+func NewFolderListCommand(args map[string]string) (*FolderListCommand, error) {
+	return &FolderListCommand{path: args["path"]}, nil
+}
+
+func (cmd *FolderListCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	folder, err := newAPIClient(config, token).ListFolder(context.Background(), cmd.path)
+	if err != nil {
+		return fmt.Errorf("can`t read folder. Reason: %w", err)
+	}
+
+	output := folderOutput{Path: folder.Path, Folders: folder.Folders, Secrets: make([]secretListItem, 0, len(folder.Secrets))}
+	for _, secret := range folder.Secrets {
+		output.Secrets = append(output.Secrets, secretListItem{Name: secret.Name, Type: secret.Type, Tags: secret.Tags})
+	}
+
+	return printOutput(config, output, func(w io.Writer) error {
+		fmt.Fprintf(w, "Folder: /%s\n", folder.Path)
+		for _, subfolder := range folder.Folders {
+			fmt.Fprintf(w, "  %s/\n", subfolder)
+		}
+		for _, secret := range folder.Secrets {
+			fmt.Fprintf(w, "  %s\t%s\n", path.Base(secret.Name), secret.Type)
+		}
+		return nil
+	})
+}
+
+// Folder content without secrets data
+type folderOutput struct {
+	Path    string           `json:"path"`
+	Folders []string         `json:"folders"`
+	Secrets []secretListItem `json:"secrets"`
+}
+
+// Fabric to create folder list command
+type FolderListCommandFactory struct{}
+
+func (f *FolderListCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewFolderListCommand(args)
+}
+
+// Command to move folder with all nested secrets
+type FolderMoveCommand struct {
+	from string
+	to   string
+}
+
+func NewFolderMoveCommand(args map[string]string) (*FolderMoveCommand, error) {
+	from, ok1 := args["from"]
+	to, ok2 := args["to"]
+	if !ok1 || !ok2 || from == "" || to == "" {
+		return nil, errors.New("source and target folders are required")
+	}
+
+	return &FolderMoveCommand{from: from, to: to}, nil
+}
+
+func (cmd *FolderMoveCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	err = newAPIClient(config, token).MoveFolder(context.Background(), cmd.from, cmd.to)
+	if err != nil {
+		return fmt.Errorf("can`t move folder. Reason: %w", err)
+	}
+
+	return printResult(config, commandResult{Status: "moved", Name: cmd.to},
+		fmt.Sprintf("Folder \"%s\" was moved to \"%s\"", cmd.from, cmd.to))
+}
+
+// Fabric to create folder move command
+type FolderMoveCommandFactory struct{}
+
+func (f *FolderMoveCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewFolderMoveCommand(args)
+}
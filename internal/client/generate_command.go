@@ -0,0 +1,150 @@
+package client
+
+import (
+	"fmt"
+	"github.com/desepticon55/gophkeeper/pkg/password"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Flags of password generator, common to generate command and credentials creation
+var PasswordFlags = []FlagDef{
+	{Name: "length", DefaultValue: "20", Description: "Generated password length"},
+	{Name: "charset", DefaultValue: "lower,upper,digits,symbols", Description: "Comma separated charsets of generated password: lower, upper, digits, symbols"},
+	{Name: "exclude-ambiguous", DefaultValue: "false", Description: "Exclude similar looking characters like 0/O and 1/l", Bool: true},
+	{Name: "diceware", DefaultValue: "false", Description: "Generate diceware passphrase instead of random password", Bool: true},
+	{Name: "words", DefaultValue: "6", Description: "Words count of diceware passphrase"},
+	{Name: "separator", DefaultValue: "-", Description: "Words separator of diceware passphrase"},
+}
+
+// Options of password generator filled by user
+type passwordOptions struct {
+	diceware   bool
+	policy     password.Policy
+	passphrase password.PassphrasePolicy
+}
+
+func parsePasswordOptions(args map[string]string) (passwordOptions, error) {
+	diceware, err := parseBoolFlag(args, "diceware")
+	if err != nil {
+		return passwordOptions{}, err
+	}
+	excludeAmbiguous, err := parseBoolFlag(args, "exclude-ambiguous")
+	if err != nil {
+		return passwordOptions{}, err
+	}
+	length, err := parsePositiveFlag(args, "length", 20)
+	if err != nil {
+		return passwordOptions{}, err
+	}
+	words, err := parsePositiveFlag(args, "words", 6)
+	if err != nil {
+		return passwordOptions{}, err
+	}
+
+	policy := password.Policy{Length: length, ExcludeAmbiguous: excludeAmbiguous}
+	charset := args["charset"]
+	if charset == "" {
+		charset = "lower,upper,digits,symbols"
+	}
+	for _, name := range strings.Split(charset, ",") {
+		switch strings.TrimSpace(name) {
+		case "lower":
+			policy.Lower = true
+		case "upper":
+			policy.Upper = true
+		case "digits":
+			policy.Digits = true
+		case "symbols":
+			policy.Symbols = true
+		default:
+			return passwordOptions{}, fmt.Errorf("unknown charset \"%s\", supported charsets: lower, upper, digits, symbols", name)
+		}
+	}
+
+	return passwordOptions{
+		diceware:   diceware,
+		policy:     policy,
+		passphrase: password.PassphrasePolicy{Words: words, Separator: args["separator"]},
+	}, nil
+}
+
+// Generate password or passphrase, returns it with entropy estimate in bits
+func (o passwordOptions) generate() (string, float64, error) {
+	if o.diceware {
+		return password.GeneratePassphrase(o.passphrase)
+	}
+	return password.Generate(o.policy)
+}
+
+func parsePositiveFlag(args map[string]string, name string, defaultValue int) (int, error) {
+	value := args[name]
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 1 {
+		return 0, fmt.Errorf("%s should be a positive number", name)
+	}
+	return parsed, nil
+}
+
+// Describe password strength by entropy in bits
+func entropyStrength(entropy float64) string {
+	switch {
+	case entropy < 40:
+		return "weak"
+	case entropy < 60:
+		return "fair"
+	case entropy < 80:
+		return "strong"
+	default:
+		return "very strong"
+	}
+}
+
+func printEntropy(w io.Writer, entropy float64) {
+	fmt.Fprintf(w, "Entropy: ~%.0f bits (%s)\n", entropy, entropyStrength(entropy))
+}
+
+// Generated password with its strength
+type generatedPassword struct {
+	Password string  `json:"password"`
+	Entropy  float64 `json:"entropy"`
+	Strength string  `json:"strength"`
+}
+
+// Command to generate random password or diceware passphrase without saving it
+type GenerateCommand struct {
+	options passwordOptions
+}
+
+func NewGenerateCommand(args map[string]string) (*GenerateCommand, error) {
+	options, err := parsePasswordOptions(args)
+	if err != nil {
+		return nil, err
+	}
+	return &GenerateCommand{options: options}, nil
+}
+
+func (cmd *GenerateCommand) Execute(config Config) error {
+	generated, entropy, err := cmd.options.generate()
+	if err != nil {
+		return fmt.Errorf("can`t generate password: %w", err)
+	}
+
+	result := generatedPassword{Password: generated, Entropy: entropy, Strength: entropyStrength(entropy)}
+	return printOutput(config, result, func(w io.Writer) error {
+		fmt.Fprintln(w, generated)
+		printEntropy(w, entropy)
+		return nil
+	})
+}
+
+// Fabric to create generate command
+type GenerateCommandFactory struct{}
+
+func (f *GenerateCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewGenerateCommand(args)
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestParsePasswordOptions(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      map[string]string
+		check     func(t *testing.T, generated string)
+		wantError bool
+	}{
+		{
+			name: "Default password",
+			args: map[string]string{},
+			check: func(t *testing.T, generated string) {
+				assert.Len(t, generated, 20)
+			},
+		},
+		{
+			name: "Digits only",
+			args: map[string]string{"length": "8", "charset": "digits"},
+			check: func(t *testing.T, generated string) {
+				assert.Len(t, generated, 8)
+				assert.Empty(t, strings.Trim(generated, "0123456789"))
+			},
+		},
+		{
+			name: "Diceware",
+			args: map[string]string{"diceware": "true", "words": "4", "separator": " "},
+			check: func(t *testing.T, generated string) {
+				assert.Len(t, strings.Split(generated, " "), 4)
+			},
+		},
+		{
+			name:      "Unknown charset",
+			args:      map[string]string{"charset": "lower,emoji"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid length",
+			args:      map[string]string{"length": "-1"},
+			wantError: true,
+		},
+		{
+			name:      "Invalid diceware flag",
+			args:      map[string]string{"diceware": "maybe"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			options, err := parsePasswordOptions(tc.args)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			generated, entropy, err := options.generate()
+			require.NoError(t, err)
+			assert.Positive(t, entropy)
+			tc.check(t, generated)
+		})
+	}
+}
+
+func TestNewSaveCredentialsCommand_Generate(t *testing.T) {
+	cmd, err := NewSaveCredentialsCommand(map[string]string{"name": "mail", "username": "user", "generate": "true", "length": "12"})
+	require.NoError(t, err)
+	assert.Len(t, cmd.password, 12)
+	assert.True(t, cmd.generated)
+
+	_, err = NewSaveCredentialsCommand(map[string]string{"name": "mail", "username": "user", "password": "secret", "generate": "true"})
+	assert.Error(t, err)
+
+	_, err = NewSaveCredentialsCommand(map[string]string{"name": "mail", "username": "user"})
+	assert.Error(t, err)
+}
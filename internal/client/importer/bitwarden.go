@@ -0,0 +1,130 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"io"
+	"strings"
+)
+
+// Types of Bitwarden items
+const (
+	bitwardenLoginType      = 1
+	bitwardenSecureNoteType = 2
+	bitwardenCardType       = 3
+)
+
+type bitwardenExport struct {
+	Encrypted bool `json:"encrypted"`
+	Folders   []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"folders"`
+	Items []bitwardenItem `json:"items"`
+}
+
+type bitwardenItem struct {
+	Type     int     `json:"type"`
+	Name     string  `json:"name"`
+	Notes    *string `json:"notes"`
+	FolderID *string `json:"folderId"`
+	Login    *struct {
+		Username *string `json:"username"`
+		Password *string `json:"password"`
+		Totp     *string `json:"totp"`
+		URIs     []struct {
+			URI string `json:"uri"`
+		} `json:"uris"`
+	} `json:"login"`
+	Card *struct {
+		CardholderName *string `json:"cardholderName"`
+		Number         *string `json:"number"`
+		ExpMonth       *string `json:"expMonth"`
+		ExpYear        *string `json:"expYear"`
+		Code           *string `json:"code"`
+	} `json:"card"`
+	Fields []struct {
+		Name string `json:"name"`
+	} `json:"fields"`
+}
+
+// Parse unencrypted Bitwarden JSON export. Nested folders use '/' in names, so they are kept as is
+func parseBitwardenJSON(reader io.Reader, builder *resultBuilder) error {
+	var export bitwardenExport
+	if err := json.NewDecoder(reader).Decode(&export); err != nil {
+		return err
+	}
+	if export.Encrypted {
+		return errors.New("encrypted export is not supported, export vault in unencrypted JSON format")
+	}
+
+	folders := make(map[string][]string)
+	for _, folder := range export.Folders {
+		folders[folder.ID] = strings.Split(folder.Name, "/")
+	}
+
+	for _, item := range export.Items {
+		e := entry{title: item.Name, notes: value(item.Notes)}
+		if item.FolderID != nil {
+			e.folder = folders[*item.FolderID]
+		}
+		for _, field := range item.Fields {
+			e.lostFields = append(e.lostFields, field.Name)
+		}
+
+		switch item.Type {
+		case bitwardenLoginType:
+			if item.Login != nil {
+				e.username = value(item.Login.Username)
+				e.password = value(item.Login.Password)
+				if len(item.Login.URIs) > 0 {
+					e.url = item.Login.URIs[0].URI
+				}
+				if value(item.Login.Totp) != "" {
+					e.lostFields = append(e.lostFields, "totp")
+				}
+			}
+		case bitwardenSecureNoteType:
+		case bitwardenCardType:
+			if item.Card != nil {
+				e.card = &model.Card{
+					Number: value(item.Card.Number),
+					Date:   cardDate(value(item.Card.ExpMonth), value(item.Card.ExpYear)),
+					Code:   value(item.Card.Code),
+					Holder: value(item.Card.CardholderName),
+				}
+			}
+		default:
+			builder.skip(item.Name, fmt.Sprintf("unsupported item type %d", item.Type))
+			continue
+		}
+
+		if err := builder.add(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func value(pointer *string) string {
+	if pointer == nil {
+		return ""
+	}
+	return *pointer
+}
+
+// Card expire date in MM/YY format
+func cardDate(month string, year string) string {
+	if month == "" && year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 4 {
+		year = year[2:]
+	}
+	return month + "/" + year
+}
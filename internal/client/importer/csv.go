@@ -0,0 +1,107 @@
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+// Column names of 1Password CSV export, different versions use different names
+var onePasswordColumns = map[string][]string{
+	"title":    {"title", "name"},
+	"url":      {"url", "website", "urls"},
+	"username": {"username"},
+	"password": {"password"},
+	"notes":    {"notes", "notesplain"},
+	"tags":     {"tags"},
+	"otp":      {"otpauth", "one-time password"},
+	"archived": {"archived"},
+}
+
+var chromeColumns = map[string][]string{
+	"title":    {"name"},
+	"url":      {"url"},
+	"username": {"username"},
+	"password": {"password"},
+	"notes":    {"note"},
+}
+
+// Parse 1Password CSV export, archived items are skipped
+func parseOnePasswordCSV(reader io.Reader, builder *resultBuilder) error {
+	return parseCSV(reader, onePasswordColumns, builder)
+}
+
+// Parse Chrome passwords CSV export
+func parseChromeCSV(reader io.Reader, builder *resultBuilder) error {
+	return parseCSV(reader, chromeColumns, builder)
+}
+
+func parseCSV(reader io.Reader, columns map[string][]string, builder *resultBuilder) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return err
+	}
+	indexes := columnIndexes(header, columns)
+	if _, ok := indexes["password"]; !ok {
+		return errors.New("password column was not found")
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		field := func(name string) string {
+			index, ok := indexes[name]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return record[index]
+		}
+
+		e := entry{
+			title:    field("title"),
+			username: field("username"),
+			password: field("password"),
+			url:      field("url"),
+			notes:    field("notes"),
+			tags:     splitTags(field("tags")),
+		}
+		if strings.EqualFold(field("archived"), "true") {
+			builder.skip(entryTitle(e), "entry is archived")
+			continue
+		}
+		if field("otp") != "" {
+			e.lostFields = append(e.lostFields, "otp")
+		}
+
+		if err := builder.add(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Find indexes of known columns by case insensitive names
+func columnIndexes(header []string, columns map[string][]string) map[string]int {
+	indexes := make(map[string]int)
+	for i, column := range header {
+		column = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, "\ufeff")))
+		for name, aliases := range columns {
+			for _, alias := range aliases {
+				if _, found := indexes[name]; !found && column == alias {
+					indexes[name] = i
+				}
+			}
+		}
+	}
+	return indexes
+}
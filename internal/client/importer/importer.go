@@ -0,0 +1,172 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"io"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	KeePassXMLFormat     = "keepass-xml"
+	BitwardenJSONFormat  = "bitwarden-json"
+	OnePasswordCSVFormat = "1password-csv"
+	ChromeCSVFormat      = "chrome-csv"
+)
+
+// Supported formats of exports
+var Formats = []string{KeePassXMLFormat, BitwardenJSONFormat, OnePasswordCSVFormat, ChromeCSVFormat}
+
+// Result of import. Content and notes of secrets are not encrypted
+type Result struct {
+	Secrets []model.Secret
+	// Entries which were not imported, with reasons
+	Skipped []string
+	// Data of imported entries which was lost, e.g. custom fields
+	Warnings []string
+}
+
+// Parse export of password manager in format
+func Parse(format string, reader io.Reader) (Result, error) {
+	builder := newResultBuilder()
+	var err error
+	switch format {
+	case KeePassXMLFormat:
+		err = parseKeePassXML(reader, builder)
+	case BitwardenJSONFormat:
+		err = parseBitwardenJSON(reader, builder)
+	case OnePasswordCSVFormat:
+		err = parseOnePasswordCSV(reader, builder)
+	case ChromeCSVFormat:
+		err = parseChromeCSV(reader, builder)
+	default:
+		return Result{}, fmt.Errorf("unknown import format \"%s\", supported formats: %s", format, strings.Join(Formats, ", "))
+	}
+	if err != nil {
+		return Result{}, fmt.Errorf("error during parse %s: %w", format, err)
+	}
+	return builder.result, nil
+}
+
+// Entry of password manager
+type entry struct {
+	folder   []string
+	title    string
+	username string
+	password string
+	url      string
+	notes    string
+	tags     []string
+	card     *model.Card
+	// Names of fields which can not be stored in gophkeeper secret
+	lostFields []string
+}
+
+// Collect secrets with unique names
+type resultBuilder struct {
+	result Result
+	names  map[string]bool
+}
+
+func newResultBuilder() *resultBuilder {
+	return &resultBuilder{names: make(map[string]bool)}
+}
+
+func (b *resultBuilder) skip(title string, reason string) {
+	b.result.Skipped = append(b.result.Skipped, fmt.Sprintf("%s: %s", title, reason))
+}
+
+// Map entry to CARD, CREDENTIALS or TEXT secret
+func (b *resultBuilder) add(e entry) error {
+	secret := model.Secret{URL: e.url, Tags: e.tags}
+	switch {
+	case e.card != nil:
+		content, err := json.Marshal(e.card)
+		if err != nil {
+			return err
+		}
+		secret.Type = model.CardSecretType
+		secret.Content = content
+	case e.username != "" || e.password != "":
+		// Content of credentials is split by the first separator, so it can't keep username with it
+		if strings.Contains(e.username, ":") {
+			b.skip(entryTitle(e), "username contains \":\"")
+			return nil
+		}
+		secret.Type = model.CredentialsSecretType
+		secret.Content = []byte(e.username + ":" + e.password)
+	case e.notes != "":
+		secret.Type = model.TextSecretType
+		secret.Content = []byte(e.notes)
+		e.notes = ""
+	default:
+		b.skip(entryTitle(e), "entry has no data")
+		return nil
+	}
+
+	if e.notes != "" {
+		secret.Notes = []byte(e.notes)
+	}
+	secret.Name = b.uniqueName(e)
+	if utf8.RuneCountInString(secret.Name) > model.MaxSecretNameLength {
+		b.skip(entryTitle(e), fmt.Sprintf("name is longer than %d characters", model.MaxSecretNameLength))
+		return nil
+	}
+	for _, field := range e.lostFields {
+		b.result.Warnings = append(b.result.Warnings, fmt.Sprintf("%s: field \"%s\" was not imported", secret.Name, field))
+	}
+	b.result.Secrets = append(b.result.Secrets, secret)
+	return nil
+}
+
+// Build secret name from folder path and title. Duplicates get numeric suffix
+func (b *resultBuilder) uniqueName(e entry) string {
+	var segments []string
+	for _, segment := range append(append([]string{}, e.folder...), entryTitle(e)) {
+		if segment = cleanSegment(segment); segment != "" {
+			segments = append(segments, segment)
+		}
+	}
+	name := strings.Join(segments, model.SecretPathSeparator)
+
+	unique := name
+	for i := 2; b.names[unique]; i++ {
+		unique = fmt.Sprintf("%s (%d)", name, i)
+	}
+	b.names[unique] = true
+	return unique
+}
+
+// Title of entry, host of URL is used for entries without title
+func entryTitle(e entry) string {
+	if strings.TrimSpace(e.title) != "" {
+		return e.title
+	}
+	if parsed, err := url.Parse(e.url); err == nil && parsed.Host != "" {
+		return parsed.Host
+	}
+	return "untitled"
+}
+
+// Make path segment of secret name from arbitrary text
+func cleanSegment(segment string) string {
+	segment = strings.TrimSpace(strings.ReplaceAll(segment, model.SecretPathSeparator, "-"))
+	if segment == "." || segment == ".." {
+		return ""
+	}
+	return segment
+}
+
+// Split tags separated by comma or semicolon
+func splitTags(value string) []string {
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ';' }) {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
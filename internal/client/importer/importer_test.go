@@ -0,0 +1,179 @@
+package importer
+
+import (
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const keePassXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinUUID>bin</RecycleBinUUID>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>root</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<Tags>work;mail</Tags>
+				<String><Key>Title</Key><Value>Mail</Value></String>
+				<String><Key>UserName</Key><Value>user@mail.com</Value></String>
+				<String><Key>Password</Key><Value ProtectInMemory="True">secret</Value></String>
+				<String><Key>URL</Key><Value>https://mail.example.com</Value></String>
+				<String><Key>Notes</Key><Value>main account</Value></String>
+				<String><Key>Recovery code</Key><Value>1234</Value></String>
+			</Entry>
+			<Group>
+				<UUID>prod</UUID>
+				<Name>prod</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>db/postgres</Value></String>
+					<String><Key>UserName</Key><Value>admin</Value></String>
+					<String><Key>Password</Key><Value>pwd</Value></String>
+				</Entry>
+				<Entry>
+					<String><Key>Title</Key><Value>Runbook</Value></String>
+					<String><Key>Notes</Key><Value>restart everything</Value></String>
+				</Entry>
+			</Group>
+			<Group>
+				<UUID>bin</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Old</Value></String>
+					<String><Key>Password</Key><Value>old</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+const bitwardenJSON = `{
+	"encrypted": false,
+	"folders": [{"id": "f1", "name": "prod/db"}],
+	"items": [
+		{
+			"type": 1, "name": "postgres", "notes": "primary", "folderId": "f1",
+			"login": {"username": "admin", "password": "pwd", "totp": "JBSWY3DPEHPK3PXP", "uris": [{"uri": "https://db.example.com"}]},
+			"fields": [{"name": "port", "value": "5432"}]
+		},
+		{"type": 2, "name": "Wi-Fi", "notes": "password is 12345678", "folderId": null},
+		{
+			"type": 3, "name": "Visa", "folderId": null,
+			"card": {"cardholderName": "IVAN IVANOV", "number": "4242424242424242", "expMonth": "1", "expYear": "2027", "code": "123"}
+		},
+		{"type": 4, "name": "Passport", "folderId": null}
+	]
+}`
+
+const onePasswordCSV = "Title,Url,Username,Password,OTPAuth,Favorite,Archived,Tags,Notes\n" +
+	"GitHub,https://github.com,octocat,pwd,otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP,false,false,\"dev,work\",personal\n" +
+	"Old,https://old.example.com,user,old,,false,true,,\n" +
+	"GitHub,https://github.com,bot,token,,false,false,,\n"
+
+const chromeCSV = "name,url,username,password,note\n" +
+	"example.com,https://example.com/login,user,pwd,\n" +
+	",https://shop.example.com/,buyer,pwd2,gift card inside\n" +
+	"ldap,https://ldap.example.com,cn=admin:corp,pwd3,\n" +
+	longName + ",https://long.example.com,user,pwd4,\n"
+
+// Name of 256 characters, longer than secret name limit
+const longName = "long" + "longlonglonglonglonglonglonglonglonglonglonglonglonglonglonglong" +
+	"longlonglonglonglonglonglonglonglonglonglonglonglonglonglonglong" +
+	"longlonglonglonglonglonglonglonglonglonglonglonglonglonglonglong" +
+	"longlonglonglonglonglonglonglonglonglonglonglonglonglonglong"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name             string
+		format           string
+		data             string
+		expectedSecrets  []model.Secret
+		expectedSkipped  []string
+		expectedWarnings []string
+	}{
+		{
+			name:   "KeePass XML",
+			format: KeePassXMLFormat,
+			data:   keePassXML,
+			expectedSecrets: []model.Secret{
+				{Name: "Mail", Type: model.CredentialsSecretType, Content: []byte("user@mail.com:secret"), Notes: []byte("main account"), Tags: []string{"work", "mail"}, URL: "https://mail.example.com"},
+				{Name: "prod/db-postgres", Type: model.CredentialsSecretType, Content: []byte("admin:pwd")},
+				{Name: "prod/Runbook", Type: model.TextSecretType, Content: []byte("restart everything")},
+			},
+			expectedSkipped:  []string{"Old: entry is in recycle bin"},
+			expectedWarnings: []string{"Mail: field \"Recovery code\" was not imported"},
+		},
+		{
+			name:   "Bitwarden JSON",
+			format: BitwardenJSONFormat,
+			data:   bitwardenJSON,
+			expectedSecrets: []model.Secret{
+				{Name: "prod/db/postgres", Type: model.CredentialsSecretType, Content: []byte("admin:pwd"), Notes: []byte("primary"), URL: "https://db.example.com"},
+				{Name: "Wi-Fi", Type: model.TextSecretType, Content: []byte("password is 12345678")},
+				{Name: "Visa", Type: model.CardSecretType, Content: []byte(`{"number":"4242424242424242","date":"01/27","code":"123","holder":"IVAN IVANOV"}`)},
+			},
+			expectedSkipped: []string{"Passport: unsupported item type 4"},
+			expectedWarnings: []string{
+				"prod/db/postgres: field \"port\" was not imported",
+				"prod/db/postgres: field \"totp\" was not imported",
+			},
+		},
+		{
+			name:   "1Password CSV",
+			format: OnePasswordCSVFormat,
+			data:   onePasswordCSV,
+			expectedSecrets: []model.Secret{
+				{Name: "GitHub", Type: model.CredentialsSecretType, Content: []byte("octocat:pwd"), Notes: []byte("personal"), Tags: []string{"dev", "work"}, URL: "https://github.com"},
+				{Name: "GitHub (2)", Type: model.CredentialsSecretType, Content: []byte("bot:token"), URL: "https://github.com"},
+			},
+			expectedSkipped:  []string{"Old: entry is archived"},
+			expectedWarnings: []string{"GitHub: field \"otp\" was not imported"},
+		},
+		{
+			name:   "Chrome CSV",
+			format: ChromeCSVFormat,
+			data:   chromeCSV,
+			expectedSecrets: []model.Secret{
+				{Name: "example.com", Type: model.CredentialsSecretType, Content: []byte("user:pwd"), URL: "https://example.com/login"},
+				{Name: "shop.example.com", Type: model.CredentialsSecretType, Content: []byte("buyer:pwd2"), Notes: []byte("gift card inside"), URL: "https://shop.example.com/"},
+			},
+			expectedSkipped: []string{
+				"ldap: username contains \":\"",
+				longName + ": name is longer than 255 characters",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.format, strings.NewReader(tt.data))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedSecrets, result.Secrets)
+			assert.Equal(t, tt.expectedSkipped, result.Skipped)
+			assert.Equal(t, tt.expectedWarnings, result.Warnings)
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		data   string
+	}{
+		{name: "Unknown format", format: "lastpass-csv", data: ""},
+		{name: "Encrypted Bitwarden export", format: BitwardenJSONFormat, data: `{"encrypted": true, "items": []}`},
+		{name: "CSV without password column", format: ChromeCSVFormat, data: "name,url\nexample,https://example.com\n"},
+		{name: "Invalid XML", format: KeePassXMLFormat, data: "<KeePassFile>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.format, strings.NewReader(tt.data))
+			assert.Error(t, err)
+		})
+	}
+}
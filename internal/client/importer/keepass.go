@@ -0,0 +1,88 @@
+package importer
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// Standard fields of KeePass entry, other fields are custom
+var keePassStandardFields = map[string]bool{"Title": true, "UserName": true, "Password": true, "URL": true, "Notes": true}
+
+type keePassFile struct {
+	Meta struct {
+		RecycleBinUUID string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Groups []keePassGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type keePassGroup struct {
+	UUID    string         `xml:"UUID"`
+	Name    string         `xml:"Name"`
+	Entries []keePassEntry `xml:"Entry"`
+	Groups  []keePassGroup `xml:"Group"`
+}
+
+type keePassEntry struct {
+	Tags    string `xml:"Tags"`
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value string `xml:"Value"`
+	} `xml:"String"`
+}
+
+// Parse KeePass 2.x XML export. Groups below the root group become folders, recycle bin is skipped
+func parseKeePassXML(reader io.Reader, builder *resultBuilder) error {
+	var file keePassFile
+	if err := xml.NewDecoder(reader).Decode(&file); err != nil {
+		return err
+	}
+
+	for _, root := range file.Root.Groups {
+		if err := addKeePassGroup(root, nil, file.Meta.RecycleBinUUID, false, builder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func addKeePassGroup(group keePassGroup, folder []string, recycleBinUUID string, deleted bool, builder *resultBuilder) error {
+	deleted = deleted || (recycleBinUUID != "" && group.UUID == recycleBinUUID)
+	for _, keePassEntry := range group.Entries {
+		e := entry{folder: folder, tags: splitTags(keePassEntry.Tags)}
+		for _, field := range keePassEntry.Strings {
+			switch field.Key {
+			case "Title":
+				e.title = field.Value
+			case "UserName":
+				e.username = field.Value
+			case "Password":
+				e.password = field.Value
+			case "URL":
+				e.url = field.Value
+			case "Notes":
+				e.notes = field.Value
+			}
+			if !keePassStandardFields[field.Key] && field.Value != "" {
+				e.lostFields = append(e.lostFields, field.Key)
+			}
+		}
+
+		if deleted {
+			builder.skip(entryTitle(e), "entry is in recycle bin")
+			continue
+		}
+		if err := builder.add(e); err != nil {
+			return err
+		}
+	}
+
+	for _, subgroup := range group.Groups {
+		subfolder := append(append([]string{}, folder...), subgroup.Name)
+		if err := addKeePassGroup(subgroup, subfolder, recycleBinUUID, deleted, builder); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -5,10 +5,11 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 )
 
-func saveTokenToFile(token string) error {
-	filePath := "tokens.json"
+// Save token of profile to file readable by owner only
+func saveTokenToFile(config Config, token string) error {
 	tokenData := map[string]string{
 		"token": token,
 	}
@@ -17,7 +18,10 @@ func saveTokenToFile(token string) error {
 		return fmt.Errorf("error marshaling token data: %w", err)
 	}
 
-	err = os.WriteFile(filePath, fileContent, 0644)
+	if err := os.MkdirAll(filepath.Dir(config.TokenFile), 0700); err != nil {
+		return fmt.Errorf("error creating token dir: %w", err)
+	}
+	err = writeFileAtomic(config.TokenFile, fileContent)
 	if err != nil {
 		return fmt.Errorf("error writing token to file: %w", err)
 	}
@@ -25,9 +29,8 @@ func saveTokenToFile(token string) error {
 	return nil
 }
 
-func readTokenFromFile() (string, error) {
-	filePath := "tokens.json"
-	data, err := os.ReadFile(filePath)
+func readTokenFromFile(config Config) (string, error) {
+	data, err := os.ReadFile(config.TokenFile)
 	if err != nil {
 		return "", fmt.Errorf("error reading token from file: %w", err)
 	}
@@ -45,3 +48,16 @@ func readTokenFromFile() (string, error) {
 
 	return token, nil
 }
+
+// Read token held by unlocked agent, or from file of profile when agent is not used.
+// Profile with client certificate can work without token, user is authenticated by certificate
+func readToken(config Config) (string, error) {
+	if config.token != "" {
+		return config.token, nil
+	}
+	token, err := readTokenFromFile(config)
+	if errors.Is(err, os.ErrNotExist) && config.ClientCertFile != "" {
+		return "", nil
+	}
+	return token, err
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Message authenticated by key derived from master password
+const keyVerifierMessage = "gophkeeper key verifier"
+
+var errWrongMasterPassword = errors.New("wrong master password")
+
+// Verifiers of keys are stored next to token of profile, one verifier per user
+func keyVerifierFilePath(config Config) string {
+	return strings.TrimSuffix(config.TokenFile, filepath.Ext(config.TokenFile)) + ".verifier.json"
+}
+
+// HMAC of known message under key. Decryption does not authenticate data, so wrong key is detected by verifier only
+func keyVerifier(key []byte) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(keyVerifierMessage))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Check key of user by stored verifier. Verifier is saved on first check of user and true is returned
+func checkKeyVerifier(path string, username string, key []byte) (bool, error) {
+	verifiers := make(map[string]string)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, fmt.Errorf("error during read key verifier: %w", err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &verifiers); err != nil {
+			return false, fmt.Errorf("error during parse key verifier: %w", err)
+		}
+	}
+
+	expected := keyVerifier(key)
+	if stored, ok := verifiers[username]; ok {
+		if !hmac.Equal([]byte(stored), []byte(expected)) {
+			return false, errWrongMasterPassword
+		}
+		return false, nil
+	}
+
+	verifiers[username] = expected
+	data, err = json.MarshalIndent(verifiers, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("error during marshal key verifier: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return false, fmt.Errorf("error during create key verifier dir: %w", err)
+	}
+	if err := writeFileAtomic(path, data); err != nil {
+		return false, fmt.Errorf("error during write key verifier: %w", err)
+	}
+	return true, nil
+}
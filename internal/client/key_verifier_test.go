@@ -0,0 +1,39 @@
+package client
+
+import (
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCheckKeyVerifier(t *testing.T) {
+	config := Config{TokenFile: filepath.Join(t.TempDir(), "tokens", "default.json")}
+	path := keyVerifierFilePath(config)
+	assert.Equal(t, filepath.Join(filepath.Dir(config.TokenFile), "default.verifier.json"), path)
+
+	key := crypto.DeriveKey([]byte("master"), masterKeySalt("ivan"))
+	created, err := checkKeyVerifier(path, "ivan", key)
+	require.NoError(t, err)
+	assert.True(t, created)
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	created, err = checkKeyVerifier(path, "ivan", key)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	wrongKey := crypto.DeriveKey([]byte("mastr"), masterKeySalt("ivan"))
+	_, err = checkKeyVerifier(path, "ivan", wrongKey)
+	assert.ErrorIs(t, err, errWrongMasterPassword)
+
+	otherKey := crypto.DeriveKey([]byte("other"), masterKeySalt("petr"))
+	created, err = checkKeyVerifier(path, "petr", otherKey)
+	require.NoError(t, err)
+	assert.True(t, created)
+	_, err = checkKeyVerifier(path, "ivan", key)
+	assert.NoError(t, err)
+}
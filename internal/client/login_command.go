@@ -1,11 +1,10 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
-	"github.com/go-resty/resty/v2"
-	"time"
 )
 
 // Command to login
@@ -15,49 +14,40 @@ type UserLoginCommand struct {
 }
 
 func NewUserLoginCommand(args map[string]string) (*UserLoginCommand, error) {
-	username, ok1 := args["username"]
-	password, ok2 := args["password"]
-	if !ok1 || !ok2 || username == "" || password == "" {
+	password, ok := args["password"]
+	if !ok || password == "" {
 		return nil, errors.New("username and password are required")
 	}
 
-	return &UserLoginCommand{username: username, password: password}, nil
+	return &UserLoginCommand{username: args["username"], password: password}, nil
 }
 
 func (cmd *UserLoginCommand) Execute(config Config) error {
+	// user name can be omitted when it is set in profile
+	username := firstNonEmpty(cmd.username, config.Username)
+	if username == "" {
+		return errors.New("username and password are required")
+	}
+
 	loginPayload := &model.User{
-		Username: cmd.username,
+		Username: username,
 		Password: cmd.password,
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(loginPayload).
-		Post(config.ServerAddress + "/api/user/login")
-
+	token, err := newAPIClient(config, "").Login(context.Background(), *loginPayload)
 	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
+		return fmt.Errorf("error during execute command. Reason: %w", err)
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("error during execute command. Reason: %s", resp.String())
-	}
-
-	authHeader := resp.Header().Get("Authorization")
-	if authHeader == "" {
-		return errors.New("authorization token missing from response")
-	}
-
-	token := authHeader[len("Bearer "):]
-	err = saveTokenToFile(token)
+	err = saveTokenToFile(config, token)
 	if err != nil {
 		return fmt.Errorf("failed to save token to file: %w", err)
 	}
+	if err := updateAgentToken(config, token); err != nil {
+		return fmt.Errorf("failed to pass token to agent: %w", err)
+	}
 
-	fmt.Println("User logged in successfully")
-	return nil
+	return printResult(config, commandResult{Status: "logged_in"}, "User logged in successfully")
 }
 
 // Fabric to create user login command
@@ -0,0 +1,162 @@
+package client
+
+import (
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Flags with secret metadata, common to all create commands
+var MetadataFlags = []FlagDef{
+	{Name: "tag", Description: "Secret tag, can be repeated or comma separated", Repeated: true},
+	{Name: "note", DefaultValue: "", Description: "Secret note"},
+	{Name: "url", DefaultValue: "", Description: "Secret URL"},
+	{Name: "expires-at", DefaultValue: "", Description: "Secret expiry date (YYYY-MM-DD) or duration from now (e.g. 365d)"},
+	{Name: "rotate-after", DefaultValue: "", Description: "Secret rotation date (YYYY-MM-DD) or duration from now (e.g. 90d)"},
+	{Name: "burn-after-reading", DefaultValue: "false", Description: "Delete secret on server after the first read", Bool: true},
+}
+
+// Secret metadata filled by user
+type secretMetadata struct {
+	tags             []string
+	note             string
+	url              string
+	expiresAt        *time.Time
+	rotateAfter      *time.Time
+	burnAfterReading bool
+}
+
+func parseSecretMetadata(args map[string]string) (secretMetadata, error) {
+	now := time.Now()
+	expiresAt, err := parseMoment(args["expires-at"], now)
+	if err != nil {
+		return secretMetadata{}, fmt.Errorf("invalid expires-at: %w", err)
+	}
+	rotateAfter, err := parseMoment(args["rotate-after"], now)
+	if err != nil {
+		return secretMetadata{}, fmt.Errorf("invalid rotate-after: %w", err)
+	}
+	burnAfterReading, err := parseBoolFlag(args, "burn-after-reading")
+	if err != nil {
+		return secretMetadata{}, err
+	}
+
+	return secretMetadata{
+		tags:             parseTags(splitRepeated(args["tag"])),
+		note:             args["note"],
+		url:              args["url"],
+		expiresAt:        expiresAt,
+		rotateAfter:      rotateAfter,
+		burnAfterReading: burnAfterReading,
+	}, nil
+}
+
+// Fill secret metadata, note is encrypted with the same key as content
+func (m secretMetadata) applyTo(secret *model.Secret, key []byte) error {
+	if m.note != "" {
+		encryptedNote, err := crypto.EncryptData([]byte(m.note), key)
+		if err != nil {
+			return fmt.Errorf("error during encrypt note: %w", err)
+		}
+		secret.Notes = encryptedNote
+	}
+	secret.Tags = m.tags
+	secret.URL = m.url
+	secret.ExpiresAt = m.expiresAt
+	secret.RotateAfter = m.rotateAfter
+	secret.BurnAfterReading = m.burnAfterReading
+	return nil
+}
+
+// Parse date in YYYY-MM-DD or RFC 3339 format, or duration from now. Empty value means no date
+func parseMoment(value string, now time.Time) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+
+	if duration, err := parseDuration(value); err == nil {
+		moment := now.Add(duration)
+		return &moment, nil
+	}
+	if moment, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return &moment, nil
+	}
+	moment, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, fmt.Errorf("\"%s\" is neither a date nor a duration", value)
+	}
+	return &moment, nil
+}
+
+// Parse duration, in addition to time.ParseDuration units days are supported with 'd' suffix
+func parseDuration(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		count, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration \"%s\"", value)
+		}
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// Split comma separated tags, trim and remove duplicates
+func parseTags(values []string) []string {
+	var tags []string
+	seen := make(map[string]bool)
+	for _, value := range values {
+		for _, tag := range strings.Split(value, ",") {
+			tag = strings.TrimSpace(tag)
+			if tag == "" || seen[tag] {
+				continue
+			}
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func printSecretMetadata(w io.Writer, secret *model.Secret, key []byte) error {
+	if len(secret.Notes) > 0 {
+		note, err := crypto.DecryptData(secret.Notes, key)
+		if err != nil {
+			return fmt.Errorf("error during decrypt note: %w", err)
+		}
+		fmt.Fprintf(w, "Note: %s\n", note)
+	}
+	if len(secret.Tags) > 0 {
+		fmt.Fprintf(w, "Tags: %s\n", strings.Join(secret.Tags, ", "))
+	}
+	if secret.URL != "" {
+		fmt.Fprintf(w, "URL: %s\n", secret.URL)
+	}
+	printTime(w, "Expires at", secret.ExpiresAt)
+	printTime(w, "Rotate after", secret.RotateAfter)
+	if secret.BurnAfterReading {
+		fmt.Fprintln(w, "Burn after reading: secret was deleted from server")
+	}
+	printTime(w, "Created at", secret.CreatedAt)
+	printTime(w, "Updated at", secret.UpdatedAt)
+	printTime(w, "Last accessed at", secret.LastAccessedAt)
+	printDueWarning(w, secret, time.Now())
+	return nil
+}
+
+func printDueWarning(w io.Writer, secret *model.Secret, now time.Time) {
+	if secret.ExpiresAt != nil && secret.ExpiresAt.Before(now) {
+		fmt.Fprintln(w, "Warning: secret is expired")
+	} else if secret.RotateAfter != nil && secret.RotateAfter.Before(now) {
+		fmt.Fprintln(w, "Warning: secret should be rotated")
+	}
+}
+
+func printTime(w io.Writer, label string, value *time.Time) {
+	if value != nil {
+		fmt.Fprintf(w, "%s: %s\n", label, value.Local().Format(time.DateTime))
+	}
+}
@@ -0,0 +1,122 @@
+package client
+
+import (
+	"bytes"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestParseSecretMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     map[string]string
+		expected secretMetadata
+	}{
+		{
+			name: "Lifetime metadata",
+			args: map[string]string{
+				"expires-at":         "2030-01-02T10:00:00Z",
+				"burn-after-reading": "true",
+			},
+			expected: secretMetadata{
+				expiresAt:        timePointer(time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC)),
+				burnAfterReading: true,
+			},
+		},
+		{
+			name:     "Empty metadata",
+			args:     map[string]string{},
+			expected: secretMetadata{},
+		},
+		{
+			name: "Repeated and comma separated tags",
+			args: map[string]string{
+				"tag":  "work, mail" + repeatedFlagSeparator + "mail" + repeatedFlagSeparator + " ",
+				"note": "some note",
+				"url":  "https://mail.example.com",
+			},
+			expected: secretMetadata{
+				tags: []string{"work", "mail"},
+				note: "some note",
+				url:  "https://mail.example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metadata, err := parseSecretMetadata(tt.args)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, metadata)
+		})
+	}
+}
+
+func TestParseSecretMetadata_Invalid(t *testing.T) {
+	for _, args := range []map[string]string{
+		{"expires-at": "tomorrow"},
+		{"rotate-after": "90x"},
+		{"burn-after-reading": "maybe"},
+	} {
+		_, err := parseSecretMetadata(args)
+		assert.Error(t, err)
+	}
+}
+
+func TestParseMoment(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected *time.Time
+	}{
+		{value: "", expected: nil},
+		{value: "90d", expected: timePointer(now.Add(90 * 24 * time.Hour))},
+		{value: "36h", expected: timePointer(now.Add(36 * time.Hour))},
+		{value: "2025-01-31", expected: timePointer(time.Date(2025, 1, 31, 0, 0, 0, 0, time.Local))},
+		{value: "2025-01-31T10:00:00Z", expected: timePointer(time.Date(2025, 1, 31, 10, 0, 0, 0, time.UTC))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			moment, err := parseMoment(tt.value, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, moment)
+		})
+	}
+}
+
+func timePointer(value time.Time) *time.Time {
+	return &value
+}
+
+func TestSecretMetadata_ApplyTo(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	metadata := secretMetadata{tags: []string{"work"}, note: "some note", url: "https://example.com"}
+
+	secret := &model.Secret{Name: "test"}
+	err := metadata.applyTo(secret, key)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"work"}, secret.Tags)
+	assert.Equal(t, "https://example.com", secret.URL)
+
+	note, err := crypto.DecryptData(secret.Notes, key)
+	assert.NoError(t, err)
+	assert.Equal(t, "some note", string(note))
+}
+
+func TestPrintSecretMetadata(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+	notes, err := crypto.EncryptData([]byte("primary"), key)
+	assert.NoError(t, err)
+	expiresAt := time.Now().Add(-time.Hour)
+	secret := model.Secret{Notes: notes, Tags: []string{"prod", "db"}, URL: "postgres://db", ExpiresAt: &expiresAt}
+
+	var output bytes.Buffer
+	assert.NoError(t, printSecretMetadata(&output, &secret, key))
+	assert.Equal(t, "Note: primary\nTags: prod, db\nURL: postgres://db\n"+
+		"Expires at: "+expiresAt.Local().Format(time.DateTime)+"\nWarning: secret is expired\n", output.String())
+}
@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+	"github.com/desepticon55/gophkeeper/pkg/otp"
+	"io"
+	"time"
+)
+
+// Attempts to save HOTP counter changed by concurrent requests
+const maxOTPCounterAttempts = 3
+
+// Command to print current one-time code of OTP secret. Codes are computed on client,
+// counter of HOTP secret is incremented on server before each code is printed
+type OTPCommand struct {
+	secretName string
+}
+
+func NewOTPCommand(args map[string]string) (*OTPCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name is required")
+	}
+
+	return &OTPCommand{secretName: secretName}, nil
+}
+
+func (cmd *OTPCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := newAPIClient(config, token)
+	for attempt := 1; ; attempt++ {
+		err := cmd.printCode(api, config)
+		if !errors.Is(err, gophkeeperclient.ErrConflict) || attempt == maxOTPCounterAttempts {
+			return err
+		}
+	}
+}
+
+// Print current code. Counter of HOTP secret is saved before code is printed, so printed code is never reused.
+// ErrConflict is returned when counter was changed by concurrent request
+func (cmd *OTPCommand) printCode(api *apiClient, config Config) error {
+	secret, err := api.FindSecret(context.Background(), cmd.secretName)
+	if err != nil {
+		return err
+	}
+	if secret.Type != model.OTPSecretType {
+		return fmt.Errorf("secret %s has type %s, OTP secret is expected", secret.Name, secret.Type)
+	}
+
+	key, err := decryptOTPKey(secret, []byte(config.EncryptionKey))
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	code, err := key.Code(now)
+	if err != nil {
+		return fmt.Errorf("can`t generate code: %w", err)
+	}
+
+	if key.Type == otp.TOTPType {
+		remaining := int(key.Remaining(now).Seconds())
+		return printOutput(config, otpCode{Code: code, RemainingSeconds: remaining}, func(w io.Writer) error {
+			_, err := fmt.Fprintf(w, "Code: %s\nSeconds remaining: %d\n", code, remaining)
+			return err
+		})
+	}
+
+	counter := key.Counter
+	if !secret.BurnAfterReading {
+		key.Counter++
+		if err := saveOTPKey(api, config, secret, key); err != nil {
+			return err
+		}
+	}
+	return printOutput(config, otpCode{Code: code, Counter: &counter}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Code: %s\nCounter: %d\n", code, counter)
+		return err
+	})
+}
+
+// One-time code, TOTP code has remaining seconds and HOTP code has counter
+type otpCode struct {
+	Code             string  `json:"code"`
+	RemainingSeconds int     `json:"remaining_seconds,omitempty"`
+	Counter          *uint64 `json:"counter,omitempty"`
+}
+
+func decryptOTPKey(secret model.Secret, key []byte) (otp.Key, error) {
+	content, err := crypto.DecryptData(secret.Content, key)
+	if err != nil {
+		return otp.Key{}, fmt.Errorf("error during decrypt content: %w", err)
+	}
+
+	var otpKey otp.Key
+	if err := json.Unmarshal(content, &otpKey); err != nil {
+		return otp.Key{}, fmt.Errorf("can`t parse otp key: %w", err)
+	}
+	return otpKey, nil
+}
+
+// Save key with incremented counter, secret metadata is kept. Secret changed since it was read is not overwritten
+func saveOTPKey(api *apiClient, config Config, secret model.Secret, otpKey otp.Key) error {
+	content, err := json.Marshal(otpKey)
+	if err != nil {
+		return fmt.Errorf("can`t serialise otp key: %w", err)
+	}
+	secret.Content, err = crypto.EncryptData(content, []byte(config.EncryptionKey))
+	if err != nil {
+		return fmt.Errorf("error during encrypt data: %w", err)
+	}
+
+	if err := api.UpdateSecret(context.Background(), secret); err != nil {
+		return fmt.Errorf("can`t save otp counter: %w", err)
+	}
+	return nil
+}
+
+// Fabric to create OTP code command
+type OTPCommandFactory struct{}
+
+func (f *OTPCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewOTPCommand(args)
+}
@@ -0,0 +1,127 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/otp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+)
+
+func TestParseOTPKey(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      map[string]string
+		expected  otp.Key
+		wantError bool
+	}{
+		{
+			name: "From uri",
+			args: map[string]string{"uri": "otpauth://totp/GitHub:bot?secret=JBSWY3DPEHPK3PXP&issuer=GitHub"},
+			expected: otp.Key{Type: otp.TOTPType, Secret: "JBSWY3DPEHPK3PXP", Issuer: "GitHub", Account: "bot",
+				Algorithm: otp.SHA1Algorithm, Digits: 6, Period: 30},
+		},
+		{
+			name: "From parameters",
+			args: map[string]string{"secret": "JBSWY3DPEHPK3PXP", "account": "bot", "algorithm": "sha256", "digits": "8", "period": "60"},
+			expected: otp.Key{Type: otp.TOTPType, Secret: "JBSWY3DPEHPK3PXP", Account: "bot",
+				Algorithm: otp.SHA256Algorithm, Digits: 8, Period: 60},
+		},
+		{
+			name:      "Uri and secret",
+			args:      map[string]string{"uri": "otpauth://totp/bot?secret=JBSWY3DPEHPK3PXP", "secret": "JBSWY3DPEHPK3PXP"},
+			wantError: true,
+		},
+		{
+			name:      "Nothing",
+			args:      map[string]string{},
+			wantError: true,
+		},
+		{
+			name:      "Invalid digits",
+			args:      map[string]string{"secret": "JBSWY3DPEHPK3PXP", "digits": "4"},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := parseOTPKey(tc.args)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, key)
+		})
+	}
+}
+
+func TestOTPCommand_HOTPCounter(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+	newServer := func(t *testing.T, counter uint64) (*fakeSecretServer, Config) {
+		content, err := json.Marshal(otp.Key{Type: otp.HOTPType, Secret: "JBSWY3DPEHPK3PXP", Algorithm: otp.SHA1Algorithm, Digits: 6, Counter: counter})
+		require.NoError(t, err)
+		encrypted, err := crypto.EncryptData(content, key)
+		require.NoError(t, err)
+
+		fake, config := newFakeSecretServer(t, model.Secret{Name: "otp/github", Type: model.OTPSecretType, Content: encrypted})
+		config.EncryptionKey = string(key)
+		return fake, config
+	}
+	storedCounter := func(t *testing.T, fake *fakeSecretServer) uint64 {
+		secret, ok := fake.secret("otp/github")
+		require.True(t, ok)
+		otpKey, err := decryptOTPKey(secret, key)
+		require.NoError(t, err)
+		return otpKey.Counter
+	}
+	cmd := &OTPCommand{secretName: "otp/github"}
+
+	t.Run("should read counter again when it was changed concurrently", func(t *testing.T) {
+		fake, config := newServer(t, 5)
+		fake.beforeUpdate = func(server *fakeSecretServer) int {
+			if server.updates == 1 {
+				content, err := json.Marshal(otp.Key{Type: otp.HOTPType, Secret: "JBSWY3DPEHPK3PXP", Algorithm: otp.SHA1Algorithm, Digits: 6, Counter: 6})
+				assert.NoError(t, err)
+				secret := server.secrets["otp/github"]
+				secret.Content, err = crypto.EncryptData(content, key)
+				assert.NoError(t, err)
+				secret.Version++
+				server.secrets["otp/github"] = secret
+			}
+			return 0
+		}
+
+		require.NoError(t, cmd.Execute(config))
+		assert.Equal(t, 2, fake.updates)
+		assert.Equal(t, uint64(7), storedCounter(t, fake))
+		secret, _ := fake.secret("otp/github")
+		assert.Equal(t, int64(2), secret.Version)
+	})
+
+	t.Run("should not print code when counter was not saved", func(t *testing.T) {
+		fake, config := newServer(t, 5)
+		fake.beforeUpdate = func(server *fakeSecretServer) int { return http.StatusForbidden }
+
+		reader, writer, err := os.Pipe()
+		require.NoError(t, err)
+		stdout := os.Stdout
+		os.Stdout = writer
+		err = cmd.Execute(config)
+		os.Stdout = stdout
+		writer.Close()
+		output, readErr := io.ReadAll(reader)
+		require.NoError(t, readErr)
+
+		assert.Error(t, err)
+		assert.Empty(t, string(output))
+		assert.Equal(t, uint64(5), storedCounter(t, fake))
+	})
+}
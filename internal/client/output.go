@@ -0,0 +1,147 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"gopkg.in/yaml.v3"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	TextOutput = "text"
+	JSONOutput = "json"
+	YAMLOutput = "yaml"
+	EnvOutput  = "env"
+)
+
+// Output formats of command results
+var OutputFormats = []string{TextOutput, JSONOutput, YAMLOutput, EnvOutput}
+
+func validateOutputFormat(format string) error {
+	for _, known := range OutputFormats {
+		if format == known {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown output format \"%s\", available formats: %s", format, strings.Join(OutputFormats, ", "))
+}
+
+// Result of command which changes one object, e.g. saved or deleted secret
+type commandResult struct {
+	Status string `json:"status"`
+	Name   string `json:"name,omitempty"`
+}
+
+// Print command result in output format of config to stdout
+func printOutput(config Config, value interface{}, text func(w io.Writer) error) error {
+	return writeOutput(os.Stdout, config.Output, value, text)
+}
+
+// Print status of command, text format prints message
+func printResult(config Config, result commandResult, message string) error {
+	return printOutput(config, result, func(w io.Writer) error {
+		_, err := fmt.Fprintln(w, message)
+		return err
+	})
+}
+
+// Write value in output format. Text format is written by text function, other formats
+// are built from JSON representation of value
+func writeOutput(writer io.Writer, format string, value interface{}, text func(w io.Writer) error) error {
+	if format == "" || format == TextOutput {
+		return text(writer)
+	}
+
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	encoder.SetIndent("", "  ")
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("error during marshal output: %w", err)
+	}
+	data := buffer.Bytes()
+
+	switch format {
+	case JSONOutput:
+		_, err := writer.Write(data)
+		return err
+	case YAMLOutput:
+		var generic interface{}
+		if err := json.Unmarshal(data, &generic); err != nil {
+			return fmt.Errorf("error during marshal output: %w", err)
+		}
+		encoder := yaml.NewEncoder(writer)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(generic); err != nil {
+			return fmt.Errorf("error during marshal output: %w", err)
+		}
+		return encoder.Close()
+	case EnvOutput:
+		var record map[string]interface{}
+		if err := json.Unmarshal(data, &record); err != nil {
+			return errors.New("env output is supported by commands printing one record")
+		}
+		variables := make(map[string]string)
+		flattenEnv("", record, variables)
+
+		names := make([]string, 0, len(variables))
+		for name := range variables {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if _, err := fmt.Fprintf(writer, "%s=%s\n", name, shellQuote(variables[name])); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return validateOutputFormat(format)
+	}
+}
+
+// Flatten nested record into environment variables, e.g. fields.password is FIELDS_PASSWORD
+func flattenEnv(prefix string, value interface{}, variables map[string]string) {
+	switch typed := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range typed {
+			flattenEnv(envName(prefix, key), nested, variables)
+		}
+	case []interface{}:
+		items := make([]string, 0, len(typed))
+		for _, item := range typed {
+			items = append(items, fmt.Sprint(item))
+		}
+		variables[prefix] = strings.Join(items, ",")
+	case nil:
+		variables[prefix] = ""
+	case float64:
+		variables[prefix] = strconv.FormatFloat(typed, 'f', -1, 64)
+	default:
+		variables[prefix] = fmt.Sprint(typed)
+	}
+}
+
+func envName(prefix string, key string) string {
+	name := strings.ToUpper(strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' {
+			return r
+		}
+		return '_'
+	}, key))
+	if prefix == "" {
+		return name
+	}
+	return prefix + "_" + name
+}
+
+// Quote value for POSIX shell, so output can be used with eval
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
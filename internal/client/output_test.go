@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestWriteOutput(t *testing.T) {
+	value := secretOutput{
+		Name:   "prod/db",
+		Type:   model.CredentialsSecretType,
+		Fields: map[string]string{"username": "admin", "password": "it's <secret>"},
+		Tags:   []string{"prod", "db"},
+	}
+	text := func(w io.Writer) error {
+		_, err := io.WriteString(w, "text\n")
+		return err
+	}
+	write := func(format string, value interface{}) (string, error) {
+		var buffer bytes.Buffer
+		err := writeOutput(&buffer, format, value, text)
+		return buffer.String(), err
+	}
+
+	output, err := write(TextOutput, value)
+	require.NoError(t, err)
+	assert.Equal(t, "text\n", output)
+
+	output, err = write(JSONOutput, value)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"prod/db","type":"CREDENTIALS","fields":{"username":"admin","password":"it's <secret>"},"tags":["prod","db"]}`, output)
+	assert.Contains(t, output, "<secret>")
+
+	output, err = write(YAMLOutput, value)
+	require.NoError(t, err)
+	assert.Equal(t, "fields:\n  password: it's <secret>\n  username: admin\nname: prod/db\ntags:\n  - prod\n  - db\ntype: CREDENTIALS\n", output)
+
+	output, err = write(EnvOutput, value)
+	require.NoError(t, err)
+	assert.Equal(t, "FIELDS_PASSWORD='it'\\''s <secret>'\nFIELDS_USERNAME='admin'\nNAME='prod/db'\nTAGS='prod,db'\nTYPE='CREDENTIALS'\n", output)
+
+	_, err = write(EnvOutput, []secretListItem{{Name: "prod/db"}})
+	assert.Error(t, err)
+
+	_, err = write("xml", value)
+	assert.Error(t, err)
+
+	textErr := errors.New("text error")
+	err = writeOutput(io.Discard, TextOutput, value, func(w io.Writer) error { return textErr })
+	assert.ErrorIs(t, err, textErr)
+}
+
+func TestValidateOutputFormat(t *testing.T) {
+	for _, format := range OutputFormats {
+		assert.NoError(t, validateOutputFormat(format))
+	}
+	assert.Error(t, validateOutputFormat("xml"))
+}
+
+func TestNewSecretOutput(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+	encrypt := func(content string) []byte {
+		encrypted, err := crypto.EncryptData([]byte(content), key)
+		require.NoError(t, err)
+		return encrypted
+	}
+	createdAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	output, err := newSecretOutput(model.Secret{
+		Name:      "prod/db",
+		Type:      model.CredentialsSecretType,
+		Content:   encrypt("admin:p:ss"),
+		Notes:     encrypt("primary"),
+		URL:       "https://db.example.com",
+		CreatedAt: &createdAt,
+	}, key, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, secretOutput{
+		Name:      "prod/db",
+		Type:      model.CredentialsSecretType,
+		Fields:    map[string]string{"username": "admin", "password": "p:ss"},
+		URL:       "https://db.example.com",
+		Note:      "primary",
+		CreatedAt: &createdAt,
+	}, output)
+
+	output, err = newSecretOutput(model.Secret{Name: "note", Type: model.TextSecretType, Content: encrypt("hello")}, key, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"value": "hello"}, output.Fields)
+}
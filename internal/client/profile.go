@@ -0,0 +1,223 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// Profile used when no profile is selected
+const defaultProfileName = "default"
+
+// Named client settings stored in configuration file
+type profile struct {
+	Server             string `json:"server,omitempty"`
+	Username           string `json:"username,omitempty"`
+	CAFile             string `json:"ca_file,omitempty"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify,omitempty"`
+	ClientCertFile     string `json:"client_cert_file,omitempty"`
+	ClientKeyFile      string `json:"client_key_file,omitempty"`
+	PinSHA256          string `json:"pin_sha256,omitempty"`
+	TokenFile          string `json:"token_file,omitempty"`
+	CredentialsFolder  string `json:"credentials_folder,omitempty"`
+	Output             string `json:"output,omitempty"`
+}
+
+// Client configuration file with named profiles
+type configFile struct {
+	CurrentProfile string             `json:"current_profile,omitempty"`
+	Profiles       map[string]profile `json:"profiles,omitempty"`
+}
+
+// Setting of profile available to config commands
+type profileKey struct {
+	name        string
+	description string
+	get         func(p profile) string
+	set         func(p *profile, value string) error
+}
+
+var profileKeys = []profileKey{
+	{
+		name:        "server",
+		description: "Server address",
+		get:         func(p profile) string { return p.Server },
+		set:         func(p *profile, value string) error { p.Server = value; return nil },
+	},
+	{
+		name:        "username",
+		description: "User name used by login",
+		get:         func(p profile) string { return p.Username },
+		set:         func(p *profile, value string) error { p.Username = value; return nil },
+	},
+	{
+		name:        "ca-file",
+		description: "PEM file with CA certificates of server",
+		get:         func(p profile) string { return p.CAFile },
+		set:         func(p *profile, value string) error { p.CAFile = value; return nil },
+	},
+	{
+		name:        "insecure-skip-verify",
+		description: "Skip verification of server certificate",
+		get:         func(p profile) string { return strconv.FormatBool(p.InsecureSkipVerify) },
+		set: func(p *profile, value string) error {
+			parsed, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.New("insecure-skip-verify should be true or false")
+			}
+			p.InsecureSkipVerify = parsed
+			return nil
+		},
+	},
+	{
+		name:        "client-cert",
+		description: "PEM file with client certificate for mutual TLS",
+		get:         func(p profile) string { return p.ClientCertFile },
+		set:         func(p *profile, value string) error { p.ClientCertFile = value; return nil },
+	},
+	{
+		name:        "client-key",
+		description: "PEM file with private key of client certificate",
+		get:         func(p profile) string { return p.ClientKeyFile },
+		set:         func(p *profile, value string) error { p.ClientKeyFile = value; return nil },
+	},
+	{
+		name:        "pin-sha256",
+		description: "Comma separated base64 SHA-256 hashes of pinned server public keys",
+		get:         func(p profile) string { return p.PinSHA256 },
+		set: func(p *profile, value string) error {
+			if _, err := parsePins(value); err != nil {
+				return err
+			}
+			p.PinSHA256 = value
+			return nil
+		},
+	},
+	{
+		name:        "token-file",
+		description: "File with auth token, in config dir by default",
+		get:         func(p profile) string { return p.TokenFile },
+		set:         func(p *profile, value string) error { p.TokenFile = value; return nil },
+	},
+	{
+		name:        "credentials-folder",
+		description: "Folder with credentials of git and docker helpers",
+		get:         func(p profile) string { return p.CredentialsFolder },
+		set:         func(p *profile, value string) error { p.CredentialsFolder = value; return nil },
+	},
+	{
+		name:        "output",
+		description: "Output format of command results",
+		get:         func(p profile) string { return p.Output },
+		set: func(p *profile, value string) error {
+			if value != "" {
+				if err := validateOutputFormat(value); err != nil {
+					return err
+				}
+			}
+			p.Output = value
+			return nil
+		},
+	},
+}
+
+func findProfileKey(name string) (profileKey, error) {
+	for _, key := range profileKeys {
+		if key.name == name {
+			return key, nil
+		}
+	}
+	names := make([]string, 0, len(profileKeys))
+	for _, key := range profileKeys {
+		names = append(names, key.name)
+	}
+	return profileKey{}, fmt.Errorf("unknown setting \"%s\", available settings: %v", name, names)
+}
+
+// Dir of client configuration and tokens in user config dir, e.g. ~/.config/gophkeeper
+func defaultConfigDir() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "gophkeeper")
+}
+
+func defaultConfigFilePath() string {
+	dir := defaultConfigDir()
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, "config.json")
+}
+
+// Token of profile is stored next to configuration file
+func defaultTokenFilePath(configFilePath string, profileName string) string {
+	if configFilePath == "" {
+		return "tokens.json"
+	}
+	return filepath.Join(filepath.Dir(configFilePath), "tokens", profileName+".json")
+}
+
+// Read configuration file, missing file is empty configuration
+func loadConfigFile(path string) (configFile, error) {
+	file := configFile{Profiles: make(map[string]profile)}
+	if path == "" {
+		return file, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return file, nil
+	}
+	if err != nil {
+		return configFile{}, fmt.Errorf("error during read config file: %w", err)
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return configFile{}, fmt.Errorf("can`t parse config file %s: %w", path, err)
+	}
+	if file.Profiles == nil {
+		file.Profiles = make(map[string]profile)
+	}
+	return file, nil
+}
+
+// Write configuration file readable by owner only
+func (f configFile) save(path string) error {
+	if path == "" {
+		return errors.New("config file path is unknown, set GOPHKEEPER_CONFIG")
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error during marshal config: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("error during create config dir: %w", err)
+	}
+	return writeFileAtomic(path, data)
+}
+
+func (f configFile) profileNames() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for name := range f.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Commands other than config commands can`t run with profile missing in configuration file,
+// so misspelled profile does not send requests to default server
+func checkProfile(config Config, command Command) error {
+	if config.profileFound || config.Profile == "" || config.Profile == defaultProfileName {
+		return nil
+	}
+	if _, ok := command.(*ConfigCommand); ok {
+		return nil
+	}
+	return fmt.Errorf("profile %s is not configured, add it with --profile %s config set server <address>", config.Profile, config.Profile)
+}
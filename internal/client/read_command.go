@@ -1,17 +1,20 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
-	"github.com/desepticon55/gophkeeper/pkg/crypto"
-	"github.com/go-resty/resty/v2"
+	"io"
 	"time"
 )
 
 // Command to read secret
 type ReadCommand struct {
 	secretName string
+	field      string
+	// Print card number and code without mask
+	reveal bool
 }
 
 func NewReadCommand(args map[string]string) (*ReadCommand, error) {
@@ -19,42 +22,102 @@ func NewReadCommand(args map[string]string) (*ReadCommand, error) {
 	if !ok1 || secretName == "" {
 		return nil, errors.New("secret name are required")
 	}
+	reveal, err := parseBoolFlag(args, "reveal")
+	if err != nil {
+		return nil, err
+	}
 
 	return &ReadCommand{
 		secretName: secretName,
+		field:      args["field"],
+		reveal:     reveal,
 	}, nil
 }
 
 func (cmd *ReadCommand) Execute(config Config) error {
-	token, err := readTokenFromFile()
+	token, err := readToken(config)
 	if err != nil {
 		return fmt.Errorf("can`t find auth data: %w", err)
 	}
 
-	var secretPayload = &model.Secret{}
+	secretPayload, err := newAPIClient(config, token).FindSecret(context.Background(), cmd.secretName)
+	if err != nil {
+		return fmt.Errorf("can`t read secret. Reason: %w", err)
+	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
+	key := []byte(config.EncryptionKey)
+	now := time.Now()
 
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+token).
-		SetResult(secretPayload).
-		Get(config.ServerAddress + "/api/user/secret/" + cmd.secretName)
+	// single field is printed as is, so it can be used by scripts in any output format. Hidden fields
+	// are masked like in full output unless reveal is set
+	if cmd.field != "" {
+		value, err := secretField(secretPayload, cmd.field, key, now)
+		if err != nil {
+			return err
+		}
+		if !cmd.reveal {
+			if value, err = maskSecretField(secretPayload.Type, cmd.field, value); err != nil {
+				return err
+			}
+		}
+		fmt.Println(value)
+		return nil
+	}
 
+	output, err := newSecretOutput(secretPayload, key, now)
 	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
+		return err
 	}
-
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("can`t save secret. Reason: %s", resp.String())
+	if !cmd.reveal {
+		output.Fields = maskSecretFields(output.Type, output.Fields)
 	}
+	return printOutput(config, output, func(w io.Writer) error {
+		fmt.Fprintf(w, "Your secret name: %s\nType: %s\n", output.Name, output.Type)
+		renderSecretFields(w, output.Type, output.Fields)
+		return printSecretMetadata(w, &secretPayload, key)
+	})
+}
 
-	data, err := crypto.DecryptData(secretPayload.Content, []byte(config.EncryptionKey))
+// Decrypted secret with content split into fields of secret type
+type secretOutput struct {
+	Name             string            `json:"name"`
+	Type             string            `json:"type"`
+	Fields           map[string]string `json:"fields"`
+	URL              string            `json:"url,omitempty"`
+	Note             string            `json:"note,omitempty"`
+	Tags             []string          `json:"tags,omitempty"`
+	ExpiresAt        *time.Time        `json:"expires_at,omitempty"`
+	RotateAfter      *time.Time        `json:"rotate_after,omitempty"`
+	BurnAfterReading bool              `json:"burn_after_reading,omitempty"`
+	CreatedAt        *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt        *time.Time        `json:"updated_at,omitempty"`
+	LastAccessedAt   *time.Time        `json:"last_accessed_at,omitempty"`
+}
+
+func newSecretOutput(secret model.Secret, key []byte, now time.Time) (secretOutput, error) {
+	_, fields, _, err := decodeContentFields(secret, key, now)
+	if err != nil {
+		return secretOutput{}, err
+	}
+	note, err := secretField(secret, "note", key, now)
 	if err != nil {
-		return fmt.Errorf("error during decrypt content: %w", err)
+		return secretOutput{}, err
 	}
-	fmt.Printf("Your secret name: %s\nYour secret value: %s\n", secretPayload.Name, data)
-	return nil
+
+	return secretOutput{
+		Name:             secret.Name,
+		Type:             secret.Type,
+		Fields:           fields,
+		URL:              secret.URL,
+		Note:             note,
+		Tags:             secret.Tags,
+		ExpiresAt:        secret.ExpiresAt,
+		RotateAfter:      secret.RotateAfter,
+		BurnAfterReading: secret.BurnAfterReading,
+		CreatedAt:        secret.CreatedAt,
+		UpdatedAt:        secret.UpdatedAt,
+		LastAccessedAt:   secret.LastAccessedAt,
+	}, nil
 }
 
 // Fabric to create secret read command
@@ -1,11 +1,10 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
-	"github.com/go-resty/resty/v2"
-	"time"
 )
 
 // Command to register new user
@@ -30,34 +29,17 @@ func (cmd *UserRegisterCommand) Execute(config Config) error {
 		Password: cmd.password,
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetBody(registerPayload).
-		Post(config.ServerAddress + "/api/user/register")
-
+	token, err := newAPIClient(config, "").Register(context.Background(), *registerPayload)
 	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
-	}
-
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("error during register new user. Reason: %s. Status = %d", resp.String(), resp.StatusCode())
-	}
-
-	authHeader := resp.Header().Get("Authorization")
-	if authHeader == "" {
-		return errors.New("authorization token missing from response")
+		return fmt.Errorf("error during register new user. Reason: %w", err)
 	}
 
-	token := authHeader[len("Bearer "):]
-	err = saveTokenToFile(token)
+	err = saveTokenToFile(config, token)
 	if err != nil {
 		return fmt.Errorf("failed to save token to file: %w", err)
 	}
 
-	fmt.Println("User logged in successfully")
-	return nil
+	return printResult(config, commandResult{Status: "logged_in"}, "User logged in successfully")
 }
 
 // Fabric to create user register command
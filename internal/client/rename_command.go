@@ -0,0 +1,45 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Command to rename secret
+type RenameCommand struct {
+	from string
+	to   string
+}
+
+func NewRenameCommand(args map[string]string) (*RenameCommand, error) {
+	from, ok1 := args["from"]
+	to, ok2 := args["to"]
+	if !ok1 || !ok2 || from == "" || to == "" {
+		return nil, errors.New("current and new secret names are required")
+	}
+
+	return &RenameCommand{from: from, to: to}, nil
+}
+
+func (cmd *RenameCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	err = newAPIClient(config, token).RenameSecret(context.Background(), cmd.from, cmd.to)
+	if err != nil {
+		return fmt.Errorf("can`t rename secret. Reason: %w", err)
+	}
+
+	return printResult(config, commandResult{Status: "renamed", Name: cmd.to},
+		fmt.Sprintf("Secret \"%s\" was renamed to \"%s\"", cmd.from, cmd.to))
+}
+
+// Fabric to create secret rename command
+type RenameCommandFactory struct{}
+
+func (f *RenameCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewRenameCommand(args)
+}
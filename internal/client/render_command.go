@@ -0,0 +1,113 @@
+package client
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/template"
+)
+
+// Command to render config file from Go template with secret references
+type RenderCommand struct {
+	input  string
+	output string
+}
+
+func NewRenderCommand(args map[string]string) (*RenderCommand, error) {
+	input, ok := args["input"]
+	if !ok || input == "" {
+		return nil, errors.New("input template is required")
+	}
+
+	return &RenderCommand{input: input, output: args["output"]}, nil
+}
+
+func (cmd *RenderCommand) Execute(config Config) error {
+	text, err := os.ReadFile(cmd.input)
+	if err != nil {
+		return fmt.Errorf("error during read template: %w", err)
+	}
+
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	resolver := newSecretResolver(newAPIClient(config, token), config)
+	var rendered bytes.Buffer
+	if err := renderTemplate(filepath.Base(cmd.input), string(text), resolver.resolve, &rendered); err != nil {
+		return err
+	}
+
+	if cmd.output == "" || cmd.output == "-" {
+		_, err := os.Stdout.Write(rendered.Bytes())
+		return err
+	}
+	if err := writeFileAtomic(cmd.output, rendered.Bytes()); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "Template was rendered to %s\n", cmd.output)
+	return nil
+}
+
+// Render template with secret "name" "field" function, field is optional
+func renderTemplate(name string, text string, resolve func(secretReference) (string, error), writer io.Writer) error {
+	funcs := template.FuncMap{
+		"secret": func(name string, field ...string) (string, error) {
+			if len(field) > 1 {
+				return "", errors.New("secret function accepts secret name and one field")
+			}
+			reference := secretReference{name: name}
+			if len(field) == 1 {
+				reference.field = field[0]
+			}
+			return resolve(reference)
+		},
+	}
+
+	tmpl, err := template.New(name).Funcs(funcs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return fmt.Errorf("can`t parse template: %w", err)
+	}
+	if err := tmpl.Execute(writer, nil); err != nil {
+		return fmt.Errorf("can`t render template: %w", err)
+	}
+	return nil
+}
+
+// Write file readable by owner only. Data is written to temp file in the same directory
+// and renamed, so partially written secrets are never left on disk
+func writeFileAtomic(path string, data []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("error during create output file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if err := file.Chmod(0600); err != nil {
+		file.Close()
+		return fmt.Errorf("error during change output file permissions: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return fmt.Errorf("error during write output file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error during write output file: %w", err)
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return fmt.Errorf("error during write output file: %w", err)
+	}
+	return nil
+}
+
+// Fabric to create render command
+type RenderCommandFactory struct{}
+
+func (f *RenderCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewRenderCommand(args)
+}
@@ -0,0 +1,75 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	secrets := map[secretReference]string{
+		{name: "prod/db", field: "username"}: "admin",
+		{name: "prod/db", field: "password"}: "p@ss",
+		{name: "token"}:                      "api-token",
+	}
+	resolve := func(reference secretReference) (string, error) {
+		value, ok := secrets[reference]
+		if !ok {
+			return "", fmt.Errorf("secret %s was not found", reference.name)
+		}
+		return value, nil
+	}
+
+	testCases := []struct {
+		name      string
+		template  string
+		expected  string
+		wantError bool
+	}{
+		{
+			name:     "Fields and default field",
+			template: "db:\n  user: {{ secret \"prod/db\" \"username\" }}\n  password: {{ secret \"prod/db\" \"password\" | printf \"%q\" }}\ntoken: {{ secret \"token\" }}\n",
+			expected: "db:\n  user: admin\n  password: \"p@ss\"\ntoken: api-token\n",
+		},
+		{name: "Unknown secret", template: "{{ secret \"missing\" }}", wantError: true},
+		{name: "Too many arguments", template: "{{ secret \"prod/db\" \"username\" \"password\" }}", wantError: true},
+		{name: "Invalid template", template: "{{ secret ", wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var rendered bytes.Buffer
+			err := renderTemplate("test", tc.template, resolve, &rendered)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, rendered.String())
+		})
+	}
+}
+
+func TestWriteFileAtomic(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("old"), 0644))
+
+	require.NoError(t, writeFileAtomic(path, []byte("new")))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new", string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1, "temp file should be removed")
+}
@@ -0,0 +1,118 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+)
+
+var envNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Variables of client which are not passed to child process, so vault key does not leak
+var hiddenEnvs = []string{"ENCRYPTION_KEY", "GOPHKEEPER_AGENT_SOCKET"}
+
+// Environment variable filled by secret field
+type secretEnv struct {
+	name      string
+	reference secretReference
+}
+
+// Command to run child process with secrets in environment. Secret values are never printed
+type RunCommand struct {
+	envs    []secretEnv
+	command []string
+}
+
+func NewRunCommand(args map[string]string, command []string) (*RunCommand, error) {
+	if len(command) == 0 {
+		return nil, errors.New("command to run is required after --")
+	}
+
+	var envs []secretEnv
+	for _, value := range splitRepeated(args["env"]) {
+		env, err := parseSecretEnv(value)
+		if err != nil {
+			return nil, err
+		}
+		envs = append(envs, env)
+	}
+	if len(envs) == 0 {
+		return nil, errors.New("at least one env is required")
+	}
+
+	return &RunCommand{envs: envs, command: command}, nil
+}
+
+// Parse NAME=secret#field
+func parseSecretEnv(value string) (secretEnv, error) {
+	name, reference, ok := strings.Cut(value, "=")
+	if !ok || !envNamePattern.MatchString(name) {
+		return secretEnv{}, fmt.Errorf("env \"%s\" should be in NAME=secret#field format", value)
+	}
+
+	parsedReference, err := parseSecretReference(reference)
+	if err != nil {
+		return secretEnv{}, err
+	}
+	return secretEnv{name: name, reference: parsedReference}, nil
+}
+
+func (cmd *RunCommand) Execute(config Config) error {
+	path, err := exec.LookPath(cmd.command[0])
+	if err != nil {
+		return fmt.Errorf("can`t find command: %w", err)
+	}
+
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	resolver := newSecretResolver(newAPIClient(config, token), config)
+	var secrets []string
+	for _, secretEnv := range cmd.envs {
+		value, err := resolver.resolve(secretEnv.reference)
+		if err != nil {
+			return fmt.Errorf("can`t resolve env %s: %w", secretEnv.name, err)
+		}
+		secrets = append(secrets, secretEnv.name+"="+value)
+	}
+
+	return execProcess(path, cmd.command, childEnv(os.Environ(), secrets))
+}
+
+// Environment of child process: parent environment without client variables and variables
+// set by secrets, because the first occurrence of duplicated variable wins
+func childEnv(parent []string, secrets []string) []string {
+	removed := make(map[string]bool)
+	for _, name := range hiddenEnvs {
+		removed[name] = true
+	}
+	for _, secret := range secrets {
+		name, _, _ := strings.Cut(secret, "=")
+		removed[name] = true
+	}
+
+	env := make([]string, 0, len(parent)+len(secrets))
+	for _, variable := range parent {
+		name, _, _ := strings.Cut(variable, "=")
+		if !removed[name] {
+			env = append(env, variable)
+		}
+	}
+	return append(env, secrets...)
+}
+
+// Fabric to create run command
+type RunCommandFactory struct{}
+
+func (f *RunCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewRunCommand(args, nil)
+}
+
+func (f *RunCommandFactory) CreateWithArgs(args map[string]string, command []string) (Command, error) {
+	return NewRunCommand(args, command)
+}
@@ -0,0 +1,71 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestNewRunCommand(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      map[string]string
+		command   []string
+		expected  []secretEnv
+		wantError bool
+	}{
+		{
+			name:    "Several envs",
+			args:    map[string]string{"env": "DB_USER=prod/db#username" + repeatedFlagSeparator + "API_TOKEN=token"},
+			command: []string{"./app"},
+			expected: []secretEnv{
+				{name: "DB_USER", reference: secretReference{name: "prod/db", field: "username"}},
+				{name: "API_TOKEN", reference: secretReference{name: "token"}},
+			},
+		},
+		{name: "No command", args: map[string]string{"env": "A=a"}, wantError: true},
+		{name: "No envs", args: map[string]string{}, command: []string{"./app"}, wantError: true},
+		{name: "Invalid env name", args: map[string]string{"env": "1A=a"}, command: []string{"./app"}, wantError: true},
+		{name: "Missing reference", args: map[string]string{"env": "A"}, command: []string{"./app"}, wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cmd, err := NewRunCommand(tc.args, tc.command)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, cmd.envs)
+			assert.Equal(t, tc.command, cmd.command)
+		})
+	}
+}
+
+func TestChildEnv(t *testing.T) {
+	t.Setenv("DB_PASS", "stale")
+	t.Setenv("ENCRYPTION_KEY", "vault-key")
+	t.Setenv("GOPHKEEPER_AGENT_SOCKET", "/tmp/agent.sock")
+	t.Setenv("KEEP", "kept")
+
+	env := childEnv(os.Environ(), []string{"DB_PASS=secret"})
+	assert.Contains(t, env, "DB_PASS=secret")
+	assert.Contains(t, env, "KEEP=kept")
+	assert.NotContains(t, env, "DB_PASS=stale")
+	assert.NotContains(t, env, "ENCRYPTION_KEY=vault-key")
+	assert.NotContains(t, env, "GOPHKEEPER_AGENT_SOCKET=/tmp/agent.sock")
+
+	shell, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("sh is not available")
+	}
+	child := exec.Command(shell, "-c", `printf "%s|%s" "$DB_PASS" "$ENCRYPTION_KEY"`)
+	child.Env = env
+	output, err := child.Output()
+	require.NoError(t, err)
+	assert.Equal(t, "secret|", string(output))
+}
@@ -1,19 +1,19 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
 	"github.com/desepticon55/gophkeeper/pkg/crypto"
-	"github.com/go-resty/resty/v2"
-	"time"
 )
 
 // Command to save CARD secret
 type SaveCardCommand struct {
 	secretName     string
 	cardRequisites model.Card
+	metadata       secretMetadata
 }
 
 func NewSaveCardCommand(args map[string]string) (*SaveCardCommand, error) {
@@ -26,14 +26,20 @@ func NewSaveCardCommand(args map[string]string) (*SaveCardCommand, error) {
 		return nil, errors.New("card requisites are required")
 	}
 
+	cardRequisites, err := validateCard(model.Card{Number: number, Date: date, Code: code, Holder: holder})
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseSecretMetadata(args)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SaveCardCommand{
-		secretName: secretName,
-		cardRequisites: model.Card{
-			Number: number,
-			Date:   date,
-			Code:   code,
-			Holder: holder,
-		},
+		secretName:     secretName,
+		cardRequisites: cardRequisites,
+		metadata:       metadata,
 	}, nil
 }
 
@@ -43,7 +49,7 @@ func (cmd *SaveCardCommand) Execute(config Config) error {
 		return fmt.Errorf("can`t serialise card requisites: %w", err)
 	}
 
-	token, err := readTokenFromFile()
+	token, err := readToken(config)
 	if err != nil {
 		return fmt.Errorf("can`t find auth data: %w", err)
 	}
@@ -59,24 +65,16 @@ func (cmd *SaveCardCommand) Execute(config Config) error {
 		Content: encryptedData,
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+token).
-		SetBody(secretPayload).
-		Post(config.ServerAddress + "/api/user/secret")
-
-	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
+	if err := cmd.metadata.applyTo(secretPayload, []byte(config.EncryptionKey)); err != nil {
+		return err
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("can`t save secret. Reason: %s", resp.String())
+	err = newAPIClient(config, token).CreateSecret(context.Background(), *secretPayload)
+	if err != nil {
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
 	}
 
-	fmt.Println("Secret saved successfully")
-	return nil
+	return printResult(config, commandResult{Status: "saved", Name: cmd.secretName}, "Secret saved successfully")
 }
 
 // Fabric to create CARD secret command
@@ -1,12 +1,12 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
 	"github.com/desepticon55/gophkeeper/pkg/crypto"
-	"github.com/go-resty/resty/v2"
-	"time"
+	"io"
 )
 
 // Command to save CREDENTIALS secret
@@ -14,27 +14,59 @@ type SaveCredentialsCommand struct {
 	secretName string
 	username   string
 	password   string
+	metadata   secretMetadata
+	// Password was generated and should be shown to user
+	generated bool
+	entropy   float64
 }
 
 func NewSaveCredentialsCommand(args map[string]string) (*SaveCredentialsCommand, error) {
 	secretName, ok1 := args["name"]
 	username, ok2 := args["username"]
-	password, ok3 := args["password"]
-	if !ok1 || !ok2 || !ok3 || secretName == "" || username == "" || password == "" {
+	password := args["password"]
+	generate, err := parseBoolFlag(args, "generate")
+	if err != nil {
+		return nil, err
+	}
+	if generate && password != "" {
+		return nil, errors.New("password and generate can`t be used together")
+	}
+
+	var entropy float64
+	if generate {
+		options, err := parsePasswordOptions(args)
+		if err != nil {
+			return nil, err
+		}
+		password, entropy, err = options.generate()
+		if err != nil {
+			return nil, fmt.Errorf("can`t generate password: %w", err)
+		}
+	}
+
+	if !ok1 || !ok2 || secretName == "" || username == "" || password == "" {
 		return nil, errors.New("secretName and username and password are required")
 	}
 
+	metadata, err := parseSecretMetadata(args)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SaveCredentialsCommand{
 		secretName: secretName,
 		username:   username,
 		password:   password,
+		metadata:   metadata,
+		generated:  generate,
+		entropy:    entropy,
 	}, nil
 }
 
 func (cmd *SaveCredentialsCommand) Execute(config Config) error {
 	content := []byte(cmd.username + ":" + cmd.password)
 
-	token, err := readTokenFromFile()
+	token, err := readToken(config)
 	if err != nil {
 		return fmt.Errorf("can`t find auth data: %w", err)
 	}
@@ -50,24 +82,32 @@ func (cmd *SaveCredentialsCommand) Execute(config Config) error {
 		Content: encryptedData,
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+token).
-		SetBody(secretPayload).
-		Post(config.ServerAddress + "/api/user/secret")
+	if err := cmd.metadata.applyTo(secretPayload, []byte(config.EncryptionKey)); err != nil {
+		return err
+	}
 
+	err = newAPIClient(config, token).CreateSecret(context.Background(), *secretPayload)
 	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("can`t save secret. Reason: %s", resp.String())
+	result := struct {
+		commandResult
+		GeneratedPassword string  `json:"generated_password,omitempty"`
+		Entropy           float64 `json:"entropy,omitempty"`
+	}{commandResult: commandResult{Status: "saved", Name: cmd.secretName}}
+	if cmd.generated {
+		result.GeneratedPassword = cmd.password
+		result.Entropy = cmd.entropy
 	}
-
-	fmt.Println("Secret saved successfully")
-	return nil
+	return printOutput(config, result, func(w io.Writer) error {
+		fmt.Fprintln(w, "Secret saved successfully")
+		if cmd.generated {
+			fmt.Fprintf(w, "Generated password: %s\n", cmd.password)
+			printEntropy(w, cmd.entropy)
+		}
+		return nil
+	})
 }
 
 // Fabric to create CREDENTIALS secret command
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/otp"
+	"strconv"
+	"strings"
+)
+
+// Command to save OTP secret
+type SaveOTPCommand struct {
+	secretName string
+	key        otp.Key
+	metadata   secretMetadata
+}
+
+func NewSaveOTPCommand(args map[string]string) (*SaveOTPCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name is required")
+	}
+
+	key, err := parseOTPKey(args)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseSecretMetadata(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SaveOTPCommand{
+		secretName: secretName,
+		key:        key,
+		metadata:   metadata,
+	}, nil
+}
+
+// Parse OTP key from otpauth URI or from separate parameters
+func parseOTPKey(args map[string]string) (otp.Key, error) {
+	if uri := args["uri"]; uri != "" {
+		if args["secret"] != "" {
+			return otp.Key{}, errors.New("uri and secret can`t be used together")
+		}
+		return otp.ParseURI(uri)
+	}
+	if args["secret"] == "" {
+		return otp.Key{}, errors.New("otpauth uri or otp secret is required")
+	}
+
+	key := otp.Key{
+		Type:      otp.TOTPType,
+		Secret:    args["secret"],
+		Issuer:    args["issuer"],
+		Account:   args["account"],
+		Algorithm: strings.ToUpper(args["algorithm"]),
+		Digits:    otp.DefaultDigits,
+		Period:    otp.DefaultPeriod,
+	}
+	if key.Algorithm == "" {
+		key.Algorithm = otp.SHA1Algorithm
+	}
+	if value := args["digits"]; value != "" {
+		digits, err := strconv.Atoi(value)
+		if err != nil {
+			return otp.Key{}, errors.New("digits should be a number")
+		}
+		key.Digits = digits
+	}
+	if value := args["period"]; value != "" {
+		period, err := strconv.Atoi(value)
+		if err != nil {
+			return otp.Key{}, errors.New("period should be a number of seconds")
+		}
+		key.Period = period
+	}
+
+	if err := key.Validate(); err != nil {
+		return otp.Key{}, err
+	}
+	return key, nil
+}
+
+func (cmd *SaveOTPCommand) Execute(config Config) error {
+	content, err := json.Marshal(cmd.key)
+	if err != nil {
+		return fmt.Errorf("can`t serialise otp key: %w", err)
+	}
+
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	encryptedData, err := crypto.EncryptData(content, []byte(config.EncryptionKey))
+	if err != nil {
+		return fmt.Errorf("error during encrypt data: %w", err)
+	}
+
+	secretPayload := &model.Secret{
+		Name:    cmd.secretName,
+		Type:    model.OTPSecretType,
+		Content: encryptedData,
+	}
+
+	if err := cmd.metadata.applyTo(secretPayload, []byte(config.EncryptionKey)); err != nil {
+		return err
+	}
+
+	err = newAPIClient(config, token).CreateSecret(context.Background(), *secretPayload)
+	if err != nil {
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
+	}
+
+	return printResult(config, commandResult{Status: "saved", Name: cmd.secretName}, "Secret saved successfully")
+}
+
+// Fabric to create OTP secret command
+type SaveOTPCommandFactory struct{}
+
+func (f *SaveOTPCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewSaveOTPCommand(args)
+}
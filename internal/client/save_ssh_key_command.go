@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"io"
+	"os"
+)
+
+// Command to save SSH_KEY secret, key is generated or read from file
+type SaveSSHKeyCommand struct {
+	secretName string
+	sshKey     model.SSHKey
+	metadata   secretMetadata
+}
+
+func NewSaveSSHKeyCommand(args map[string]string) (*SaveSSHKeyCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name is required")
+	}
+
+	generate, err := parseBoolFlag(args, "generate")
+	if err != nil {
+		return nil, err
+	}
+	privateKeyFile := args["private-key-file"]
+	if generate == (privateKeyFile != "") {
+		return nil, errors.New("either generate or private-key-file is required")
+	}
+
+	var sshKey model.SSHKey
+	if generate {
+		sshKey, err = generateSSHKey(args["comment"], args["passphrase"])
+	} else {
+		sshKey, err = readSSHKeyFile(privateKeyFile, args["comment"], args["passphrase"])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := parseSecretMetadata(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SaveSSHKeyCommand{
+		secretName: secretName,
+		sshKey:     sshKey,
+		metadata:   metadata,
+	}, nil
+}
+
+func readSSHKeyFile(path string, comment string, passphrase string) (model.SSHKey, error) {
+	privateKey, err := os.ReadFile(path)
+	if err != nil {
+		return model.SSHKey{}, fmt.Errorf("error during read private key file: %w", err)
+	}
+	return parseSSHKey(privateKey, comment, passphrase)
+}
+
+func (cmd *SaveSSHKeyCommand) Execute(config Config) error {
+	content, err := json.Marshal(cmd.sshKey)
+	if err != nil {
+		return fmt.Errorf("can`t serialise ssh key: %w", err)
+	}
+
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	encryptedData, err := crypto.EncryptData(content, []byte(config.EncryptionKey))
+	if err != nil {
+		return fmt.Errorf("error during encrypt data: %w", err)
+	}
+
+	secretPayload := &model.Secret{
+		Name:    cmd.secretName,
+		Type:    model.SSHKeySecretType,
+		Content: encryptedData,
+	}
+
+	if err := cmd.metadata.applyTo(secretPayload, []byte(config.EncryptionKey)); err != nil {
+		return err
+	}
+
+	err = newAPIClient(config, token).CreateSecret(context.Background(), *secretPayload)
+	if err != nil {
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
+	}
+
+	result := struct {
+		commandResult
+		PublicKey string `json:"public_key"`
+	}{commandResult{Status: "saved", Name: cmd.secretName}, cmd.sshKey.PublicKey}
+	return printOutput(config, result, func(w io.Writer) error {
+		fmt.Fprintf(w, "Secret saved successfully\nPublic key: %s\n", cmd.sshKey.PublicKey)
+		return nil
+	})
+}
+
+// Fabric to create SSH_KEY secret command
+type SaveSSHKeyCommandFactory struct{}
+
+func (f *SaveSSHKeyCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewSaveSSHKeyCommand(args)
+}
@@ -1,18 +1,18 @@
 package client
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
 	"github.com/desepticon55/gophkeeper/pkg/crypto"
-	"github.com/go-resty/resty/v2"
-	"time"
 )
 
 // Command to save TEXT secret
 type SaveTextCommand struct {
 	secretName string
 	data       string
+	metadata   secretMetadata
 }
 
 func NewSaveTextCommand(args map[string]string) (*SaveTextCommand, error) {
@@ -22,14 +22,20 @@ func NewSaveTextCommand(args map[string]string) (*SaveTextCommand, error) {
 		return nil, errors.New("secretName and data are required")
 	}
 
+	metadata, err := parseSecretMetadata(args)
+	if err != nil {
+		return nil, err
+	}
+
 	return &SaveTextCommand{
 		secretName: secretName,
 		data:       data,
+		metadata:   metadata,
 	}, nil
 }
 
 func (cmd *SaveTextCommand) Execute(config Config) error {
-	token, err := readTokenFromFile()
+	token, err := readToken(config)
 	if err != nil {
 		return fmt.Errorf("can`t find auth data: %w", err)
 	}
@@ -45,24 +51,16 @@ func (cmd *SaveTextCommand) Execute(config Config) error {
 		Content: encryptedData,
 	}
 
-	client := resty.New().SetTimeout(10 * time.Second)
-
-	resp, err := client.R().
-		SetHeader("Content-Type", "application/json").
-		SetHeader("Authorization", "Bearer "+token).
-		SetBody(secretPayload).
-		Post(config.ServerAddress + "/api/user/secret")
-
-	if err != nil {
-		return fmt.Errorf("error during send request: %w", err)
+	if err := cmd.metadata.applyTo(secretPayload, []byte(config.EncryptionKey)); err != nil {
+		return err
 	}
 
-	if resp.StatusCode() != 200 {
-		return fmt.Errorf("can`t save secret. Reason: %s", resp.String())
+	err = newAPIClient(config, token).CreateSecret(context.Background(), *secretPayload)
+	if err != nil {
+		return fmt.Errorf("can`t save secret. Reason: %w", err)
 	}
 
-	fmt.Println("Secret saved successfully")
-	return nil
+	return printResult(config, commandResult{Status: "saved", Name: cmd.secretName}, "Secret saved successfully")
 }
 
 // Fabric to create TEXT secret command
@@ -0,0 +1,135 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// Command to search secrets. Name, type and tag are filtered on server,
+// query is matched locally against decrypted content and notes
+type SearchCommand struct {
+	filter model.SecretFilter
+	query  string
+	limit  int
+}
+
+func NewSearchCommand(args map[string]string) (*SearchCommand, error) {
+	limit := 0
+	if value := args["limit"]; value != "" {
+		parsedLimit, err := strconv.Atoi(value)
+		if err != nil || parsedLimit < 0 {
+			return nil, errors.New("limit should be a positive number")
+		}
+		limit = parsedLimit
+	}
+
+	return &SearchCommand{
+		filter: model.SecretFilter{
+			Name:   args["name"],
+			Type:   strings.ToUpper(args["type"]),
+			Tag:    args["tag"],
+			Folder: args["folder"],
+		},
+		query: strings.ToLower(args["query"]),
+		limit: limit,
+	}, nil
+}
+
+func (cmd *SearchCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := newAPIClient(config, token)
+	key := []byte(config.EncryptionKey)
+
+	found := make([]secretListItem, 0)
+	filter := cmd.filter
+search:
+	for {
+		page, err := api.FindSecrets(context.Background(), filter)
+		if err != nil {
+			return err
+		}
+
+		for _, secret := range page.Secrets {
+			matched, err := matchSecret(secret, cmd.query, key)
+			if err != nil {
+				return err
+			}
+			if !matched {
+				continue
+			}
+
+			found = append(found, secretListItem{Name: secret.Name, Type: secret.Type, Tags: secret.Tags})
+			if cmd.limit > 0 && len(found) == cmd.limit {
+				break search
+			}
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	return printOutput(config, found, func(w io.Writer) error {
+		if len(found) == 0 {
+			_, err := fmt.Fprintln(w, "Secrets were not found")
+			return err
+		}
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, secret := range found {
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", secret.Name, secret.Type, strings.Join(secret.Tags, ","))
+		}
+		return writer.Flush()
+	})
+}
+
+// Secret found by search, without content
+type secretListItem struct {
+	Name string   `json:"name"`
+	Type string   `json:"type"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Check that query is a part of secret name, URL, tags or decrypted content and notes
+func matchSecret(secret model.Secret, query string, key []byte) (bool, error) {
+	if query == "" {
+		return true, nil
+	}
+
+	fields := []string{secret.Name, secret.URL, strings.Join(secret.Tags, " ")}
+	for _, encrypted := range [][]byte{secret.Content, secret.Notes} {
+		if len(encrypted) == 0 {
+			continue
+		}
+		data, err := crypto.DecryptData(encrypted, key)
+		if err != nil {
+			return false, fmt.Errorf("error during decrypt secret \"%s\": %w", secret.Name, err)
+		}
+		fields = append(fields, string(data))
+	}
+
+	for _, field := range fields {
+		if strings.Contains(strings.ToLower(field), query) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Fabric to create secret search command
+type SearchCommandFactory struct{}
+
+func (f *SearchCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewSearchCommand(args)
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMatchSecret(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	content, err := crypto.EncryptData([]byte("admin:qwerty"), key)
+	assert.NoError(t, err)
+	notes, err := crypto.EncryptData([]byte("Main database"), key)
+	assert.NoError(t, err)
+
+	secret := model.Secret{
+		Name:    "prod-db",
+		Content: content,
+		Notes:   notes,
+		Tags:    []string{"postgres"},
+		URL:     "https://db.example.com",
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected bool
+	}{
+		{name: "Empty query", query: "", expected: true},
+		{name: "Match name", query: "prod", expected: true},
+		{name: "Match URL", query: "example.com", expected: true},
+		{name: "Match tag", query: "postgres", expected: true},
+		{name: "Match content", query: "qwerty", expected: true},
+		{name: "Match notes", query: "main database", expected: true},
+		{name: "No match", query: "stage", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			matched, err := matchSecret(secret, tt.query, key)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, matched)
+		})
+	}
+}
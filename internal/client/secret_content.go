@@ -0,0 +1,289 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/card"
+	"github.com/desepticon55/gophkeeper/pkg/otp"
+	"io"
+	"strings"
+	"time"
+)
+
+// Field of secret content edited by user
+type contentField struct {
+	name      string
+	label     string
+	sensitive bool
+	// Field can be empty
+	optional bool
+	// Field is computed from content and is not used to build it
+	readOnly bool
+	// Hide value when secret is printed without reveal
+	mask func(value string) string
+}
+
+// Codec of secret type: splits decrypted content into fields and builds content from edited fields
+type secretCodec struct {
+	// Fields in display order, names match secret references
+	fields []contentField
+	// Field returned by reference without field, whole content when empty
+	defaultField string
+	decode       func(content []byte, now time.Time) (map[string]string, error)
+	// Validate field values and build content, nil when type can`t be edited
+	encode func(values map[string]string) ([]byte, error)
+}
+
+// Codecs of known secret types, other types are handled as text
+var secretCodecs = map[string]secretCodec{
+	model.CredentialsSecretType: {
+		fields: []contentField{
+			{name: "username", label: "Username"},
+			{name: "password", label: "Password", sensitive: true},
+		},
+		defaultField: "password",
+		decode: func(content []byte, now time.Time) (map[string]string, error) {
+			username, password, _ := strings.Cut(string(content), ":")
+			return map[string]string{"username": username, "password": password}, nil
+		},
+		encode: func(values map[string]string) ([]byte, error) {
+			if strings.Contains(values["username"], ":") {
+				return nil, errors.New("username can`t contain \":\"")
+			}
+			return []byte(values["username"] + ":" + values["password"]), nil
+		},
+	},
+	model.CardSecretType: {
+		fields: []contentField{
+			{name: "number", label: "Number", sensitive: true, mask: card.Mask},
+			{name: "brand", label: "Brand", readOnly: true},
+			{name: "date", label: "Date"},
+			{name: "code", label: "CVC", sensitive: true, mask: maskAll},
+			{name: "holder", label: "Holder"},
+		},
+		decode: func(content []byte, now time.Time) (map[string]string, error) {
+			var requisites model.Card
+			if err := json.Unmarshal(content, &requisites); err != nil {
+				return nil, fmt.Errorf("can`t parse card: %w", err)
+			}
+			return map[string]string{"number": requisites.Number, "brand": card.DetectBrand(requisites.Number),
+				"date": requisites.Date, "code": requisites.Code, "holder": requisites.Holder}, nil
+		},
+		encode: func(values map[string]string) ([]byte, error) {
+			requisites, err := validateCard(model.Card{Number: values["number"], Date: values["date"], Code: values["code"], Holder: values["holder"]})
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(requisites)
+		},
+	},
+	model.TextSecretType: textSecretCodec,
+	model.BinarySecretType: {
+		fields: []contentField{
+			{name: "base64", label: "Base64", sensitive: true},
+		},
+		decode: func(content []byte, now time.Time) (map[string]string, error) {
+			return map[string]string{"base64": base64.StdEncoding.EncodeToString(content)}, nil
+		},
+		encode: func(values map[string]string) ([]byte, error) {
+			content, err := base64.StdEncoding.DecodeString(values["base64"])
+			if err != nil {
+				return nil, errors.New("base64 field has invalid data")
+			}
+			return content, nil
+		},
+	},
+	model.OTPSecretType: {
+		fields: []contentField{
+			{name: "uri", label: "otpauth URI", sensitive: true},
+			{name: "secret", label: "Secret", sensitive: true, readOnly: true},
+			{name: "code", label: "Code", readOnly: true},
+		},
+		defaultField: "code",
+		decode: func(content []byte, now time.Time) (map[string]string, error) {
+			var key otp.Key
+			if err := json.Unmarshal(content, &key); err != nil {
+				return nil, fmt.Errorf("can`t parse otp key: %w", err)
+			}
+			values := map[string]string{"secret": key.Secret, "uri": key.URI()}
+			// Code of HOTP key advances its counter, so it is generated only by otp command
+			if key.Type == otp.HOTPType {
+				return values, nil
+			}
+			code, err := key.Code(now)
+			if err != nil {
+				return nil, fmt.Errorf("can`t generate code: %w", err)
+			}
+			values["code"] = code
+			return values, nil
+		},
+		encode: func(values map[string]string) ([]byte, error) {
+			key, err := otp.ParseURI(values["uri"])
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(key)
+		},
+	},
+	model.SSHKeySecretType: {
+		fields: []contentField{
+			{name: "public_key", label: "Public key", readOnly: true},
+			{name: "comment", label: "Comment", optional: true},
+			{name: "private_key", label: "Private key", sensitive: true},
+			{name: "passphrase", label: "Passphrase", sensitive: true, optional: true},
+		},
+		decode: func(content []byte, now time.Time) (map[string]string, error) {
+			var sshKey model.SSHKey
+			if err := json.Unmarshal(content, &sshKey); err != nil {
+				return nil, fmt.Errorf("can`t parse ssh key: %w", err)
+			}
+			return map[string]string{"private_key": sshKey.PrivateKey, "public_key": sshKey.PublicKey,
+				"comment": sshKey.Comment, "passphrase": sshKey.Passphrase}, nil
+		},
+		encode: func(values map[string]string) ([]byte, error) {
+			sshKey, err := parseSSHKey([]byte(values["private_key"]), values["comment"], values["passphrase"])
+			if err != nil {
+				return nil, err
+			}
+			return json.Marshal(sshKey)
+		},
+	},
+}
+
+// Codec of TEXT secrets and secrets of unknown types
+var textSecretCodec = secretCodec{
+	fields: []contentField{
+		{name: "value", label: "Text", sensitive: true},
+	},
+	decode: func(content []byte, now time.Time) (map[string]string, error) {
+		return map[string]string{"value": string(content)}, nil
+	},
+	encode: func(values map[string]string) ([]byte, error) {
+		return []byte(values["value"]), nil
+	},
+}
+
+func maskAll(value string) string {
+	return strings.Repeat("*", len(value))
+}
+
+func findSecretCodec(secretType string) secretCodec {
+	if codec, ok := secretCodecs[secretType]; ok {
+		return codec
+	}
+	return textSecretCodec
+}
+
+// Secret types which can be created and edited field by field in terminal interface
+var editableSecretTypes = []string{model.CredentialsSecretType, model.CardSecretType, model.TextSecretType, model.OTPSecretType}
+
+func isEditableSecretType(secretType string) bool {
+	for _, editable := range editableSecretTypes {
+		if secretType == editable {
+			return true
+		}
+	}
+	return false
+}
+
+// Fields of secret type which are used to build content
+func editableFields(secretType string) []contentField {
+	var fields []contentField
+	for _, field := range findSecretCodec(secretType).fields {
+		if !field.readOnly {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// Build plain secret content from field values
+func encodeSecretContent(secretType string, values map[string]string) ([]byte, error) {
+	codec := findSecretCodec(secretType)
+	if codec.encode == nil {
+		return nil, fmt.Errorf("secret type %s can`t be edited", secretType)
+	}
+	for _, field := range editableFields(secretType) {
+		if values[field.name] == "" && !field.optional {
+			return nil, fmt.Errorf("%s is required", field.label)
+		}
+	}
+	return codec.encode(values)
+}
+
+// Decrypt content of secret editable in terminal interface into field values
+func decodeSecretContent(secret model.Secret, key []byte) (map[string]string, error) {
+	if !isEditableSecretType(secret.Type) {
+		return nil, fmt.Errorf("secret type %s can`t be edited", secret.Type)
+	}
+	return decodeSecretFields(secret, editableFields(secret.Type), key)
+}
+
+func decodeSecretFields(secret model.Secret, fields []contentField, key []byte) (map[string]string, error) {
+	if len(secret.Content) == 0 {
+		return nil, errors.New("content of one-time secret is not listed")
+	}
+
+	values := make(map[string]string, len(fields))
+	for _, field := range fields {
+		value, err := secretField(secret, field.name, key, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		values[field.name] = value
+	}
+	return values, nil
+}
+
+// Replace values of fields which are hidden until reveal
+func maskSecretFields(secretType string, values map[string]string) map[string]string {
+	masked := make(map[string]string, len(values))
+	for name, value := range values {
+		masked[name] = value
+	}
+	for _, field := range findSecretCodec(secretType).fields {
+		if value, ok := masked[field.name]; ok && field.mask != nil {
+			masked[field.name] = field.mask(value)
+		}
+	}
+	return masked
+}
+
+// Mask one field of secret printed without reveal. Whole content of secret type with masked fields
+// is refused, because masked fields can't be hidden inside it
+func maskSecretField(secretType string, field string, value string) (string, error) {
+	codec := findSecretCodec(secretType)
+	if field == "" {
+		field = codec.defaultField
+	}
+	for _, contentField := range codec.fields {
+		if contentField.mask == nil {
+			continue
+		}
+		if contentField.name == field {
+			return contentField.mask(value), nil
+		}
+		if field == "" || field == "value" {
+			return "", fmt.Errorf("content of %s secret has hidden fields, add --reveal to print it", secretType)
+		}
+	}
+	return value, nil
+}
+
+// Print decoded fields of secret type with labels
+func renderSecretFields(w io.Writer, secretType string, values map[string]string) {
+	for _, field := range findSecretCodec(secretType).fields {
+		value, ok := values[field.name]
+		if !ok || value == "" && field.optional {
+			continue
+		}
+		if strings.Contains(value, "\n") {
+			fmt.Fprintf(w, "%s:\n%s\n", field.label, strings.TrimRight(value, "\n"))
+			continue
+		}
+		fmt.Fprintf(w, "%s: %s\n", field.label, value)
+	}
+}
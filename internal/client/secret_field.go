@@ -0,0 +1,144 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Separator of secret name and field in secret reference, e.g. prod/db#password
+const secretFieldSeparator = "#"
+
+// Reference to secret field
+type secretReference struct {
+	name  string
+	field string
+}
+
+// Parse secret reference in name#field format, field is optional. Suffix after the last separator is a field
+// only when it is a known field name, so names with separator are kept, e.g. visa#1. Empty field is explicit
+// reference without field: visa#password# is secret visa#password
+func parseSecretReference(value string) (secretReference, error) {
+	name, field := value, ""
+	if index := strings.LastIndex(value, secretFieldSeparator); index >= 0 {
+		suffix := value[index+1:]
+		if suffix == "" || isSecretFieldName(suffix) {
+			name, field = value[:index], suffix
+		}
+	}
+	if name == "" {
+		return secretReference{}, fmt.Errorf("secret name is required in reference \"%s\"", value)
+	}
+	return secretReference{name: name, field: field}, nil
+}
+
+// Field of any secret type, including metadata fields
+func isSecretFieldName(name string) bool {
+	switch name {
+	case "name", "url", "note", "value":
+		return true
+	}
+	for _, codec := range secretCodecs {
+		for _, field := range codec.fields {
+			if field.name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Decrypt secret and return one field of its content. Without field password of CREDENTIALS,
+// current code of OTP and whole content of other types is returned. Code of HOTP is not returned,
+// because it must advance counter stored on server
+func secretField(secret model.Secret, field string, key []byte, now time.Time) (string, error) {
+	switch field {
+	case "name":
+		return secret.Name, nil
+	case "url":
+		return secret.URL, nil
+	case "note":
+		if len(secret.Notes) == 0 {
+			return "", nil
+		}
+		note, err := crypto.DecryptData(secret.Notes, key)
+		if err != nil {
+			return "", fmt.Errorf("error during decrypt note: %w", err)
+		}
+		return string(note), nil
+	}
+
+	content, fields, defaultField, err := decodeContentFields(secret, key, now)
+	if err != nil {
+		return "", err
+	}
+
+	if field == "" {
+		field = defaultField
+	}
+	if field == "" || field == "value" {
+		return string(content), nil
+	}
+
+	value, ok := fields[field]
+	if !ok && field == "code" && secret.Type == model.OTPSecretType {
+		return "", fmt.Errorf("code of HOTP secret %s advances its counter, use secret otp --name=%s", secret.Name, secret.Name)
+	}
+	if !ok {
+		names := []string{"name", "url", "note"}
+		if _, ok := fields["value"]; !ok {
+			names = append(names, "value")
+		}
+		for name := range fields {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return "", fmt.Errorf("secret %s of type %s has no field \"%s\", available fields: %s",
+			secret.Name, secret.Type, field, strings.Join(names, ", "))
+	}
+	return value, nil
+}
+
+// Decrypt secret content and split it into fields of secret type. Default field is returned
+// by reference without field, whole content is returned when it is empty
+func decodeContentFields(secret model.Secret, key []byte, now time.Time) ([]byte, map[string]string, string, error) {
+	content, err := crypto.DecryptData(secret.Content, key)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("error during decrypt content: %w", err)
+	}
+
+	codec := findSecretCodec(secret.Type)
+	fields, err := codec.decode(content, now)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return content, fields, codec.defaultField, nil
+}
+
+// Resolver of secret references, each secret is read from server once
+type secretResolver struct {
+	api     *apiClient
+	config  Config
+	secrets map[string]model.Secret
+}
+
+func newSecretResolver(api *apiClient, config Config) *secretResolver {
+	return &secretResolver{api: api, config: config, secrets: make(map[string]model.Secret)}
+}
+
+func (r *secretResolver) resolve(reference secretReference) (string, error) {
+	secret, ok := r.secrets[reference.name]
+	if !ok {
+		var err error
+		secret, err = r.api.FindSecret(context.Background(), reference.name)
+		if err != nil {
+			return "", fmt.Errorf("secret %s: %w", reference.name, err)
+		}
+		r.secrets[reference.name] = secret
+	}
+	return secretField(secret, reference.field, []byte(r.config.EncryptionKey), time.Now())
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/otp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParseSecretReference(t *testing.T) {
+	reference, err := parseSecretReference("prod/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "prod/db", field: "password"}, reference)
+
+	reference, err = parseSecretReference("a#b#username")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "a#b", field: "username"}, reference)
+
+	reference, err = parseSecretReference("prod/api-key")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "prod/api-key"}, reference)
+
+	reference, err = parseSecretReference("visa#1")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "visa#1"}, reference)
+
+	reference, err = parseSecretReference("visa#1#number")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "visa#1", field: "number"}, reference)
+
+	reference, err = parseSecretReference("notes#password#")
+	require.NoError(t, err)
+	assert.Equal(t, secretReference{name: "notes#password"}, reference)
+
+	_, err = parseSecretReference("#password")
+	assert.Error(t, err)
+}
+
+func TestSecretField(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+	encrypt := func(content string) []byte {
+		encrypted, err := crypto.EncryptData([]byte(content), key)
+		require.NoError(t, err)
+		return encrypted
+	}
+	marshal := func(value interface{}) string {
+		content, err := json.Marshal(value)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	credentials := model.Secret{Name: "prod/db", Type: model.CredentialsSecretType, Content: encrypt("admin:p:ss"),
+		Notes: encrypt("primary"), URL: "postgres://db"}
+	card := model.Secret{Name: "card", Type: model.CardSecretType,
+		Content: encrypt(marshal(model.Card{Number: "4111111111111111", Date: "12/30", Code: "123", Holder: "IVAN"}))}
+	text := model.Secret{Name: "token", Type: model.TextSecretType, Content: encrypt("api-token")}
+	totp := model.Secret{Name: "otp", Type: model.OTPSecretType, Content: encrypt(marshal(otp.Key{
+		Type: otp.TOTPType, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Algorithm: otp.SHA1Algorithm, Digits: 8, Period: 30}))}
+	hotp := model.Secret{Name: "hotp", Type: model.OTPSecretType, Content: encrypt(marshal(otp.Key{
+		Type: otp.HOTPType, Secret: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ", Algorithm: otp.SHA1Algorithm, Digits: 6, Counter: 1}))}
+
+	testCases := []struct {
+		name      string
+		secret    model.Secret
+		field     string
+		expected  string
+		wantError bool
+	}{
+		{name: "Credentials default", secret: credentials, expected: "p:ss"},
+		{name: "Credentials username", secret: credentials, field: "username", expected: "admin"},
+		{name: "Credentials value", secret: credentials, field: "value", expected: "admin:p:ss"},
+		{name: "Note", secret: credentials, field: "note", expected: "primary"},
+		{name: "URL", secret: credentials, field: "url", expected: "postgres://db"},
+		{name: "Card code", secret: card, field: "code", expected: "123"},
+		{name: "Text default", secret: text, expected: "api-token"},
+		{name: "OTP code", secret: totp, expected: "94287082"},
+		{name: "HOTP default", secret: hotp, wantError: true},
+		{name: "HOTP code", secret: hotp, field: "code", wantError: true},
+		{name: "HOTP secret", secret: hotp, field: "secret", expected: "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"},
+		{name: "Unknown field", secret: credentials, field: "pin", wantError: true},
+		{name: "Text has no fields", secret: text, field: "password", wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			value, err := secretField(tc.secret, tc.field, key, time.Unix(59, 0))
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, value)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package client
+
+import "net"
+
+// Create unix socket, permissions are set after listen as umask is not supported
+func listenPrivateSocket(path string) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
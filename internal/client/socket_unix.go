@@ -0,0 +1,16 @@
+//go:build unix
+
+package client
+
+import (
+	"net"
+	"syscall"
+)
+
+// Create unix socket with permissions of current user only. Umask is changed for the whole process,
+// so socket is listened before any concurrent file creation
+func listenPrivateSocket(path string) (net.Listener, error) {
+	mask := syscall.Umask(0077)
+	defer syscall.Umask(mask)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+)
+
+// Command to serve SSH_KEY secrets over unix socket via ssh-agent protocol.
+// Keys are kept in memory only and are never written to disk
+type SSHAgentCommand struct {
+	socket string
+	folder string
+}
+
+func NewSSHAgentCommand(args map[string]string) (*SSHAgentCommand, error) {
+	socket := args["socket"]
+	if socket == "" {
+		socket = runtimeSocketPath("ssh-agent")
+	}
+
+	return &SSHAgentCommand{socket: socket, folder: args["folder"]}, nil
+}
+
+func (cmd *SSHAgentCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	secrets, err := cmd.findSSHKeys(newAPIClient(config, token))
+	if err != nil {
+		return err
+	}
+
+	keyring := agent.NewKeyring()
+	added, err := addSSHKeys(keyring, secrets, []byte(config.EncryptionKey))
+	if err != nil {
+		return err
+	}
+	if added == 0 {
+		return errors.New("no SSH_KEY secrets were found")
+	}
+
+	listener, err := listenUnixSocket(cmd.socket)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(cmd.socket)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-signals
+		listener.Close()
+	}()
+
+	fmt.Printf("Agent serves %d keys\n", added)
+	fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", cmd.socket)
+
+	if err := serveSSHAgent(listener, keyring); err != nil {
+		return err
+	}
+	fmt.Println("Agent was stopped")
+	return nil
+}
+
+// Serve ssh-agent protocol until listener is closed, connections of other users are rejected
+func serveSSHAgent(listener net.Listener, keyring agent.Agent) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error during accept agent connection: %w", err)
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := checkAgentPeer(conn); err != nil {
+				fmt.Fprintf(os.Stderr, "Connection was rejected: %s\n", err)
+				return
+			}
+			agent.ServeAgent(keyring, conn)
+		}()
+	}
+}
+
+// Read all SSH_KEY secrets, one-time secrets are skipped as their content is not listed
+func (cmd *SSHAgentCommand) findSSHKeys(api *apiClient) ([]model.Secret, error) {
+	found, err := api.FindAllSecrets(context.Background(), model.SecretFilter{Type: model.SSHKeySecretType, Folder: cmd.folder})
+	if err != nil {
+		return nil, err
+	}
+
+	var secrets []model.Secret
+	for _, secret := range found {
+		if len(secret.Content) > 0 {
+			secrets = append(secrets, secret)
+		}
+	}
+	return secrets, nil
+}
+
+// Socket in user runtime dir, temp dir is used if runtime dir is not set
+func runtimeSocketPath(name string) string {
+	dir := os.Getenv("XDG_RUNTIME_DIR")
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	return filepath.Join(dir, fmt.Sprintf("gophkeeper-%s-%d.sock", name, os.Getuid()))
+}
+
+// Listen unix socket available to current user only, stale socket file is replaced. Socket is created
+// with private permissions, so it is never accessible to other users before chmod
+func listenUnixSocket(path string) (net.Listener, error) {
+	if info, err := os.Lstat(path); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("can`t remove stale socket: %w", err)
+		}
+	}
+
+	listener, err := listenPrivateSocket(path)
+	if err != nil {
+		return nil, fmt.Errorf("can`t listen socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, fmt.Errorf("can`t change socket permissions: %w", err)
+	}
+	return listener, nil
+}
+
+// Fabric to create ssh-agent command
+type SSHAgentCommandFactory struct{}
+
+func (f *SSHAgentCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewSSHAgentCommand(args)
+}
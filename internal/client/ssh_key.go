@@ -0,0 +1,112 @@
+package client
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"strings"
+)
+
+// Generate Ed25519 key, private key is protected by passphrase if it is not empty
+func generateSSHKey(comment string, passphrase string) (model.SSHKey, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return model.SSHKey{}, fmt.Errorf("can`t generate ssh key: %w", err)
+	}
+
+	var block *pem.Block
+	if passphrase != "" {
+		block, err = ssh.MarshalPrivateKeyWithPassphrase(privateKey, comment, []byte(passphrase))
+	} else {
+		block, err = ssh.MarshalPrivateKey(privateKey, comment)
+	}
+	if err != nil {
+		return model.SSHKey{}, fmt.Errorf("can`t serialise ssh key: %w", err)
+	}
+
+	return parseSSHKey(pem.EncodeToMemory(block), comment, passphrase)
+}
+
+// Parse private key in PEM format and fill public key
+func parseSSHKey(privateKey []byte, comment string, passphrase string) (model.SSHKey, error) {
+	key := model.SSHKey{PrivateKey: string(privateKey), Comment: comment, Passphrase: passphrase}
+	rawKey, err := rawSSHPrivateKey(key)
+	if err != nil {
+		return model.SSHKey{}, err
+	}
+
+	signer, err := ssh.NewSignerFromKey(rawKey)
+	if err != nil {
+		return model.SSHKey{}, fmt.Errorf("unsupported ssh key: %w", err)
+	}
+	publicKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.PublicKey())))
+	if comment != "" {
+		publicKey += " " + comment
+	}
+	key.PublicKey = publicKey
+	return key, nil
+}
+
+func rawSSHPrivateKey(key model.SSHKey) (interface{}, error) {
+	var rawKey interface{}
+	var err error
+	if key.Passphrase != "" {
+		rawKey, err = ssh.ParseRawPrivateKeyWithPassphrase([]byte(key.PrivateKey), []byte(key.Passphrase))
+	} else {
+		rawKey, err = ssh.ParseRawPrivateKey([]byte(key.PrivateKey))
+	}
+
+	var passphraseErr *ssh.PassphraseMissingError
+	if errors.As(err, &passphraseErr) {
+		return nil, errors.New("ssh key is protected by passphrase, passphrase is required")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can`t parse ssh key: %w", err)
+	}
+	return rawKey, nil
+}
+
+func decryptSSHKey(secret model.Secret, key []byte) (model.SSHKey, error) {
+	content, err := crypto.DecryptData(secret.Content, key)
+	if err != nil {
+		return model.SSHKey{}, fmt.Errorf("error during decrypt content: %w", err)
+	}
+
+	var sshKey model.SSHKey
+	if err := json.Unmarshal(content, &sshKey); err != nil {
+		return model.SSHKey{}, fmt.Errorf("can`t parse ssh key: %w", err)
+	}
+	return sshKey, nil
+}
+
+// Add decrypted keys to in-memory agent keyring, secret name is used as comment by default
+func addSSHKeys(keyring agent.Agent, secrets []model.Secret, key []byte) (int, error) {
+	added := 0
+	for _, secret := range secrets {
+		sshKey, err := decryptSSHKey(secret, key)
+		if err != nil {
+			return added, fmt.Errorf("secret %s: %w", secret.Name, err)
+		}
+		rawKey, err := rawSSHPrivateKey(sshKey)
+		if err != nil {
+			return added, fmt.Errorf("secret %s: %w", secret.Name, err)
+		}
+
+		comment := sshKey.Comment
+		if comment == "" {
+			comment = secret.Name
+		}
+		if err := keyring.Add(agent.AddedKey{PrivateKey: rawKey, Comment: comment}); err != nil {
+			return added, fmt.Errorf("secret %s: can`t add key to agent: %w", secret.Name, err)
+		}
+		added++
+	}
+	return added, nil
+}
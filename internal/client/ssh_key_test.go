@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	key, err := generateSSHKey("deploy@ci", "")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key.PublicKey, "ssh-ed25519 "))
+	assert.True(t, strings.HasSuffix(key.PublicKey, " deploy@ci"))
+	assert.Contains(t, key.PrivateKey, "BEGIN OPENSSH PRIVATE KEY")
+
+	protected, err := generateSSHKey("", "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, "passphrase", protected.Passphrase)
+
+	_, err = parseSSHKey([]byte(protected.PrivateKey), "", "")
+	assert.Error(t, err)
+	_, err = parseSSHKey([]byte(protected.PrivateKey), "", "wrong")
+	assert.Error(t, err)
+	parsed, err := parseSSHKey([]byte(protected.PrivateKey), "", "passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, protected.PublicKey, parsed.PublicKey)
+
+	_, err = parseSSHKey([]byte("not a key"), "", "")
+	assert.Error(t, err)
+}
+
+func TestSSHAgent(t *testing.T) {
+	encryptionKey := []byte("12345678901234567890123456789012")
+	var secrets []model.Secret
+	for _, passphrase := range []string{"", "passphrase"} {
+		sshKey, err := generateSSHKey("", passphrase)
+		require.NoError(t, err)
+		content, err := json.Marshal(sshKey)
+		require.NoError(t, err)
+		encrypted, err := crypto.EncryptData(content, encryptionKey)
+		require.NoError(t, err)
+		secrets = append(secrets, model.Secret{Name: "deploy" + passphrase, Type: model.SSHKeySecretType, Content: encrypted})
+	}
+
+	keyring := agent.NewKeyring()
+	added, err := addSSHKeys(keyring, secrets, encryptionKey)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+
+	socket := filepath.Join(t.TempDir(), "agent.sock")
+	listener, err := listenUnixSocket(socket)
+	require.NoError(t, err)
+	defer listener.Close()
+	go serveSSHAgent(listener, keyring)
+
+	info, err := os.Stat(socket)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	_, err = listenUnixSocket(socket)
+	assert.Error(t, err, "socket in use should not be replaced")
+
+	conn, err := net.Dial("unix", socket)
+	require.NoError(t, err)
+	defer conn.Close()
+	agentClient := agent.NewClient(conn)
+
+	keys, err := agentClient.List()
+	require.NoError(t, err)
+	require.Len(t, keys, 2)
+	assert.Equal(t, "deploy", keys[0].Comment)
+
+	data := []byte("data to sign")
+	signature, err := agentClient.Sign(keys[1], data)
+	require.NoError(t, err)
+	publicKey, err := ssh.ParsePublicKey(keys[1].Marshal())
+	require.NoError(t, err)
+	assert.NoError(t, publicKey.Verify(data, signature))
+}
@@ -0,0 +1,136 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+)
+
+// Command to list secrets in trash
+type TrashListCommand struct{}
+
+func NewTrashListCommand(args map[string]string) (*TrashListCommand, error) {
+	return &TrashListCommand{}, nil
+}
+
+func (cmd *TrashListCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	secrets, err := newAPIClient(config, token).FindTrash(context.Background())
+	if err != nil {
+		return fmt.Errorf("can`t read trash. Reason: %w", err)
+	}
+
+	items := make([]trashItem, 0, len(secrets))
+	for _, secret := range secrets {
+		items = append(items, trashItem{Name: secret.Name, Type: secret.Type, DeletedAt: secret.DeletedAt})
+	}
+
+	return printOutput(config, items, func(w io.Writer) error {
+		if len(items) == 0 {
+			_, err := fmt.Fprintln(w, "Trash is empty")
+			return err
+		}
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		for _, item := range items {
+			deletedAt := ""
+			if item.DeletedAt != nil {
+				deletedAt = item.DeletedAt.Local().Format(time.DateTime)
+			}
+			fmt.Fprintf(writer, "%s\t%s\t%s\n", item.Name, item.Type, deletedAt)
+		}
+		return writer.Flush()
+	})
+}
+
+// Secret in trash, without content
+type trashItem struct {
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Fabric to create trash list command
+type TrashListCommandFactory struct{}
+
+func (f *TrashListCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewTrashListCommand(args)
+}
+
+// Command to restore secret from trash
+type RestoreCommand struct {
+	secretName string
+}
+
+func NewRestoreCommand(args map[string]string) (*RestoreCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name are required")
+	}
+
+	return &RestoreCommand{secretName: secretName}, nil
+}
+
+func (cmd *RestoreCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	err = newAPIClient(config, token).RestoreSecret(context.Background(), cmd.secretName)
+	if err != nil {
+		return fmt.Errorf("can`t restore secret. Reason: %w", err)
+	}
+
+	return printResult(config, commandResult{Status: "restored", Name: cmd.secretName},
+		fmt.Sprintf("Secret with name \"%s\" was restored", cmd.secretName))
+}
+
+// Fabric to create secret restore command
+type RestoreCommandFactory struct{}
+
+func (f *RestoreCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewRestoreCommand(args)
+}
+
+// Command to permanently delete secret from trash
+type PurgeCommand struct {
+	secretName string
+}
+
+func NewPurgeCommand(args map[string]string) (*PurgeCommand, error) {
+	secretName, ok := args["name"]
+	if !ok || secretName == "" {
+		return nil, errors.New("secret name are required")
+	}
+
+	return &PurgeCommand{secretName: secretName}, nil
+}
+
+func (cmd *PurgeCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	err = newAPIClient(config, token).PurgeSecret(context.Background(), cmd.secretName)
+	if err != nil {
+		return fmt.Errorf("can`t purge secret. Reason: %w", err)
+	}
+
+	return printResult(config, commandResult{Status: "purged", Name: cmd.secretName},
+		fmt.Sprintf("Secret with name \"%s\" was permanently deleted", cmd.secretName))
+}
+
+// Fabric to create secret purge command
+type PurgeCommandFactory struct{}
+
+func (f *PurgeCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewPurgeCommand(args)
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+)
+
+// Vault operations used by terminal interface
+type vaultAPI interface {
+	list() ([]model.Secret, error)
+	create(secret model.Secret) error
+	save(secret model.Secret) error
+	delete(name string) error
+}
+
+// Vault operations backed by server API
+type serverVaultAPI struct {
+	api *apiClient
+}
+
+func (v *serverVaultAPI) list() ([]model.Secret, error) {
+	return v.api.FindAllSecrets(context.Background(), model.SecretFilter{})
+}
+
+func (v *serverVaultAPI) create(secret model.Secret) error {
+	return v.api.CreateSecret(context.Background(), secret)
+}
+
+// Save edited secret with version from list, secret changed by another client is not overwritten
+func (v *serverVaultAPI) save(secret model.Secret) error {
+	err := updateSecret(context.Background(), v.api, secret)
+	if errors.Is(err, errSecretChanged) {
+		return fmt.Errorf("%w, press esc and r to reload", err)
+	}
+	return err
+}
+
+func (v *serverVaultAPI) delete(name string) error {
+	err := v.api.DeleteSecret(context.Background(), name)
+	if errors.Is(err, gophkeeperclient.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Command to browse and edit vault in terminal interface
+type UICommand struct{}
+
+func NewUICommand(args map[string]string) (*UICommand, error) {
+	return &UICommand{}, nil
+}
+
+func (cmd *UICommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := &serverVaultAPI{api: newAPIClient(config, token)}
+	program := tea.NewProgram(newUIModel(api, []byte(config.EncryptionKey)), tea.WithAltScreen())
+	if _, err := program.Run(); err != nil {
+		return fmt.Errorf("error during run terminal interface: %w", err)
+	}
+	return nil
+}
+
+// Fabric to create terminal interface command
+type UICommandFactory struct{}
+
+func (f *UICommandFactory) Create(args map[string]string) (Command, error) {
+	return NewUICommand(args)
+}
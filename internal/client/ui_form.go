@@ -0,0 +1,184 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"strings"
+)
+
+// Result of key handling by form
+type formAction int
+
+const (
+	formContinue formAction = iota
+	formSubmit
+	formCancel
+)
+
+// Form to create or edit secret of one type
+type secretForm struct {
+	secret   model.Secret
+	isNew    bool
+	fields   []contentField
+	inputs   []textinput.Model
+	focus    int
+	revealed bool
+	err      error
+}
+
+func newSecretForm(secret model.Secret, isNew bool, key []byte) (*secretForm, error) {
+	if !isEditableSecretType(secret.Type) {
+		return nil, fmt.Errorf("secret type %s can`t be edited", secret.Type)
+	}
+	typeFields := editableFields(secret.Type)
+
+	values := map[string]string{"url": secret.URL}
+	if !isNew {
+		contentValues, err := decodeSecretContent(secret, key)
+		if err != nil {
+			return nil, err
+		}
+		values = contentValues
+		values["url"] = secret.URL
+		if len(secret.Notes) > 0 {
+			note, err := crypto.DecryptData(secret.Notes, key)
+			if err != nil {
+				return nil, fmt.Errorf("error during decrypt note: %w", err)
+			}
+			values["note"] = string(note)
+		}
+		// inputs are single-line, so multi-line value would lose its line breaks on save
+		for name, value := range values {
+			if strings.Contains(value, "\n") {
+				return nil, fmt.Errorf("field %s of secret %s is multi-line, use `secret edit --name=%s` instead",
+					name, secret.Name, secret.Name)
+			}
+		}
+	}
+
+	var fields []contentField
+	if isNew {
+		fields = append(fields, contentField{name: "name", label: "Name"})
+	}
+	fields = append(fields, typeFields...)
+	fields = append(fields, contentField{name: "url", label: "URL"}, contentField{name: "note", label: "Note"})
+
+	form := &secretForm{secret: secret, isNew: isNew, fields: fields}
+	for _, field := range fields {
+		input := textinput.New()
+		input.Prompt = ""
+		input.Width = 60
+		input.SetValue(values[field.name])
+		if field.sensitive {
+			input.EchoMode = textinput.EchoPassword
+		}
+		form.inputs = append(form.inputs, input)
+	}
+	form.inputs[0].Focus()
+	return form, nil
+}
+
+func (f *secretForm) update(msg tea.KeyMsg) (tea.Cmd, formAction) {
+	switch msg.String() {
+	case "esc":
+		return nil, formCancel
+	case "ctrl+s":
+		return nil, formSubmit
+	case "enter":
+		if f.focus == len(f.inputs)-1 {
+			return nil, formSubmit
+		}
+		return f.moveFocus(1), formContinue
+	case "tab", "down":
+		return f.moveFocus(1), formContinue
+	case "shift+tab", "up":
+		return f.moveFocus(-1), formContinue
+	case "ctrl+r":
+		f.revealed = !f.revealed
+		for i, field := range f.fields {
+			if field.sensitive && !f.revealed {
+				f.inputs[i].EchoMode = textinput.EchoPassword
+			} else {
+				f.inputs[i].EchoMode = textinput.EchoNormal
+			}
+		}
+		return nil, formContinue
+	}
+
+	var cmd tea.Cmd
+	f.inputs[f.focus], cmd = f.inputs[f.focus].Update(msg)
+	return cmd, formContinue
+}
+
+func (f *secretForm) moveFocus(delta int) tea.Cmd {
+	f.inputs[f.focus].Blur()
+	f.focus = (f.focus + delta + len(f.inputs)) % len(f.inputs)
+	return f.inputs[f.focus].Focus()
+}
+
+// Build encrypted secret from form values, other metadata of edited secret is kept
+func (f *secretForm) build(key []byte) (model.Secret, error) {
+	// only name and URL are trimmed, spaces of passwords, codes and text are part of value
+	values := make(map[string]string, len(f.fields))
+	for i, field := range f.fields {
+		value := f.inputs[i].Value()
+		if field.name == "name" || field.name == "url" {
+			value = strings.TrimSpace(value)
+		}
+		values[field.name] = value
+	}
+
+	secret := f.secret
+	if f.isNew {
+		if values["name"] == "" {
+			return model.Secret{}, errors.New("name is required")
+		}
+		secret.Name = values["name"]
+	}
+
+	content, err := encodeSecretContent(secret.Type, values)
+	if err != nil {
+		return model.Secret{}, err
+	}
+	secret.Content, err = crypto.EncryptData(content, key)
+	if err != nil {
+		return model.Secret{}, fmt.Errorf("error during encrypt data: %w", err)
+	}
+
+	secret.Notes = nil
+	if values["note"] != "" {
+		secret.Notes, err = crypto.EncryptData([]byte(values["note"]), key)
+		if err != nil {
+			return model.Secret{}, fmt.Errorf("error during encrypt note: %w", err)
+		}
+	}
+	secret.URL = values["url"]
+	return secret, nil
+}
+
+func (f *secretForm) view() string {
+	var view strings.Builder
+	title := "Edit " + f.secret.Name
+	if f.isNew {
+		title = "New " + f.secret.Type
+	}
+	view.WriteString(uiTitleStyle.Render(title) + "\n\n")
+
+	for i, field := range f.fields {
+		label := fmt.Sprintf("%-12s", field.label)
+		if i == f.focus {
+			label = uiSelectedStyle.Render(label)
+		}
+		view.WriteString(label + " " + f.inputs[i].View() + "\n")
+	}
+
+	if f.err != nil {
+		view.WriteString("\n" + uiErrorStyle.Render(f.err.Error()) + "\n")
+	}
+	view.WriteString("\n" + uiHelpStyle.Render("tab/↑/↓ move · ctrl+r reveal · ctrl+s or enter on last field save · esc cancel"))
+	return view.String()
+}
@@ -0,0 +1,424 @@
+package client
+
+import (
+	"fmt"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Screen of terminal interface
+type uiState int
+
+const (
+	uiListState uiState = iota
+	uiDetailState
+	uiTypeState
+	uiFormState
+	uiDeleteState
+)
+
+// Mask of hidden sensitive values
+const uiMask = "••••••••"
+
+var (
+	uiTitleStyle    = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("12"))
+	uiSelectedStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("10"))
+	uiHelpStyle     = lipgloss.NewStyle().Faint(true)
+	uiErrorStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))
+)
+
+type secretsLoadedMsg struct {
+	secrets []model.Secret
+	err     error
+}
+
+type secretSavedMsg struct {
+	name string
+	err  error
+}
+
+type secretDeletedMsg struct {
+	name string
+	err  error
+}
+
+// Bubbletea model of terminal interface
+type uiModel struct {
+	api        vaultAPI
+	key        []byte
+	state      uiState
+	secrets    []model.Secret
+	filtered   []model.Secret
+	cursor     int
+	search     textinput.Model
+	selected   model.Secret
+	revealed   bool
+	typeCursor int
+	form       *secretForm
+	status     string
+	err        error
+	height     int
+}
+
+func newUIModel(api vaultAPI, key []byte) *uiModel {
+	search := textinput.New()
+	search.Prompt = "/ "
+	search.Placeholder = "search by name, type, tag or URL"
+	return &uiModel{api: api, key: key, search: search, height: 24}
+}
+
+func (m *uiModel) Init() tea.Cmd {
+	return m.loadSecrets()
+}
+
+func (m *uiModel) loadSecrets() tea.Cmd {
+	return func() tea.Msg {
+		secrets, err := m.api.list()
+		return secretsLoadedMsg{secrets: secrets, err: err}
+	}
+}
+
+func (m *uiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.height = msg.Height
+		return m, nil
+	case secretsLoadedMsg:
+		m.err = msg.err
+		if msg.err == nil {
+			m.secrets = msg.secrets
+			sort.Slice(m.secrets, func(i, j int) bool { return m.secrets[i].Name < m.secrets[j].Name })
+			m.applyFilter()
+		}
+		return m, nil
+	case secretSavedMsg:
+		if msg.err != nil {
+			m.form.err = msg.err
+			return m, nil
+		}
+		m.form = nil
+		m.state = uiListState
+		m.status = fmt.Sprintf("Secret %s was saved", msg.name)
+		return m, m.loadSecrets()
+	case secretDeletedMsg:
+		m.state = uiListState
+		if msg.err != nil {
+			m.err = msg.err
+			return m, nil
+		}
+		m.status = fmt.Sprintf("Secret %s was moved to trash", msg.name)
+		return m, m.loadSecrets()
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		if m.state != uiFormState {
+			m.status = ""
+			m.err = nil
+		}
+
+		switch m.state {
+		case uiListState:
+			return m.updateList(msg)
+		case uiDetailState:
+			return m.updateDetail(msg)
+		case uiTypeState:
+			return m.updateType(msg)
+		case uiFormState:
+			return m.updateForm(msg)
+		case uiDeleteState:
+			return m.updateDelete(msg)
+		}
+	}
+	return m, nil
+}
+
+func (m *uiModel) updateList(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if m.search.Focused() {
+		switch msg.String() {
+		case "esc":
+			m.search.Blur()
+			m.search.SetValue("")
+			m.applyFilter()
+			return m, nil
+		case "enter", "down":
+			m.search.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.search, cmd = m.search.Update(msg)
+		m.applyFilter()
+		return m, cmd
+	}
+
+	switch msg.String() {
+	case "q":
+		return m, tea.Quit
+	case "/":
+		return m, m.search.Focus()
+	case "up", "k":
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case "down", "j":
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+	case "enter":
+		if secret, ok := m.current(); ok {
+			m.selected = secret
+			m.revealed = false
+			m.state = uiDetailState
+		}
+	case "n":
+		m.typeCursor = 0
+		m.state = uiTypeState
+	case "e":
+		if secret, ok := m.current(); ok {
+			return m, m.openForm(secret, false)
+		}
+	case "d":
+		if secret, ok := m.current(); ok {
+			m.selected = secret
+			m.state = uiDeleteState
+		}
+	case "r":
+		return m, m.loadSecrets()
+	}
+	return m, nil
+}
+
+func (m *uiModel) updateDetail(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "v", " ":
+		m.revealed = !m.revealed
+	case "e":
+		return m, m.openForm(m.selected, false)
+	case "d":
+		m.state = uiDeleteState
+	case "esc", "backspace", "q":
+		m.state = uiListState
+	}
+	return m, nil
+}
+
+func (m *uiModel) updateType(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.typeCursor > 0 {
+			m.typeCursor--
+		}
+	case "down", "j":
+		if m.typeCursor < len(editableSecretTypes)-1 {
+			m.typeCursor++
+		}
+	case "enter":
+		return m, m.openForm(model.Secret{Type: editableSecretTypes[m.typeCursor]}, true)
+	case "esc", "q":
+		m.state = uiListState
+	}
+	return m, nil
+}
+
+func (m *uiModel) updateForm(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	cmd, action := m.form.update(msg)
+	switch action {
+	case formCancel:
+		m.form = nil
+		m.state = uiListState
+	case formSubmit:
+		secret, err := m.form.build(m.key)
+		if err != nil {
+			m.form.err = err
+			return m, nil
+		}
+		m.form.err = nil
+		isNew := m.form.isNew
+		return m, func() tea.Msg {
+			// new secret is created, so name clash is reported instead of overwriting existing secret
+			if isNew {
+				return secretSavedMsg{name: secret.Name, err: m.api.create(secret)}
+			}
+			return secretSavedMsg{name: secret.Name, err: m.api.save(secret)}
+		}
+	}
+	return m, cmd
+}
+
+func (m *uiModel) updateDelete(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "y":
+		name := m.selected.Name
+		return m, func() tea.Msg {
+			return secretDeletedMsg{name: name, err: m.api.delete(name)}
+		}
+	case "n", "esc", "q":
+		m.state = uiListState
+	}
+	return m, nil
+}
+
+func (m *uiModel) openForm(secret model.Secret, isNew bool) tea.Cmd {
+	form, err := newSecretForm(secret, isNew, m.key)
+	if err != nil {
+		m.err = err
+		return nil
+	}
+	m.form = form
+	m.state = uiFormState
+	return textinput.Blink
+}
+
+func (m *uiModel) current() (model.Secret, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.filtered) {
+		return model.Secret{}, false
+	}
+	return m.filtered[m.cursor], true
+}
+
+// Filter secrets by search query in name, type, tags and URL
+func (m *uiModel) applyFilter() {
+	query := strings.ToLower(strings.TrimSpace(m.search.Value()))
+	m.filtered = m.filtered[:0]
+	for _, secret := range m.secrets {
+		text := strings.ToLower(strings.Join(append([]string{secret.Name, secret.Type, secret.URL}, secret.Tags...), " "))
+		if query == "" || strings.Contains(text, query) {
+			m.filtered = append(m.filtered, secret)
+		}
+	}
+	if m.cursor >= len(m.filtered) {
+		m.cursor = len(m.filtered) - 1
+	}
+	if m.cursor < 0 {
+		m.cursor = 0
+	}
+}
+
+func (m *uiModel) View() string {
+	var view string
+	switch m.state {
+	case uiDetailState:
+		view = m.detailView()
+	case uiTypeState:
+		view = m.typeView()
+	case uiFormState:
+		view = m.form.view()
+	case uiDeleteState:
+		view = uiTitleStyle.Render("Delete secret") + "\n\n" +
+			fmt.Sprintf("Move secret %s to trash? (y/n)", m.selected.Name)
+	default:
+		view = m.listView()
+	}
+
+	if m.err != nil {
+		view += "\n\n" + uiErrorStyle.Render(m.err.Error())
+	} else if m.status != "" {
+		view += "\n\n" + m.status
+	}
+	return view
+}
+
+func (m *uiModel) listView() string {
+	var view strings.Builder
+	view.WriteString(uiTitleStyle.Render(fmt.Sprintf("GophKeeper · %d secrets", len(m.secrets))) + "\n")
+	view.WriteString(m.search.View() + "\n\n")
+
+	visible := m.height - 8
+	if visible < 3 {
+		visible = 3
+	}
+	start := 0
+	if m.cursor >= visible {
+		start = m.cursor - visible + 1
+	}
+	for i := start; i < len(m.filtered) && i < start+visible; i++ {
+		secret := m.filtered[i]
+		line := fmt.Sprintf("%-40s %-12s %s", secret.Name, secret.Type, strings.Join(secret.Tags, ", "))
+		if i == m.cursor {
+			line = uiSelectedStyle.Render("> " + line)
+		} else {
+			line = "  " + line
+		}
+		view.WriteString(line + "\n")
+	}
+	if len(m.filtered) == 0 {
+		view.WriteString("  No secrets found\n")
+	}
+
+	view.WriteString("\n" + uiHelpStyle.Render("↑/↓ move · enter open · / search · n new · e edit · d delete · r reload · q quit"))
+	return view.String()
+}
+
+func (m *uiModel) detailView() string {
+	secret := m.selected
+	var view strings.Builder
+	view.WriteString(uiTitleStyle.Render(fmt.Sprintf("%s (%s)", secret.Name, secret.Type)) + "\n\n")
+
+	fields := findSecretCodec(secret.Type).fields
+
+	values, err := decodeSecretFields(secret, fields, m.key)
+	if err != nil {
+		view.WriteString(uiErrorStyle.Render(err.Error()) + "\n")
+	}
+	for _, field := range fields {
+		value, ok := values[field.name]
+		if !ok {
+			continue
+		}
+		switch {
+		case m.revealed:
+		case field.mask != nil:
+			value = field.mask(value)
+		case field.sensitive:
+			value = uiMask
+		}
+		view.WriteString(fmt.Sprintf("%-12s %s\n", field.label+":", value))
+	}
+
+	if secret.URL != "" {
+		view.WriteString(fmt.Sprintf("%-12s %s\n", "URL:", secret.URL))
+	}
+	if len(secret.Notes) > 0 {
+		if note, err := crypto.DecryptData(secret.Notes, m.key); err == nil {
+			view.WriteString(fmt.Sprintf("%-12s %s\n", "Note:", note))
+		}
+	}
+	if len(secret.Tags) > 0 {
+		view.WriteString(fmt.Sprintf("%-12s %s\n", "Tags:", strings.Join(secret.Tags, ", ")))
+	}
+	for _, moment := range []struct {
+		label string
+		value *time.Time
+	}{
+		{"Expires:", secret.ExpiresAt},
+		{"Rotate:", secret.RotateAfter},
+		{"Updated:", secret.UpdatedAt},
+	} {
+		if moment.value != nil {
+			view.WriteString(fmt.Sprintf("%-12s %s\n", moment.label, moment.value.Local().Format(time.DateTime)))
+		}
+	}
+
+	view.WriteString("\n" + uiHelpStyle.Render("v reveal/hide · e edit · d delete · esc back"))
+	return view.String()
+}
+
+func (m *uiModel) typeView() string {
+	var view strings.Builder
+	view.WriteString(uiTitleStyle.Render("New secret type") + "\n\n")
+	for i, secretType := range editableSecretTypes {
+		if i == m.typeCursor {
+			view.WriteString(uiSelectedStyle.Render("> "+secretType) + "\n")
+		} else {
+			view.WriteString("  " + secretType + "\n")
+		}
+	}
+	view.WriteString("\n" + uiHelpStyle.Render("↑/↓ move · enter select · esc back"))
+	return view.String()
+}
@@ -0,0 +1,268 @@
+package client
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"github.com/desepticon55/gophkeeper/pkg/gophkeeperclient"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+type fakeVaultAPI struct {
+	secrets map[string]model.Secret
+}
+
+func (api *fakeVaultAPI) list() ([]model.Secret, error) {
+	var secrets []model.Secret
+	for _, secret := range api.secrets {
+		secrets = append(secrets, secret)
+	}
+	return secrets, nil
+}
+
+func (api *fakeVaultAPI) create(secret model.Secret) error {
+	if _, ok := api.secrets[secret.Name]; ok {
+		return gophkeeperclient.ErrConflict
+	}
+	api.secrets[secret.Name] = secret
+	return nil
+}
+
+func (api *fakeVaultAPI) save(secret model.Secret) error {
+	stored, ok := api.secrets[secret.Name]
+	if !ok || stored.Version != secret.Version {
+		return errSecretChanged
+	}
+	secret.Version++
+	api.secrets[secret.Name] = secret
+	return nil
+}
+
+func (api *fakeVaultAPI) delete(name string) error {
+	delete(api.secrets, name)
+	return nil
+}
+
+func runeKey(text string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(text)}
+}
+
+// Send messages to model, commands are run synchronously and their messages are sent back
+func sendUI(m *uiModel, messages ...tea.Msg) {
+	for _, msg := range messages {
+		_, cmd := m.Update(msg)
+		for cmd != nil {
+			result := cmd()
+			switch result.(type) {
+			case secretsLoadedMsg, secretSavedMsg, secretDeletedMsg:
+				_, cmd = m.Update(result)
+			default:
+				cmd = nil
+			}
+		}
+	}
+}
+
+func newTestUI(t *testing.T) (*uiModel, *fakeVaultAPI, []byte) {
+	key := []byte("12345678901234567890123456789012")
+	encrypt := func(content string) []byte {
+		encrypted, err := crypto.EncryptData([]byte(content), key)
+		require.NoError(t, err)
+		return encrypted
+	}
+
+	api := &fakeVaultAPI{secrets: map[string]model.Secret{
+		"prod/db":   {Name: "prod/db", Type: model.CredentialsSecretType, Content: encrypt("admin:secret"), Tags: []string{"prod"}},
+		"github":    {Name: "github", Type: model.CredentialsSecretType, Content: encrypt("bot:token"), URL: "https://github.com"},
+		"api-token": {Name: "api-token", Type: model.TextSecretType, Content: encrypt("value")},
+	}}
+	m := newUIModel(api, key)
+	sendUI(m, m.Init()())
+	return m, api, key
+}
+
+func TestUIModel_Search(t *testing.T) {
+	m, _, _ := newTestUI(t)
+	require.Len(t, m.filtered, 3)
+	assert.Equal(t, "api-token", m.filtered[0].Name)
+
+	sendUI(m, runeKey("/"), runeKey("p"), runeKey("r"), runeKey("o"), runeKey("d"))
+	require.Len(t, m.filtered, 1)
+	assert.Equal(t, "prod/db", m.filtered[0].Name)
+
+	sendUI(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Len(t, m.filtered, 3)
+
+	sendUI(m, runeKey("/"), runeKey("github.com"), tea.KeyMsg{Type: tea.KeyEnter})
+	require.Len(t, m.filtered, 1)
+	assert.Equal(t, "github", m.filtered[0].Name)
+	assert.False(t, m.search.Focused())
+}
+
+func TestUIModel_DetailReveal(t *testing.T) {
+	m, _, _ := newTestUI(t)
+	sendUI(m, runeKey("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, uiDetailState, m.state)
+	assert.Equal(t, "github", m.selected.Name)
+
+	view := m.View()
+	assert.Contains(t, view, "bot")
+	assert.Contains(t, view, uiMask)
+	assert.NotContains(t, view, "token")
+
+	sendUI(m, runeKey("v"))
+	view = m.View()
+	assert.Contains(t, view, "token")
+	assert.NotContains(t, view, uiMask)
+
+	sendUI(m, tea.KeyMsg{Type: tea.KeyEsc})
+	assert.Equal(t, uiListState, m.state)
+}
+
+func TestUIModel_Delete(t *testing.T) {
+	m, api, _ := newTestUI(t)
+	sendUI(m, runeKey("d"), runeKey("n"))
+	assert.Equal(t, uiListState, m.state)
+	assert.Len(t, api.secrets, 3)
+
+	sendUI(m, runeKey("d"), runeKey("y"))
+	assert.Equal(t, uiListState, m.state)
+	assert.NotContains(t, api.secrets, "api-token")
+	assert.Len(t, m.filtered, 2)
+}
+
+func TestUIModel_Create(t *testing.T) {
+	m, api, key := newTestUI(t)
+	sendUI(m, runeKey("n"), tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, uiFormState, m.state)
+
+	sendUI(m, runeKey("mail"), tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.Equal(t, uiFormState, m.state)
+	assert.EqualError(t, m.form.err, "Username is required")
+
+	sendUI(m, tea.KeyMsg{Type: tea.KeyTab}, runeKey("ivan"), tea.KeyMsg{Type: tea.KeyTab}, runeKey("p@ss"),
+		tea.KeyMsg{Type: tea.KeyTab}, runeKey("https://mail.example.com"), tea.KeyMsg{Type: tea.KeyTab}, runeKey("personal"),
+		tea.KeyMsg{Type: tea.KeyEnter})
+	assert.Equal(t, uiListState, m.state)
+	assert.Contains(t, m.status, "mail")
+
+	secret, ok := api.secrets["mail"]
+	require.True(t, ok)
+	assert.Equal(t, model.CredentialsSecretType, secret.Type)
+	assert.Equal(t, "https://mail.example.com", secret.URL)
+	content, err := crypto.DecryptData(secret.Content, key)
+	require.NoError(t, err)
+	assert.Equal(t, "ivan:p@ss", string(content))
+	note, err := crypto.DecryptData(secret.Notes, key)
+	require.NoError(t, err)
+	assert.Equal(t, "personal", string(note))
+}
+
+func TestUIModel_Edit(t *testing.T) {
+	m, api, key := newTestUI(t)
+	sendUI(m, runeKey("j"), runeKey("j"), runeKey("e"))
+	require.Equal(t, uiFormState, m.state)
+	assert.True(t, strings.HasPrefix(m.form.inputs[0].Value(), "admin"))
+
+	m.form.inputs[1].SetValue("changed")
+	sendUI(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.Equal(t, uiListState, m.state)
+
+	secret := api.secrets["prod/db"]
+	assert.Equal(t, []string{"prod"}, secret.Tags)
+	content, err := crypto.DecryptData(secret.Content, key)
+	require.NoError(t, err)
+	assert.Equal(t, "admin:changed", string(content))
+}
+
+func TestUIModel_CreateExisting(t *testing.T) {
+	m, api, key := newTestUI(t)
+	sendUI(m, runeKey("n"), runeKey("j"), runeKey("j"), tea.KeyMsg{Type: tea.KeyEnter})
+	require.Equal(t, uiFormState, m.state)
+	require.Equal(t, model.TextSecretType, m.form.secret.Type)
+
+	m.form.inputs[0].SetValue("api-token")
+	m.form.inputs[1].SetValue("other")
+	sendUI(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.Equal(t, uiFormState, m.state)
+	assert.ErrorIs(t, m.form.err, gophkeeperclient.ErrConflict)
+
+	content, err := crypto.DecryptData(api.secrets["api-token"].Content, key)
+	require.NoError(t, err)
+	assert.Equal(t, "value", string(content))
+}
+
+func TestUIModel_EditKeepsSpaces(t *testing.T) {
+	m, api, key := newTestUI(t)
+	secret := api.secrets["prod/db"]
+	var err error
+	secret.Content, err = crypto.EncryptData([]byte("admin: p@ss "), key)
+	require.NoError(t, err)
+	api.secrets["prod/db"] = secret
+	sendUI(m, runeKey("r"), runeKey("j"), runeKey("j"), runeKey("e"))
+	require.Equal(t, uiFormState, m.state)
+
+	m.form.inputs[2].SetValue(" https://db.example.com ")
+	sendUI(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	require.Equal(t, uiListState, m.state)
+
+	content, err := crypto.DecryptData(api.secrets["prod/db"].Content, key)
+	require.NoError(t, err)
+	assert.Equal(t, "admin: p@ss ", string(content))
+	assert.Equal(t, "https://db.example.com", api.secrets["prod/db"].URL)
+}
+
+func TestUIModel_EditMultiline(t *testing.T) {
+	m, api, key := newTestUI(t)
+	secret := api.secrets["api-token"]
+	var err error
+	secret.Content, err = crypto.EncryptData([]byte("line 1\nline 2"), key)
+	require.NoError(t, err)
+	api.secrets["api-token"] = secret
+	sendUI(m, runeKey("r"), runeKey("e"))
+
+	assert.Equal(t, uiListState, m.state)
+	require.Error(t, m.err)
+	assert.Contains(t, m.err.Error(), "secret edit")
+	assert.Nil(t, m.form)
+}
+
+func TestUIModel_EditChanged(t *testing.T) {
+	m, api, _ := newTestUI(t)
+	sendUI(m, runeKey("j"), runeKey("j"), runeKey("e"))
+	require.Equal(t, uiFormState, m.state)
+
+	changed := api.secrets["prod/db"]
+	changed.Content = []byte("changed by another client")
+	changed.Version++
+	api.secrets["prod/db"] = changed
+
+	m.form.inputs[1].SetValue("mine")
+	sendUI(m, tea.KeyMsg{Type: tea.KeyCtrlS})
+	assert.Equal(t, uiFormState, m.state)
+	assert.ErrorIs(t, m.form.err, errSecretChanged)
+	assert.Equal(t, []byte("changed by another client"), api.secrets["prod/db"].Content)
+}
+
+func TestEncodeSecretContent(t *testing.T) {
+	key := []byte("12345678901234567890123456789012")
+	values := map[string]string{"number": "4111111111111111", "date": "12/30", "code": "123", "holder": "IVAN"}
+	content, err := encodeSecretContent(model.CardSecretType, values)
+	require.NoError(t, err)
+	encrypted, err := crypto.EncryptData(content, key)
+	require.NoError(t, err)
+
+	decoded, err := decodeSecretContent(model.Secret{Type: model.CardSecretType, Content: encrypted}, key)
+	require.NoError(t, err)
+	assert.Equal(t, values, decoded)
+
+	_, err = encodeSecretContent(model.CardSecretType, map[string]string{"number": "4111111111111111"})
+	assert.Error(t, err)
+	_, err = encodeSecretContent(model.OTPSecretType, map[string]string{"uri": "https://example.com"})
+	assert.Error(t, err)
+	_, err = decodeSecretContent(model.Secret{Type: model.SSHKeySecretType}, key)
+	assert.Error(t, err)
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// Command to show storage usage and quota of user
+type UsageCommand struct{}
+
+func NewUsageCommand(args map[string]string) (*UsageCommand, error) {
+	return &UsageCommand{}, nil
+}
+
+func (cmd *UsageCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	usage, err := newAPIClient(config, token).FindUsage(context.Background())
+	if err != nil {
+		return fmt.Errorf("can`t read usage. Reason: %w", err)
+	}
+
+	return printOutput(config, usage, func(w io.Writer) error {
+		fmt.Fprintf(w, "Secrets: %d (in trash: %d) of %s\n", usage.Secrets+usage.TrashSecrets, usage.TrashSecrets, formatLimit(usage.MaxSecrets, formatCount))
+		fmt.Fprintf(w, "Total size: %s of %s\n", formatBytes(usage.TotalSize), formatLimit(usage.MaxTotalSize, formatBytes))
+		fmt.Fprintf(w, "Max secret size: %s\n", formatLimit(usage.MaxSecretSize, formatBytes))
+		return nil
+	})
+}
+
+func formatLimit(limit int64, format func(int64) string) string {
+	if limit == 0 {
+		return "unlimited"
+	}
+	return format(limit)
+}
+
+func formatCount(count int64) string {
+	return fmt.Sprint(count)
+}
+
+// Format size in bytes with binary unit prefix
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exp := float64(size)/unit, 0
+	for value >= unit && exp < 4 {
+		value /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGTP"[exp])
+}
+
+// Fabric to create account usage command
+type UsageCommandFactory struct{}
+
+func (f *UsageCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewUsageCommand(args)
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestFormatBytes(t *testing.T) {
+	tests := []struct {
+		size     int64
+		expected string
+	}{
+		{size: 0, expected: "0 B"},
+		{size: 1023, expected: "1023 B"},
+		{size: 1536, expected: "1.5 KiB"},
+		{size: 100 << 20, expected: "100.0 MiB"},
+		{size: 3 << 30, expected: "3.0 GiB"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, formatBytes(tt.size))
+		})
+	}
+}
+
+func TestFormatLimit(t *testing.T) {
+	assert.Equal(t, "unlimited", formatLimit(0, formatBytes))
+	assert.Equal(t, "10", formatLimit(10, formatCount))
+}
@@ -0,0 +1,109 @@
+package client
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"io"
+	"time"
+)
+
+const (
+	vaultArchiveMagic    = "GKVAULT1"
+	vaultArchiveSaltSize = 16
+	vaultArchiveVersion  = 1
+)
+
+// Vault archive. Content and notes of secrets are decrypted, the whole archive is encrypted with passphrase.
+// File layout: magic, salt of key derivation, AES encrypted gzipped JSON
+type vaultArchive struct {
+	Version   int            `json:"version"`
+	CreatedAt time.Time      `json:"created_at"`
+	Secrets   []model.Secret `json:"secrets"`
+}
+
+func writeVaultArchive(writer io.Writer, archive vaultArchive, passphrase []byte) error {
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	if err := json.NewEncoder(gzipWriter).Encode(archive); err != nil {
+		return fmt.Errorf("error during encode archive: %w", err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		return fmt.Errorf("error during compress archive: %w", err)
+	}
+
+	salt := make([]byte, vaultArchiveSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return fmt.Errorf("error during generate salt: %w", err)
+	}
+	encrypted, err := crypto.EncryptData(compressed.Bytes(), crypto.DeriveKey(passphrase, salt))
+	if err != nil {
+		return fmt.Errorf("error during encrypt archive: %w", err)
+	}
+
+	for _, part := range [][]byte{[]byte(vaultArchiveMagic), salt, encrypted} {
+		if _, err := writer.Write(part); err != nil {
+			return fmt.Errorf("error during write archive: %w", err)
+		}
+	}
+	return nil
+}
+
+func readVaultArchive(reader io.Reader, passphrase []byte) (vaultArchive, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return vaultArchive{}, fmt.Errorf("error during read archive: %w", err)
+	}
+
+	header := len(vaultArchiveMagic) + vaultArchiveSaltSize
+	if len(data) < header || string(data[:len(vaultArchiveMagic)]) != vaultArchiveMagic {
+		return vaultArchive{}, errors.New("file is not a gophkeeper vault archive")
+	}
+
+	salt := data[len(vaultArchiveMagic):header]
+	compressed, err := crypto.DecryptData(data[header:], crypto.DeriveKey(passphrase, salt))
+	if err != nil {
+		return vaultArchive{}, fmt.Errorf("error during decrypt archive: %w", err)
+	}
+
+	gzipReader, err := gzip.NewReader(bytes.NewReader(compressed))
+	if err != nil {
+		return vaultArchive{}, errors.New("wrong passphrase or damaged archive")
+	}
+	defer gzipReader.Close()
+
+	var archive vaultArchive
+	if err := json.NewDecoder(gzipReader).Decode(&archive); err != nil {
+		return vaultArchive{}, errors.New("wrong passphrase or damaged archive")
+	}
+	if archive.Version != vaultArchiveVersion {
+		return vaultArchive{}, fmt.Errorf("unsupported archive version %d", archive.Version)
+	}
+	return archive, nil
+}
+
+// Split secrets into batches with approximate JSON size less than maxBytes. Too large secret goes to separate batch
+func splitSecretBatches(secrets []model.Secret, maxBytes int) [][]model.Secret {
+	var batches [][]model.Secret
+	var batch []model.Secret
+	batchBytes := 0
+	for _, secret := range secrets {
+		// base64 encoding of content and notes plus metadata
+		secretBytes := (len(secret.Content)+len(secret.Notes))*4/3 + len(secret.Name) + len(secret.URL) + 512
+		if len(batch) > 0 && batchBytes+secretBytes > maxBytes {
+			batches = append(batches, batch)
+			batch, batchBytes = nil, 0
+		}
+		batch = append(batch, secret)
+		batchBytes += secretBytes
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
@@ -0,0 +1,260 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/client/importer"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/pkg/crypto"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+const (
+	// Max approximate size of one batch request during import
+	importBatchBytes = 1 << 20
+	// Import format of archive made by vault export
+	vaultArchiveFormat = "gophkeeper"
+)
+
+// Command to export all secrets to encrypted archive
+type VaultExportCommand struct {
+	file       string
+	passphrase string
+}
+
+func NewVaultExportCommand(args map[string]string) (*VaultExportCommand, error) {
+	file, ok := args["file"]
+	if !ok || file == "" {
+		return nil, errors.New("archive file is required")
+	}
+
+	return &VaultExportCommand{file: file, passphrase: args["passphrase"]}, nil
+}
+
+func (cmd *VaultExportCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	api := newAPIClient(config, token)
+	key := []byte(config.EncryptionKey)
+
+	archive := vaultArchive{Version: vaultArchiveVersion, CreatedAt: time.Now().UTC()}
+	skipped := 0
+	filter := model.SecretFilter{}
+	for {
+		page, err := api.FindSecrets(context.Background(), filter)
+		if err != nil {
+			return err
+		}
+
+		for _, secret := range page.Secrets {
+			// content of one-time secrets is not listed by server, reading it would burn the secret
+			if secret.BurnAfterReading && len(secret.Content) == 0 {
+				skipped++
+				continue
+			}
+			decrypted, err := decryptSecret(secret, key)
+			if err != nil {
+				return err
+			}
+			archive.Secrets = append(archive.Secrets, decrypted)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+		filter.Cursor = page.NextCursor
+	}
+
+	file, err := os.OpenFile(cmd.file, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("error during create archive file: %w", err)
+	}
+	defer file.Close()
+
+	if err := writeVaultArchive(file, archive, vaultPassphrase(cmd.passphrase, config)); err != nil {
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error during write archive file: %w", err)
+	}
+
+	fmt.Printf("Exported %d secrets to \"%s\"\n", len(archive.Secrets), cmd.file)
+	if skipped > 0 {
+		fmt.Printf("Skipped %d one-time secrets\n", skipped)
+	}
+	return nil
+}
+
+// Fabric to create vault export command
+type VaultExportCommandFactory struct{}
+
+func (f *VaultExportCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewVaultExportCommand(args)
+}
+
+// Command to import secrets from encrypted archive or export of another password manager.
+// Existing secrets with the same names are replaced
+type VaultImportCommand struct {
+	file       string
+	passphrase string
+	format     string
+	dryRun     bool
+}
+
+func NewVaultImportCommand(args map[string]string) (*VaultImportCommand, error) {
+	file, ok := args["file"]
+	if !ok || file == "" {
+		return nil, errors.New("archive file is required")
+	}
+
+	dryRun, err := parseBoolFlag(args, "dry-run")
+	if err != nil {
+		return nil, err
+	}
+
+	return &VaultImportCommand{file: file, passphrase: args["passphrase"], format: args["format"], dryRun: dryRun}, nil
+}
+
+func (cmd *VaultImportCommand) Execute(config Config) error {
+	token, err := readToken(config)
+	if err != nil {
+		return fmt.Errorf("can`t find auth data: %w", err)
+	}
+
+	result, err := cmd.readSecrets(config)
+	if err != nil {
+		return err
+	}
+
+	api := newAPIClient(config, token)
+	existing, err := findSecretNames(context.Background(), api)
+	if err != nil {
+		return err
+	}
+	printImportReport(result, existing)
+
+	if cmd.dryRun {
+		fmt.Println("Dry run, secrets were not imported")
+		return nil
+	}
+
+	key := []byte(config.EncryptionKey)
+	secrets := make([]model.Secret, 0, len(result.Secrets))
+	for _, secret := range result.Secrets {
+		encrypted, err := encryptSecret(secret, key)
+		if err != nil {
+			return err
+		}
+		secrets = append(secrets, encrypted)
+	}
+
+	total := model.SecretBatchResult{}
+	for _, batch := range splitSecretBatches(secrets, importBatchBytes) {
+		batchResult, err := api.UpsertSecrets(context.Background(), batch)
+		if err != nil {
+			return fmt.Errorf("imported %d secrets before error: %w", total.Created+total.Updated, err)
+		}
+		total.Created += batchResult.Created
+		total.Updated += batchResult.Updated
+	}
+
+	fmt.Printf("Imported %d secrets: %d created, %d updated\n", total.Created+total.Updated, total.Created, total.Updated)
+	return nil
+}
+
+// Read decrypted secrets from gophkeeper archive or parse export of another password manager
+func (cmd *VaultImportCommand) readSecrets(config Config) (importer.Result, error) {
+	file, err := os.Open(cmd.file)
+	if err != nil {
+		return importer.Result{}, fmt.Errorf("error during open import file: %w", err)
+	}
+	defer file.Close()
+
+	if cmd.format != "" && cmd.format != vaultArchiveFormat {
+		return importer.Parse(cmd.format, file)
+	}
+
+	archive, err := readVaultArchive(file, vaultPassphrase(cmd.passphrase, config))
+	if err != nil {
+		return importer.Result{}, err
+	}
+	return importer.Result{Secrets: archive.Secrets}, nil
+}
+
+// Print secrets to import, existing secrets are marked to be replaced
+func printImportReport(result importer.Result, existing map[string]bool) {
+	writer := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	for _, secret := range result.Secrets {
+		action := "create"
+		if existing[secret.Name] {
+			action = "replace"
+		}
+		fmt.Fprintf(writer, "%s\t%s\t%s\t%s\n", action, secret.Name, secret.Type, secret.URL)
+	}
+	writer.Flush()
+
+	for _, warning := range result.Warnings {
+		fmt.Printf("Warning: %s\n", warning)
+	}
+	for _, skipped := range result.Skipped {
+		fmt.Printf("Skipped: %s\n", skipped)
+	}
+	fmt.Printf("Secrets to import: %d, skipped: %d\n", len(result.Secrets), len(result.Skipped))
+}
+
+// Fabric to create vault import command
+type VaultImportCommandFactory struct{}
+
+func (f *VaultImportCommandFactory) Create(args map[string]string) (Command, error) {
+	return NewVaultImportCommand(args)
+}
+
+// Archive is encrypted with encryption key when passphrase is not set
+func vaultPassphrase(passphrase string, config Config) []byte {
+	if passphrase == "" {
+		return []byte(config.EncryptionKey)
+	}
+	return []byte(passphrase)
+}
+
+// Decrypt content and notes of secret
+func decryptSecret(secret model.Secret, key []byte) (model.Secret, error) {
+	content, err := crypto.DecryptData(secret.Content, key)
+	if err != nil {
+		return model.Secret{}, fmt.Errorf("error during decrypt secret \"%s\": %w", secret.Name, err)
+	}
+	secret.Content = content
+
+	if len(secret.Notes) > 0 {
+		notes, err := crypto.DecryptData(secret.Notes, key)
+		if err != nil {
+			return model.Secret{}, fmt.Errorf("error during decrypt notes of secret \"%s\": %w", secret.Name, err)
+		}
+		secret.Notes = notes
+	}
+	return secret, nil
+}
+
+// Encrypt content and notes of secret
+func encryptSecret(secret model.Secret, key []byte) (model.Secret, error) {
+	content, err := crypto.EncryptData(secret.Content, key)
+	if err != nil {
+		return model.Secret{}, fmt.Errorf("error during encrypt secret \"%s\": %w", secret.Name, err)
+	}
+	secret.Content = content
+
+	if len(secret.Notes) > 0 {
+		notes, err := crypto.EncryptData(secret.Notes, key)
+		if err != nil {
+			return model.Secret{}, fmt.Errorf("error during encrypt notes of secret \"%s\": %w", secret.Name, err)
+		}
+		secret.Notes = notes
+	}
+	return secret, nil
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"bytes"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVaultArchive(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	archive := vaultArchive{
+		Version:   vaultArchiveVersion,
+		CreatedAt: time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		Secrets: []model.Secret{
+			{Name: "prod/db", Type: model.CredentialsSecretType, Content: []byte("admin:secret"), Notes: []byte("note"), Tags: []string{"db"}, Version: 3},
+			{Name: "card", Type: model.CardSecretType, Content: []byte(`{"number":"4242"}`), ExpiresAt: &expiresAt},
+		},
+	}
+
+	t.Run("should read written archive", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := writeVaultArchive(&buffer, archive, []byte("passphrase"))
+		assert.NoError(t, err)
+		assert.True(t, strings.HasPrefix(buffer.String(), vaultArchiveMagic))
+		assert.NotContains(t, buffer.String(), "admin:secret")
+
+		result, err := readVaultArchive(&buffer, []byte("passphrase"))
+		assert.NoError(t, err)
+		assert.Equal(t, archive, result)
+	})
+
+	t.Run("should return error for wrong passphrase", func(t *testing.T) {
+		var buffer bytes.Buffer
+		err := writeVaultArchive(&buffer, archive, []byte("passphrase"))
+		assert.NoError(t, err)
+
+		_, err = readVaultArchive(&buffer, []byte("another"))
+		assert.Error(t, err)
+	})
+
+	t.Run("should return error for not archive file", func(t *testing.T) {
+		_, err := readVaultArchive(strings.NewReader("name,password"), []byte("passphrase"))
+		assert.EqualError(t, err, "file is not a gophkeeper vault archive")
+	})
+}
+
+func TestEncryptDecryptSecret(t *testing.T) {
+	key := []byte("WYJcWgkItShq513L21E1CFuz6uQWDy3p")
+	secret := model.Secret{Name: "prod/db", Content: []byte("admin:secret"), Notes: []byte("note")}
+
+	encrypted, err := encryptSecret(secret, key)
+	assert.NoError(t, err)
+	assert.NotEqual(t, secret.Content, encrypted.Content)
+	assert.NotEqual(t, secret.Notes, encrypted.Notes)
+
+	decrypted, err := decryptSecret(encrypted, key)
+	assert.NoError(t, err)
+	assert.Equal(t, secret, decrypted)
+}
+
+func TestSplitSecretBatches(t *testing.T) {
+	secret := func(name string, size int) model.Secret {
+		return model.Secret{Name: name, Content: make([]byte, size)}
+	}
+
+	batches := splitSecretBatches([]model.Secret{secret("a", 300), secret("b", 300), secret("c", 3000), secret("d", 10)}, 2000)
+	var names [][]string
+	for _, batch := range batches {
+		var batchNames []string
+		for _, secret := range batch {
+			batchNames = append(batchNames, secret.Name)
+		}
+		names = append(names, batchNames)
+	}
+	assert.Equal(t, [][]string{{"a", "b"}, {"c"}, {"d"}}, names)
+	assert.Nil(t, splitSecretBatches(nil, 2000))
+}
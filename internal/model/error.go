@@ -9,4 +9,14 @@ var (
 	ErrSecretsWasNotFound       = errors.New("secrets to current user was not found")
 	ErrSecretNameIsEmpty        = errors.New("secret name is empty")
 	ErrSecretExistToCurrentUser = errors.New("secret already exists to current user")
+	ErrSecretFilterIsNotValid   = errors.New("secret filter is not valid")
+	ErrSecretNameIsNotValid     = errors.New("secret name is not valid")
+	ErrFolderWasNotFound        = errors.New("folder with specific path to current user was not found")
+	ErrFolderMoveIsNotValid     = errors.New("folder can not be moved into itself")
+	ErrFolderAccessDenied       = errors.New("access to folder is denied")
+	ErrTrashIsEmpty             = errors.New("trash of current user is empty")
+	ErrSecretTooLarge           = errors.New("secret size exceeds limit")
+	ErrSecretBatchIsNotValid    = errors.New("secret batch is empty or contains duplicate names")
+	ErrQuotaExceeded            = errors.New("storage quota of current user is exceeded")
+	ErrSecretVersionConflict    = errors.New("secret was changed by another request")
 )
@@ -2,6 +2,7 @@ package model
 
 import (
 	"github.com/golang-jwt/jwt/v4"
+	"time"
 )
 
 const (
@@ -9,6 +10,16 @@ const (
 	TextSecretType        = "TEXT"
 	CardSecretType        = "CARD"
 	BinarySecretType      = "BINARY"
+	OTPSecretType         = "OTP"
+	SSHKeySecretType      = "SSH_KEY"
+)
+
+// Action on folder checked by folder policy
+type FolderAction string
+
+const (
+	FolderReadAction  FolderAction = "read"
+	FolderWriteAction FolderAction = "write"
 )
 
 // JWT claims
@@ -29,7 +40,80 @@ type Secret struct {
 	Content  []byte `json:"content"`
 	Username string `json:"-"`
 	Type     string `json:"type"`
-	Version  int64  `json:"-"`
+	Version  int64  `json:"version,omitempty"`
+
+	// Encrypted free-form notes
+	Notes []byte   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	URL   string   `json:"url,omitempty"`
+
+	// Secret lifetime, informational only: expired secrets are still readable
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RotateAfter *time.Time `json:"rotate_after,omitempty"`
+	// Secret is deleted by server right after the first successful read
+	BurnAfterReading bool `json:"burn_after_reading,omitempty"`
+
+	// Managed by server, ignored on upload
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	// Filled for secrets in trash only
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Filter to search user secrets
+type SecretFilter struct {
+	// Name prefix or glob pattern with '*' and '?' wildcards
+	Name string
+	// Folder containing secrets, including subfolders
+	Folder string
+	Type   string
+	Tag    string
+	// Secrets expiring or requiring rotation before time
+	DueBefore *time.Time
+	// Name of the last secret from the previous page
+	Cursor string
+	Limit  int
+}
+
+// Page of user secrets ordered by name
+type SecretPage struct {
+	Secrets    []Secret `json:"secrets"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Result of batch secrets upload
+type SecretBatchResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// Content of folder: direct subfolders and secrets
+type Folder struct {
+	Path    string   `json:"path"`
+	Folders []string `json:"folders"`
+	Secrets []Secret `json:"secrets"`
+}
+
+// Request to rename secret
+type SecretRename struct {
+	To string `json:"to"`
+}
+
+// Request to move folder with all nested secrets
+type FolderMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Storage usage of user with configured limits. Secrets in trash are counted until purged, zero limit means unlimited
+type Usage struct {
+	Secrets       int64 `json:"secrets"`
+	TrashSecrets  int64 `json:"trash_secrets"`
+	TotalSize     int64 `json:"total_size"`
+	MaxSecrets    int64 `json:"max_secrets"`
+	MaxSecretSize int64 `json:"max_secret_size"`
+	MaxTotalSize  int64 `json:"max_total_size"`
 }
 
 // Card requisites
@@ -39,3 +123,13 @@ type Card struct {
 	Code   string `json:"code"`
 	Holder string `json:"holder"`
 }
+
+// SSH key domain model
+type SSHKey struct {
+	// Private key in OpenSSH PEM format
+	PrivateKey string `json:"private_key"`
+	// Public key in authorized_keys format
+	PublicKey  string `json:"public_key"`
+	Comment    string `json:"comment,omitempty"`
+	Passphrase string `json:"passphrase,omitempty"`
+}
@@ -0,0 +1,45 @@
+package model
+
+import "strings"
+
+// Separator of folders in secret name
+const SecretPathSeparator = "/"
+
+// Max length of secret name in characters, limited by database schema
+const MaxSecretNameLength = 255
+
+// Normalize path-style secret name like "prod/db/postgres".
+// Leading and trailing separators are trimmed, empty, "." and ".." segments are not allowed
+func CleanSecretName(name string) (string, error) {
+	cleanName, err := CleanFolderPath(name)
+	if err != nil {
+		return "", err
+	}
+	if cleanName == "" {
+		return "", ErrSecretNameIsEmpty
+	}
+	return cleanName, nil
+}
+
+// Normalize folder path. Empty path means root folder
+func CleanFolderPath(path string) (string, error) {
+	path = strings.Trim(path, SecretPathSeparator)
+	if path == "" {
+		return "", nil
+	}
+
+	for _, segment := range strings.Split(path, SecretPathSeparator) {
+		if segment == "" || segment == "." || segment == ".." {
+			return "", ErrSecretNameIsNotValid
+		}
+	}
+	return path, nil
+}
+
+// Folder containing secret, empty for secrets in root folder
+func SecretFolder(name string) string {
+	if index := strings.LastIndex(name, SecretPathSeparator); index >= 0 {
+		return name[:index]
+	}
+	return ""
+}
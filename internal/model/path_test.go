@@ -0,0 +1,36 @@
+package model
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCleanSecretName(t *testing.T) {
+	tests := []struct {
+		name          string
+		secretName    string
+		expected      string
+		expectedError error
+	}{
+		{name: "Flat name", secretName: "postgres", expected: "postgres"},
+		{name: "Nested name", secretName: "prod/db/postgres", expected: "prod/db/postgres"},
+		{name: "Leading and trailing separators", secretName: "/prod/db/", expected: "prod/db"},
+		{name: "Name with spaces", secretName: "Small text", expected: "Small text"},
+		{name: "Empty name", secretName: "/", expectedError: ErrSecretNameIsEmpty},
+		{name: "Empty segment", secretName: "prod//db", expectedError: ErrSecretNameIsNotValid},
+		{name: "Parent segment", secretName: "prod/../db", expectedError: ErrSecretNameIsNotValid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := CleanSecretName(tt.secretName)
+			assert.Equal(t, tt.expectedError, err)
+			assert.Equal(t, tt.expected, result)
+		})
+	}
+}
+
+func TestSecretFolder(t *testing.T) {
+	assert.Equal(t, "prod/db", SecretFolder("prod/db/postgres"))
+	assert.Equal(t, "", SecretFolder("postgres"))
+}
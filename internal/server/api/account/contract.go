@@ -0,0 +1,10 @@
+package account
+
+import (
+	"context"
+	"github.com/desepticon55/gophkeeper/internal/model"
+)
+
+type accountService interface {
+	FindUsage(ctx context.Context) (model.Usage, error)
+}
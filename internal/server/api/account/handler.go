@@ -0,0 +1,38 @@
+package account
+
+import (
+	"encoding/json"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// Handler to read storage usage and quota of user
+func ReadUsageHandler(logger *zap.Logger, service accountService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		usage, err := service.FindUsage(request.Context())
+		if err != nil {
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		bytes, err := json.Marshal(usage)
+		if err != nil {
+			logger.Error("Error during marshal usage.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		if _, err = writer.Write(bytes); err != nil {
+			logger.Error("Error write usage.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package account
+
+import (
+	"context"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockAccountService struct {
+	FindUsageFunc func(ctx context.Context) (model.Usage, error)
+}
+
+func (m *mockAccountService) FindUsage(ctx context.Context) (model.Usage, error) {
+	return m.FindUsageFunc(ctx)
+}
+
+func TestReadUsageHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		service        accountService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Successful operation",
+			method: http.MethodGet,
+			service: &mockAccountService{
+				FindUsageFunc: func(ctx context.Context) (model.Usage, error) {
+					return model.Usage{Secrets: 2, TrashSecrets: 1, TotalSize: 300, MaxSecrets: 10, MaxTotalSize: 1000}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"secrets":2,"trash_secrets":1,"total_size":300,"max_secrets":10,"max_secret_size":0,"max_total_size":1000}`,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Internal server error",
+			method: http.MethodGet,
+			service: &mockAccountService{
+				FindUsageFunc: func(ctx context.Context) (model.Usage, error) {
+					return model.Usage{}, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/usage", nil)
+			rec := httptest.NewRecorder()
+
+			handler := ReadUsageHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				body, err := io.ReadAll(res.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
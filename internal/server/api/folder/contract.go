@@ -0,0 +1,12 @@
+package folder
+
+import (
+	"context"
+	"github.com/desepticon55/gophkeeper/internal/model"
+)
+
+type folderService interface {
+	ListFolder(ctx context.Context, path string) (model.Folder, error)
+
+	MoveFolder(ctx context.Context, from string, to string) error
+}
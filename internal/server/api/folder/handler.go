@@ -0,0 +1,96 @@
+package folder
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server/api"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// Handler to read direct subfolders and secrets of user folder
+func ReadFolderHandler(logger *zap.Logger, service folderService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		path := api.WildcardParam(request)
+		folder, err := service.ListFolder(request.Context(), path)
+		if err != nil {
+			if errors.Is(err, model.ErrSecretNameIsNotValid) {
+				http.Error(writer, "Folder path is not valid", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
+			if errors.Is(err, model.ErrFolderWasNotFound) {
+				http.Error(writer, "Folder was not found", http.StatusNotFound)
+				return
+			}
+			logger.Error("Error during list folder.", zap.String("path", path), zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		bytes, err := json.Marshal(folder)
+		if err != nil {
+			logger.Error("Error during marshal folder.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		if _, err = writer.Write(bytes); err != nil {
+			logger.Error("Error write folder.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Handler to move user folder with all nested secrets
+func MoveFolderHandler(logger *zap.Logger, service folderService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		var move model.FolderMove
+		if err := json.NewDecoder(request.Body).Decode(&move); err != nil {
+			logger.Error("Error decode request", zap.Error(err))
+			http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+
+		err := service.MoveFolder(request.Context(), move.From, move.To)
+		if err != nil {
+			if errors.Is(err, model.ErrSecretNameIsNotValid) || errors.Is(err, model.ErrFolderMoveIsNotValid) {
+				http.Error(writer, "Folder paths are not valid", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
+			if errors.Is(err, model.ErrFolderWasNotFound) {
+				http.Error(writer, "Folder was not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, model.ErrSecretExistToCurrentUser) {
+				http.Error(writer, "Secret already exists in target folder", http.StatusConflict)
+				return
+			}
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}
+}
@@ -0,0 +1,199 @@
+package folder
+
+import (
+	"context"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+type mockFolderService struct {
+	ListFolderFunc func(ctx context.Context, path string) (model.Folder, error)
+	MoveFolderFunc func(ctx context.Context, from string, to string) error
+}
+
+func (m *mockFolderService) ListFolder(ctx context.Context, path string) (model.Folder, error) {
+	return m.ListFolderFunc(ctx, path)
+}
+
+func (m *mockFolderService) MoveFolder(ctx context.Context, from string, to string) error {
+	return m.MoveFolderFunc(ctx, from, to)
+}
+
+func TestReadFolderHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		service        folderService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Successful operation",
+			method: http.MethodGet,
+			path:   "prod",
+			service: &mockFolderService{
+				ListFolderFunc: func(ctx context.Context, path string) (model.Folder, error) {
+					return model.Folder{
+						Path:    path,
+						Folders: []string{"db"},
+						Secrets: []model.Secret{{Name: "prod/api-key", Content: []byte("key"), Type: model.TextSecretType}},
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"path":"prod","folders":["db"],"secrets":[{"name":"prod/api-key","content":"a2V5","type":"TEXT"}]}`,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			path:           "prod",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Folder was not found",
+			method: http.MethodGet,
+			path:   "stage",
+			service: &mockFolderService{
+				ListFolderFunc: func(ctx context.Context, path string) (model.Folder, error) {
+					return model.Folder{}, model.ErrFolderWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Access to folder is denied",
+			method: http.MethodGet,
+			path:   "private",
+			service: &mockFolderService{
+				ListFolderFunc: func(ctx context.Context, path string) (model.Folder, error) {
+					return model.Folder{}, model.ErrFolderAccessDenied
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/folder/"+tt.path, nil)
+			routeContext := chi.NewRouteContext()
+			routeContext.URLParams.Add("*", tt.path)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+
+			rec := httptest.NewRecorder()
+			handler := ReadFolderHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				body, err := io.ReadAll(res.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestMoveFolderHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		service        folderService
+		expectedStatus int
+	}{
+		{
+			name:   "Successful move folder",
+			method: http.MethodPost,
+			body:   `{"from":"prod/db","to":"stage/db"}`,
+			service: &mockFolderService{
+				MoveFolderFunc: func(ctx context.Context, from string, to string) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodGet,
+			body:           "",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request payload",
+			method:         http.MethodPost,
+			body:           `{"from":`,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Folder is moved into itself",
+			method: http.MethodPost,
+			body:   `{"from":"prod","to":"prod/db"}`,
+			service: &mockFolderService{
+				MoveFolderFunc: func(ctx context.Context, from string, to string) error {
+					return model.ErrFolderMoveIsNotValid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Secret already exists in target folder",
+			method: http.MethodPost,
+			body:   `{"from":"prod","to":"stage"}`,
+			service: &mockFolderService{
+				MoveFolderFunc: func(ctx context.Context, from string, to string) error {
+					return model.ErrSecretExistToCurrentUser
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name:   "Folder was not found",
+			method: http.MethodPost,
+			body:   `{"from":"prod","to":"stage"}`,
+			service: &mockFolderService{
+				MoveFolderFunc: func(ctx context.Context, from string, to string) error {
+					return model.ErrFolderWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/folder/move", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler := MoveFolderHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
@@ -0,0 +1,17 @@
+package health
+
+import (
+	"context"
+)
+
+type database interface {
+	Ping(ctx context.Context) error
+}
+
+type migrations interface {
+	Status(ctx context.Context) (int64, error)
+}
+
+type readiness interface {
+	IsReady() bool
+}
@@ -0,0 +1,128 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"go.uber.org/zap"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	StatusOK          = "ok"
+	StatusUnavailable = "unavailable"
+
+	// Max time of database ping
+	pingTimeout = 2 * time.Second
+)
+
+// Status of database migrations applied at startup. Failed status is checked again by probes,
+// so server becomes ready after schema is fixed without restart
+type Migrations struct {
+	mu      sync.Mutex
+	version int64
+	err     error
+	check   func(ctx context.Context) (int64, error)
+}
+
+func NewMigrations(version int64, err error) *Migrations {
+	return &Migrations{version: version, err: err}
+}
+
+// Check of schema version called while migrations are failed
+func (m *Migrations) WithCheck(check func(ctx context.Context) (int64, error)) *Migrations {
+	m.check = check
+	return m
+}
+
+func (m *Migrations) Status(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.err != nil && m.check != nil {
+		m.version, m.err = m.check(ctx)
+	}
+	return m.version, m.err
+}
+
+// Status of one dependency
+type Check struct {
+	Status    string `json:"status"`
+	Error     string `json:"error,omitempty"`
+	LatencyMs int64  `json:"latency_ms,omitempty"`
+	Version   int64  `json:"version,omitempty"`
+}
+
+// Status of server with its dependencies
+type Status struct {
+	Status string           `json:"status"`
+	Checks map[string]Check `json:"checks"`
+}
+
+// Handler of liveness probe. Server is alive while it responds, so status code is always 200
+// and failed dependencies are only reported in body
+func HealthHandler(logger *zap.Logger, db database, migrations migrations) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		status := checkDependencies(request.Context(), db, migrations)
+		writeStatus(logger, writer, status, http.StatusOK)
+	}
+}
+
+// Handler of readiness probe. Server is ready when database responds, migrations were applied
+// and server is not shutting down, otherwise it responds with 503
+func ReadinessHandler(logger *zap.Logger, db database, migrations migrations, readiness readiness) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		status := checkDependencies(request.Context(), db, migrations)
+		server := Check{Status: StatusOK}
+		if !readiness.IsReady() {
+			server = Check{Status: StatusUnavailable, Error: "server is starting or shutting down"}
+			status.Status = StatusUnavailable
+		}
+		status.Checks["server"] = server
+
+		code := http.StatusOK
+		if status.Status != StatusOK {
+			code = http.StatusServiceUnavailable
+		}
+		writeStatus(logger, writer, status, code)
+	}
+}
+
+func checkDependencies(ctx context.Context, db database, migrations migrations) Status {
+	status := Status{Status: StatusOK, Checks: make(map[string]Check)}
+
+	ctx, cancel := context.WithTimeout(ctx, pingTimeout)
+	defer cancel()
+	start := time.Now()
+	database := Check{Status: StatusOK}
+	if err := db.Ping(ctx); err != nil {
+		database = Check{Status: StatusUnavailable, Error: err.Error()}
+		status.Status = StatusUnavailable
+	}
+	database.LatencyMs = time.Since(start).Milliseconds()
+	status.Checks["database"] = database
+
+	version, err := migrations.Status(ctx)
+	migration := Check{Status: StatusOK, Version: version}
+	if err != nil {
+		migration = Check{Status: StatusUnavailable, Error: err.Error(), Version: version}
+		status.Status = StatusUnavailable
+	}
+	status.Checks["migrations"] = migration
+	return status
+}
+
+func writeStatus(logger *zap.Logger, writer http.ResponseWriter, status Status, code int) {
+	bytes, err := json.Marshal(status)
+	if err != nil {
+		logger.Error("Error during marshal health status.", zap.Error(err))
+		http.Error(writer, "Internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(code)
+	if _, err = writer.Write(bytes); err != nil {
+		logger.Error("Error write health status.", zap.Error(err))
+	}
+}
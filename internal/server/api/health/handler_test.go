@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type mockDatabase struct {
+	PingFunc func(ctx context.Context) error
+}
+
+func (m *mockDatabase) Ping(ctx context.Context) error {
+	return m.PingFunc(ctx)
+}
+
+type mockMigrations struct {
+	StatusFunc func(ctx context.Context) (int64, error)
+}
+
+func (m *mockMigrations) Status(ctx context.Context) (int64, error) {
+	return m.StatusFunc(ctx)
+}
+
+type mockReadiness struct {
+	ready bool
+}
+
+func (m *mockReadiness) IsReady() bool {
+	return m.ready
+}
+
+func pingResult(err error) *mockDatabase {
+	return &mockDatabase{PingFunc: func(ctx context.Context) error { return err }}
+}
+
+func migrationsResult(version int64, err error) *mockMigrations {
+	return &mockMigrations{StatusFunc: func(ctx context.Context) (int64, error) { return version, err }}
+}
+
+func readStatus(t *testing.T, recorder *httptest.ResponseRecorder) Status {
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var status Status
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &status))
+	return status
+}
+
+func TestHealthHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	t.Run("should report dependencies", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		HealthHandler(logger, pingResult(nil), migrationsResult(6, nil))(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		status := readStatus(t, recorder)
+		assert.Equal(t, StatusOK, status.Status)
+		assert.Equal(t, StatusOK, status.Checks["database"].Status)
+		assert.Equal(t, Check{Status: StatusOK, Version: 6}, status.Checks["migrations"])
+	})
+
+	t.Run("should stay alive when database is unavailable", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		HealthHandler(logger, pingResult(errors.New("connection refused")), migrationsResult(6, nil))(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+		assert.Equal(t, http.StatusOK, recorder.Code)
+		status := readStatus(t, recorder)
+		assert.Equal(t, StatusUnavailable, status.Status)
+		assert.Equal(t, StatusUnavailable, status.Checks["database"].Status)
+		assert.Equal(t, "connection refused", status.Checks["database"].Error)
+	})
+}
+
+func TestReadinessHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		database       database
+		migrations     migrations
+		ready          bool
+		expectedStatus int
+		failedCheck    string
+	}{
+		{
+			name:           "Ready server",
+			database:       pingResult(nil),
+			migrations:     migrationsResult(6, nil),
+			ready:          true,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Database is unavailable",
+			database:       pingResult(errors.New("connection refused")),
+			migrations:     migrationsResult(6, nil),
+			ready:          true,
+			expectedStatus: http.StatusServiceUnavailable,
+			failedCheck:    "database",
+		},
+		{
+			name:           "Migrations failed",
+			database:       pingResult(nil),
+			migrations:     migrationsResult(4, errors.New("syntax error")),
+			ready:          true,
+			expectedStatus: http.StatusServiceUnavailable,
+			failedCheck:    "migrations",
+		},
+		{
+			name:           "Server is shutting down",
+			database:       pingResult(nil),
+			migrations:     migrationsResult(6, nil),
+			ready:          false,
+			expectedStatus: http.StatusServiceUnavailable,
+			failedCheck:    "server",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := httptest.NewRecorder()
+			handler := ReadinessHandler(logger, tt.database, tt.migrations, &mockReadiness{ready: tt.ready})
+			handler(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			assert.Equal(t, tt.expectedStatus, recorder.Code)
+			status := readStatus(t, recorder)
+			for name, check := range status.Checks {
+				if name == tt.failedCheck {
+					assert.Equal(t, StatusUnavailable, check.Status, name)
+					assert.NotEmpty(t, check.Error, name)
+				} else {
+					assert.Equal(t, StatusOK, check.Status, name)
+				}
+			}
+			assert.Len(t, status.Checks, 3)
+		})
+	}
+}
+
+func TestMigrations_Status(t *testing.T) {
+	schemaVersion := int64(4)
+	checks := 0
+	migrations := NewMigrations(4, errors.New("syntax error")).WithCheck(func(ctx context.Context) (int64, error) {
+		checks++
+		if schemaVersion < 6 {
+			return schemaVersion, errors.New("database version is older than migrations")
+		}
+		return schemaVersion, nil
+	})
+
+	version, err := migrations.Status(context.Background())
+	assert.Error(t, err)
+	assert.Equal(t, int64(4), version)
+
+	schemaVersion = 6
+	version, err = migrations.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), version)
+
+	_, err = migrations.Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 2, checks, "applied migrations should not be checked again")
+
+	version, err = NewMigrations(6, nil).Status(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), version)
+}
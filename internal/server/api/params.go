@@ -0,0 +1,21 @@
+package api
+
+import (
+	"github.com/go-chi/chi/v5"
+	"net/http"
+	"net/url"
+)
+
+// Read path-style value of wildcard route parameter, e.g. secret name "prod/db/postgres"
+func WildcardParam(request *http.Request) string {
+	value := chi.URLParam(request, "*")
+	if request.URL.RawPath == "" {
+		return value
+	}
+
+	// router matches escaped path when it differs from default encoding
+	if unescaped, err := url.PathUnescape(value); err == nil {
+		return unescaped
+	}
+	return value
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"context"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWildcardParam(t *testing.T) {
+	tests := []struct {
+		name     string
+		target   string
+		param    string
+		expected string
+	}{
+		{name: "Nested name", target: "/api/user/secret/prod/db", param: "prod/db", expected: "prod/db"},
+		{name: "Decoded name", target: "/api/user/secret/Small%20text", param: "Small text", expected: "Small text"},
+		{name: "Escaped name", target: "/api/user/secret/a%2Fb/c", param: "a%2Fb/c", expected: "a/b/c"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			routeContext := chi.NewRouteContext()
+			routeContext.URLParams.Add("*", tt.param)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+
+			assert.Equal(t, tt.expected, WildcardParam(req))
+		})
+	}
+}
@@ -10,7 +10,13 @@ type secretService interface {
 
 	FindSecret(ctx context.Context, name string) (model.Secret, error)
 
-	FindAllSecrets(ctx context.Context) ([]model.Secret, error)
+	FindAllSecrets(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error)
 
 	DeleteSecret(ctx context.Context, name string) error
+
+	RenameSecret(ctx context.Context, from string, to string) error
+
+	UpsertSecrets(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error)
+
+	UpdateSecret(ctx context.Context, secret model.Secret) error
 }
@@ -5,9 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
-	"github.com/go-chi/chi/v5"
+	"github.com/desepticon55/gophkeeper/internal/server/api"
 	"go.uber.org/zap"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Handler to upload user secret
@@ -21,6 +24,11 @@ func UploadSecretHandler(logger *zap.Logger, service secretService) http.Handler
 		var secret model.Secret
 		if err := json.NewDecoder(request.Body).Decode(&secret); err != nil {
 			logger.Error("Error decode request", zap.Error(err))
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				http.Error(writer, "Request is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
 			http.Error(writer, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -36,11 +44,31 @@ func UploadSecretHandler(logger *zap.Logger, service secretService) http.Handler
 				return
 			}
 
+			if errors.Is(err, model.ErrSecretNameIsNotValid) {
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+				return
+			}
+
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
+
 			if errors.Is(err, model.ErrSecretExistToCurrentUser) {
 				http.Error(writer, "Secret already exists to current user", http.StatusConflict)
 				return
 			}
 
+			if errors.Is(err, model.ErrSecretTooLarge) {
+				http.Error(writer, "Secret size exceeds limit", http.StatusRequestEntityTooLarge)
+				return
+			}
+
+			if errors.Is(err, model.ErrQuotaExceeded) {
+				http.Error(writer, "Storage quota is exceeded", http.StatusRequestEntityTooLarge)
+				return
+			}
+
 			http.Error(writer, "Internal server error", http.StatusInternalServerError)
 			return
 		}
@@ -49,6 +77,61 @@ func UploadSecretHandler(logger *zap.Logger, service secretService) http.Handler
 	}
 }
 
+// Handler to create or update many user secrets in one transaction
+func UpsertSecretsHandler(logger *zap.Logger, service secretService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPut {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		var secrets []model.Secret
+		if err := json.NewDecoder(request.Body).Decode(&secrets); err != nil {
+			logger.Error("Error decode request", zap.Error(err))
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				http.Error(writer, "Request is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		result, err := service.UpsertSecrets(request.Context(), secrets)
+		if err != nil {
+			switch {
+			case errors.Is(err, model.ErrSecretBatchIsNotValid):
+				http.Error(writer, "Secrets batch is empty or contains duplicate names", http.StatusBadRequest)
+			case errors.Is(err, model.ErrSecretNameIsEmpty), errors.Is(err, model.ErrSecretNameIsNotValid):
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+			case errors.Is(err, model.ErrFolderAccessDenied):
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+			case errors.Is(err, model.ErrSecretTooLarge):
+				http.Error(writer, "Secret size exceeds limit", http.StatusRequestEntityTooLarge)
+			case errors.Is(err, model.ErrQuotaExceeded):
+				http.Error(writer, "Storage quota is exceeded", http.StatusRequestEntityTooLarge)
+			default:
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		bytes, err := json.Marshal(result)
+		if err != nil {
+			logger.Error("Error during marshal batch result.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		if _, err = writer.Write(bytes); err != nil {
+			logger.Error("Error write batch result.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 // Handler to read user secret by secret name
 func ReadOneSecretHandler(logger *zap.Logger, service secretService) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
@@ -57,7 +140,7 @@ func ReadOneSecretHandler(logger *zap.Logger, service secretService) http.Handle
 			return
 		}
 
-		secretName := chi.URLParam(request, "name")
+		secretName := api.WildcardParam(request)
 		if secretName == "" {
 			http.Error(writer, "Secret name should be filled", http.StatusBadRequest)
 			return
@@ -69,6 +152,14 @@ func ReadOneSecretHandler(logger *zap.Logger, service secretService) http.Handle
 				http.Error(writer, "Secret was not found", http.StatusNotFound)
 				return
 			}
+			if errors.Is(err, model.ErrSecretNameIsNotValid) {
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
 			logger.Error("Error during find secret.", zap.String("secretName", secretName), zap.Error(err))
 			http.Error(writer, "Internal server error", http.StatusInternalServerError)
 			return
@@ -99,7 +190,7 @@ func DeleteSecretHandler(logger *zap.Logger, service secretService) http.Handler
 			return
 		}
 
-		secretName := chi.URLParam(request, "name")
+		secretName := api.WildcardParam(request)
 		if secretName == "" {
 			http.Error(writer, "Secret name should be filled", http.StatusBadRequest)
 			return
@@ -111,6 +202,14 @@ func DeleteSecretHandler(logger *zap.Logger, service secretService) http.Handler
 				http.Error(writer, "Secrets was not found", http.StatusNotFound)
 				return
 			}
+			if errors.Is(err, model.ErrSecretNameIsNotValid) {
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
 			http.Error(writer, "Internal server error", http.StatusInternalServerError)
 			return
 		}
@@ -119,7 +218,7 @@ func DeleteSecretHandler(logger *zap.Logger, service secretService) http.Handler
 	}
 }
 
-// Handler to read all user secrets
+// Handler to read user secrets page. Supports filtering by name, type and tag
 func ReadAllSecretsHandler(logger *zap.Logger, service secretService) http.HandlerFunc {
 	return func(writer http.ResponseWriter, request *http.Request) {
 		if request.Method != http.MethodGet {
@@ -127,8 +226,37 @@ func ReadAllSecretsHandler(logger *zap.Logger, service secretService) http.Handl
 			return
 		}
 
-		secrets, err := service.FindAllSecrets(request.Context())
+		query := request.URL.Query()
+		filter := model.SecretFilter{
+			Name:   query.Get("name"),
+			Type:   query.Get("type"),
+			Tag:    query.Get("tag"),
+			Folder: query.Get("folder"),
+			Cursor: query.Get("cursor"),
+		}
+		if limit := query.Get("limit"); limit != "" {
+			parsedLimit, err := strconv.Atoi(limit)
+			if err != nil {
+				http.Error(writer, "Limit should be a number", http.StatusBadRequest)
+				return
+			}
+			filter.Limit = parsedLimit
+		}
+		if dueBefore := query.Get("due_before"); dueBefore != "" {
+			parsedDueBefore, err := time.Parse(time.RFC3339, dueBefore)
+			if err != nil {
+				http.Error(writer, "Due before should be a RFC 3339 time", http.StatusBadRequest)
+				return
+			}
+			filter.DueBefore = &parsedDueBefore
+		}
+
+		page, err := service.FindAllSecrets(request.Context(), filter)
 		if err != nil {
+			if errors.Is(err, model.ErrSecretFilterIsNotValid) {
+				http.Error(writer, "Invalid secret filter", http.StatusBadRequest)
+				return
+			}
 			if errors.Is(err, model.ErrSecretsWasNotFound) {
 				http.Error(writer, "Secrets was not found", http.StatusNoContent)
 				return
@@ -137,7 +265,7 @@ func ReadAllSecretsHandler(logger *zap.Logger, service secretService) http.Handl
 			return
 		}
 
-		bytes, err := json.Marshal(secrets)
+		bytes, err := json.Marshal(page)
 		if err != nil {
 			logger.Error("Error during marshal secrets.", zap.Error(err))
 			http.Error(writer, "Internal server error", http.StatusInternalServerError)
@@ -153,3 +281,104 @@ func ReadAllSecretsHandler(logger *zap.Logger, service secretService) http.Handl
 		writer.WriteHeader(http.StatusOK)
 	}
 }
+
+// Handler to rename user secret, route is "/{name}/rename" where name may contain slashes
+func RenameSecretHandler(logger *zap.Logger, service secretService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		const renameSuffix = "/rename"
+		path := api.WildcardParam(request)
+		if !strings.HasSuffix(path, renameSuffix) {
+			http.NotFound(writer, request)
+			return
+		}
+		secretName := strings.TrimSuffix(path, renameSuffix)
+
+		var rename model.SecretRename
+		if err := json.NewDecoder(request.Body).Decode(&rename); err != nil {
+			logger.Error("Error decode request", zap.Error(err))
+			http.Error(writer, "Invalid request payload", http.StatusBadRequest)
+			return
+		}
+
+		err := service.RenameSecret(request.Context(), secretName, rename.To)
+		if err != nil {
+			if errors.Is(err, model.ErrSecretNameIsEmpty) || errors.Is(err, model.ErrSecretNameIsNotValid) {
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+				return
+			}
+			if errors.Is(err, model.ErrFolderAccessDenied) {
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+				return
+			}
+			if errors.Is(err, model.ErrSecretWasNotFound) {
+				http.Error(writer, "Secret was not found", http.StatusNotFound)
+				return
+			}
+			if errors.Is(err, model.ErrSecretExistToCurrentUser) {
+				http.Error(writer, "Secret already exists to current user", http.StatusConflict)
+				return
+			}
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}
+}
+
+// Handler to update user secret read with version from request, secret changed by another request is not overwritten
+func UpdateSecretHandler(logger *zap.Logger, service secretService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPut {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		secretName := api.WildcardParam(request)
+		if secretName == "" {
+			http.Error(writer, "Secret name is not filled", http.StatusBadRequest)
+			return
+		}
+
+		var secret model.Secret
+		if err := json.NewDecoder(request.Body).Decode(&secret); err != nil {
+			logger.Error("Error decode request", zap.Error(err))
+			var maxBytesError *http.MaxBytesError
+			if errors.As(err, &maxBytesError) {
+				http.Error(writer, "Request is too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(writer, err.Error(), http.StatusBadRequest)
+			return
+		}
+		secret.Name = secretName
+
+		err := service.UpdateSecret(request.Context(), secret)
+		if err != nil {
+			switch {
+			case errors.Is(err, model.ErrSecretNameIsEmpty), errors.Is(err, model.ErrSecretNameIsNotValid):
+				http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+			case errors.Is(err, model.ErrFolderAccessDenied):
+				http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+			case errors.Is(err, model.ErrSecretWasNotFound):
+				http.Error(writer, "Secret was not found", http.StatusNotFound)
+			case errors.Is(err, model.ErrSecretVersionConflict):
+				http.Error(writer, "Secret was changed by another request", http.StatusConflict)
+			case errors.Is(err, model.ErrSecretTooLarge):
+				http.Error(writer, "Secret size exceeds limit", http.StatusRequestEntityTooLarge)
+			case errors.Is(err, model.ErrQuotaExceeded):
+				http.Error(writer, "Storage quota is exceeded", http.StatusRequestEntityTooLarge)
+			default:
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}
+}
@@ -13,13 +13,17 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 )
 
 type mockSecretService struct {
 	CreateSecretFunc   func(ctx context.Context, secret model.Secret) error
 	FindSecretFunc     func(ctx context.Context, name string) (model.Secret, error)
-	FindAllSecretsFunc func(ctx context.Context) ([]model.Secret, error)
+	FindAllSecretsFunc func(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error)
 	DeleteSecretFunc   func(ctx context.Context, name string) error
+	RenameSecretFunc   func(ctx context.Context, from string, to string) error
+	UpsertSecretsFunc  func(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error)
+	UpdateSecretFunc   func(ctx context.Context, secret model.Secret) error
 }
 
 func (m *mockSecretService) CreateSecret(ctx context.Context, secret model.Secret) error {
@@ -30,14 +34,26 @@ func (m *mockSecretService) FindSecret(ctx context.Context, name string) (model.
 	return m.FindSecretFunc(ctx, name)
 }
 
-func (m *mockSecretService) FindAllSecrets(ctx context.Context) ([]model.Secret, error) {
-	return m.FindAllSecretsFunc(ctx)
+func (m *mockSecretService) FindAllSecrets(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+	return m.FindAllSecretsFunc(ctx, filter)
 }
 
 func (m *mockSecretService) DeleteSecret(ctx context.Context, name string) error {
 	return m.DeleteSecretFunc(ctx, name)
 }
 
+func (m *mockSecretService) RenameSecret(ctx context.Context, from string, to string) error {
+	return m.RenameSecretFunc(ctx, from, to)
+}
+
+func (m *mockSecretService) UpsertSecrets(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+	return m.UpsertSecretsFunc(ctx, secrets)
+}
+
+func (m *mockSecretService) UpdateSecret(ctx context.Context, secret model.Secret) error {
+	return m.UpdateSecretFunc(ctx, secret)
+}
+
 func TestUploadSecretHandler(t *testing.T) {
 	logger := zaptest.NewLogger(t)
 	defer logger.Sync()
@@ -46,6 +62,7 @@ func TestUploadSecretHandler(t *testing.T) {
 		name           string
 		method         string
 		body           string
+		maxBytes       int64
 		service        secretService
 		expectedStatus int
 	}{
@@ -89,6 +106,25 @@ func TestUploadSecretHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusConflict,
 		},
+		{
+			name:   "Storage quota is exceeded",
+			method: http.MethodPost,
+			body:   `{"name":"testSecret", "content":"dGVzdCBjb250ZW50", "type":"CREDENTIALS", "version":1}`,
+			service: &mockSecretService{
+				CreateSecretFunc: func(ctx context.Context, secret model.Secret) error {
+					return model.ErrQuotaExceeded
+				},
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "Request is too large",
+			method:         http.MethodPost,
+			body:           `{"name":"testSecret", "content":"dGVzdCBjb250ZW50", "type":"CREDENTIALS", "version":1}`,
+			maxBytes:       16,
+			service:        nil,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
 	}
 
 	for _, tt := range tests {
@@ -96,6 +132,9 @@ func TestUploadSecretHandler(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/api/user/secret", strings.NewReader(tt.body))
 			req.Header.Set("Content-Type", "application/json")
 			rec := httptest.NewRecorder()
+			if tt.maxBytes > 0 {
+				req.Body = http.MaxBytesReader(rec, req.Body, tt.maxBytes)
+			}
 
 			handler := UploadSecretHandler(logger, tt.service)
 			handler.ServeHTTP(rec, req)
@@ -119,6 +158,19 @@ func TestReadOneSecretHandler(t *testing.T) {
 		Version: 1,
 	}
 
+	createdAt := time.Date(2024, 10, 1, 12, 0, 0, 0, time.UTC)
+	mockSecretWithMetadata := model.Secret{
+		Name:      "testSecret",
+		Content:   []byte("test content"),
+		Type:      "password",
+		Version:   1,
+		Notes:     []byte("notes"),
+		Tags:      []string{"work"},
+		URL:       "https://example.com",
+		CreatedAt: &createdAt,
+		UpdatedAt: &createdAt,
+	}
+
 	tests := []struct {
 		name           string
 		method         string
@@ -137,7 +189,20 @@ func TestReadOneSecretHandler(t *testing.T) {
 				},
 			},
 			expectedStatus: http.StatusOK,
-			expectedBody:   `{"name":"testSecret","content":"dGVzdCBjb250ZW50","type":"password"}`,
+			expectedBody:   `{"name":"testSecret","content":"dGVzdCBjb250ZW50","type":"password","version":1}`,
+		},
+		{
+			name:     "Successful operation with metadata",
+			method:   http.MethodGet,
+			urlParam: "testSecret",
+			service: &mockSecretService{
+				FindSecretFunc: func(ctx context.Context, name string) (model.Secret, error) {
+					return mockSecretWithMetadata, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody: `{"name":"testSecret","content":"dGVzdCBjb250ZW50","type":"password","version":1,"notes":"bm90ZXM=",` +
+				`"tags":["work"],"url":"https://example.com","created_at":"2024-10-01T12:00:00Z","updated_at":"2024-10-01T12:00:00Z"}`,
 		},
 		{
 			name:           "Invalid HTTP method",
@@ -164,6 +229,17 @@ func TestReadOneSecretHandler(t *testing.T) {
 			},
 			expectedStatus: http.StatusNotFound,
 		},
+		{
+			name:     "Access to folder is denied",
+			method:   http.MethodGet,
+			urlParam: "prod/db/testSecret",
+			service: &mockSecretService{
+				FindSecretFunc: func(ctx context.Context, name string) (model.Secret, error) {
+					return model.Secret{}, model.ErrFolderAccessDenied
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
 		{
 			name:     "Internal server error",
 			method:   http.MethodGet,
@@ -181,7 +257,7 @@ func TestReadOneSecretHandler(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			req := httptest.NewRequest(tt.method, "/api/user/secret"+tt.urlParam, nil)
 			routeContext := chi.NewRouteContext()
-			routeContext.URLParams.Add("name", tt.urlParam)
+			routeContext.URLParams.Add("*", tt.urlParam)
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
 
 			rec := httptest.NewRecorder()
@@ -264,7 +340,7 @@ func TestDeleteSecretHandler(t *testing.T) {
 			req := httptest.NewRequest(tt.method, fmt.Sprintf("/api/user/secret/%s", tt.paramName), nil)
 
 			routeCtx := chi.NewRouteContext()
-			routeCtx.URLParams.Add("name", tt.paramName)
+			routeCtx.URLParams.Add("*", tt.paramName)
 			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeCtx))
 
 			rec := httptest.NewRecorder()
@@ -278,3 +354,393 @@ func TestDeleteSecretHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestReadAllSecretsHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		query          string
+		service        secretService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Successful operation with filter",
+			method: http.MethodGet,
+			query:  "?name=prod*&type=CREDENTIALS&tag=work&cursor=a&limit=1",
+			service: &mockSecretService{
+				FindAllSecretsFunc: func(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+					expectedFilter := model.SecretFilter{Name: "prod*", Type: "CREDENTIALS", Tag: "work", Cursor: "a", Limit: 1}
+					if filter != expectedFilter {
+						return model.SecretPage{}, fmt.Errorf("unexpected filter: %+v", filter)
+					}
+					return model.SecretPage{
+						Secrets:    []model.Secret{{Name: "prod-db", Content: []byte("test content"), Type: "CREDENTIALS"}},
+						NextCursor: "prod-db",
+					}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"secrets":[{"name":"prod-db","content":"dGVzdCBjb250ZW50","type":"CREDENTIALS"}],"next_cursor":"prod-db"}`,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Limit is not a number",
+			method:         http.MethodGet,
+			query:          "?limit=ten",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Successful operation with due before",
+			method: http.MethodGet,
+			query:  "?due_before=2024-06-01T00:00:00Z",
+			service: &mockSecretService{
+				FindAllSecretsFunc: func(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+					if filter.DueBefore == nil || !filter.DueBefore.Equal(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)) {
+						return model.SecretPage{}, fmt.Errorf("unexpected filter: %+v", filter)
+					}
+					return model.SecretPage{Secrets: []model.Secret{{Name: "prod-db", Type: "CREDENTIALS"}}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"secrets":[{"name":"prod-db","content":null,"type":"CREDENTIALS"}]}`,
+		},
+		{
+			name:           "Due before is not a time",
+			method:         http.MethodGet,
+			query:          "?due_before=tomorrow",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Invalid filter",
+			method: http.MethodGet,
+			query:  "?limit=-1",
+			service: &mockSecretService{
+				FindAllSecretsFunc: func(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+					return model.SecretPage{}, model.ErrSecretFilterIsNotValid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Secrets were not found",
+			method: http.MethodGet,
+			service: &mockSecretService{
+				FindAllSecretsFunc: func(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
+					return model.SecretPage{}, model.ErrSecretsWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/secret"+tt.query, nil)
+			rec := httptest.NewRecorder()
+
+			handler := ReadAllSecretsHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				body, err := io.ReadAll(res.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRenameSecretHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		service        secretService
+		expectedStatus int
+	}{
+		{
+			name:   "Successful rename secret",
+			method: http.MethodPost,
+			path:   "prod/db/rename",
+			body:   `{"to":"stage/db"}`,
+			service: &mockSecretService{
+				RenameSecretFunc: func(ctx context.Context, from string, to string) error {
+					if from != "prod/db" || to != "stage/db" {
+						return fmt.Errorf("unexpected names: %s, %s", from, to)
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodGet,
+			path:           "prod/db/rename",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Unknown action",
+			method:         http.MethodPost,
+			path:           "prod/db",
+			service:        nil,
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:           "Invalid request payload",
+			method:         http.MethodPost,
+			path:           "prod/db/rename",
+			body:           `{"to":`,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Secret was not found",
+			method: http.MethodPost,
+			path:   "prod/db/rename",
+			body:   `{"to":"stage/db"}`,
+			service: &mockSecretService{
+				RenameSecretFunc: func(ctx context.Context, from string, to string) error {
+					return model.ErrSecretWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Target secret already exists",
+			method: http.MethodPost,
+			path:   "prod/db/rename",
+			body:   `{"to":"stage/db"}`,
+			service: &mockSecretService{
+				RenameSecretFunc: func(ctx context.Context, from string, to string) error {
+					return model.ErrSecretExistToCurrentUser
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/secret/"+tt.path, strings.NewReader(tt.body))
+			routeContext := chi.NewRouteContext()
+			routeContext.URLParams.Add("*", tt.path)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+
+			rec := httptest.NewRecorder()
+			handler := RenameSecretHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestUpsertSecretsHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		body           string
+		service        secretService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Successful upsert secrets",
+			method: http.MethodPut,
+			body:   `[{"name":"prod/db","content":"MQ==","type":"CREDENTIALS","version":3},{"name":"stage/db","content":"Mg==","type":"TEXT"}]`,
+			service: &mockSecretService{
+				UpsertSecretsFunc: func(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+					if len(secrets) != 2 || secrets[0].Version != 3 || string(secrets[1].Content) != "2" {
+						return model.SecretBatchResult{}, fmt.Errorf("unexpected secrets: %+v", secrets)
+					}
+					return model.SecretBatchResult{Created: 1, Updated: 1}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"created":1,"updated":1}`,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request payload",
+			method:         http.MethodPut,
+			body:           `{"name":"prod/db"}`,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Duplicate secret names",
+			method: http.MethodPut,
+			body:   `[{"name":"prod/db"},{"name":"prod/db"}]`,
+			service: &mockSecretService{
+				UpsertSecretsFunc: func(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+					return model.SecretBatchResult{}, model.ErrSecretBatchIsNotValid
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Storage quota is exceeded",
+			method: http.MethodPut,
+			body:   `[{"name":"prod/db"}]`,
+			service: &mockSecretService{
+				UpsertSecretsFunc: func(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+					return model.SecretBatchResult{}, model.ErrQuotaExceeded
+				},
+			},
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:   "Internal server error",
+			method: http.MethodPut,
+			body:   `[{"name":"prod/db"}]`,
+			service: &mockSecretService{
+				UpsertSecretsFunc: func(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+					return model.SecretBatchResult{}, errors.New("database error")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/secret", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+
+			handler := UpsertSecretsHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				body, err := io.ReadAll(res.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestUpdateSecretHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		service        secretService
+		expectedStatus int
+	}{
+		{
+			name:   "Successful update secret",
+			method: http.MethodPut,
+			path:   "otp/github",
+			body:   `{"name":"other", "content":"dGVzdCBjb250ZW50", "type":"OTP", "version":3}`,
+			service: &mockSecretService{
+				UpdateSecretFunc: func(ctx context.Context, secret model.Secret) error {
+					if secret.Name != "otp/github" || secret.Version != 3 {
+						return fmt.Errorf("unexpected secret: %s, %d", secret.Name, secret.Version)
+					}
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			path:           "otp/github",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Invalid request payload",
+			method:         http.MethodPut,
+			path:           "otp/github",
+			body:           `{"content":`,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Secret was not found",
+			method: http.MethodPut,
+			path:   "otp/github",
+			body:   `{"type":"OTP"}`,
+			service: &mockSecretService{
+				UpdateSecretFunc: func(ctx context.Context, secret model.Secret) error {
+					return model.ErrSecretWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:   "Secret was changed by another request",
+			method: http.MethodPut,
+			path:   "otp/github",
+			body:   `{"type":"OTP", "version":1}`,
+			service: &mockSecretService{
+				UpdateSecretFunc: func(ctx context.Context, secret model.Secret) error {
+					return model.ErrSecretVersionConflict
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/secret/"+tt.path, strings.NewReader(tt.body))
+			routeContext := chi.NewRouteContext()
+			routeContext.URLParams.Add("*", tt.path)
+			req = req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+
+			rec := httptest.NewRecorder()
+			handler := UpdateSecretHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
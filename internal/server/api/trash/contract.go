@@ -0,0 +1,14 @@
+package trash
+
+import (
+	"context"
+	"github.com/desepticon55/gophkeeper/internal/model"
+)
+
+type trashService interface {
+	FindTrash(ctx context.Context) ([]model.Secret, error)
+
+	RestoreSecret(ctx context.Context, name string) error
+
+	PurgeSecret(ctx context.Context, name string) error
+}
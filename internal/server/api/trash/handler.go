@@ -0,0 +1,116 @@
+package trash
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server/api"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// Handler to read user secrets in trash
+func ReadTrashHandler(logger *zap.Logger, service trashService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodGet {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		secrets, err := service.FindTrash(request.Context())
+		if err != nil {
+			if errors.Is(err, model.ErrTrashIsEmpty) {
+				http.Error(writer, "Trash is empty", http.StatusNoContent)
+				return
+			}
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		bytes, err := json.Marshal(secrets)
+		if err != nil {
+			logger.Error("Error during marshal secrets.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		if _, err = writer.Write(bytes); err != nil {
+			logger.Error("Error write secrets.", zap.Error(err))
+			http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Handler to restore user secret from trash by name
+func RestoreSecretHandler(logger *zap.Logger, service trashService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodPost {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		secretName := api.WildcardParam(request)
+		if secretName == "" {
+			http.Error(writer, "Secret name should be filled", http.StatusBadRequest)
+			return
+		}
+
+		err := service.RestoreSecret(request.Context(), secretName)
+		if err != nil {
+			if !writeSecretError(writer, err) {
+				logger.Error("Error during restore secret.", zap.String("secretName", secretName), zap.Error(err))
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}
+}
+
+// Handler to permanently delete user secret from trash by name
+func PurgeSecretHandler(logger *zap.Logger, service trashService) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		if request.Method != http.MethodDelete {
+			http.Error(writer, fmt.Sprintf("Method '%s' is not allowed", request.Method), http.StatusBadRequest)
+			return
+		}
+
+		secretName := api.WildcardParam(request)
+		if secretName == "" {
+			http.Error(writer, "Secret name should be filled", http.StatusBadRequest)
+			return
+		}
+
+		err := service.PurgeSecret(request.Context(), secretName)
+		if err != nil {
+			if !writeSecretError(writer, err) {
+				logger.Error("Error during purge secret.", zap.String("secretName", secretName), zap.Error(err))
+				http.Error(writer, "Internal server error", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		writer.WriteHeader(http.StatusOK)
+	}
+}
+
+// Write response for known secret errors, returns false for unexpected ones
+func writeSecretError(writer http.ResponseWriter, err error) bool {
+	switch {
+	case errors.Is(err, model.ErrSecretNameIsNotValid):
+		http.Error(writer, "Secret name is not valid", http.StatusBadRequest)
+	case errors.Is(err, model.ErrFolderAccessDenied):
+		http.Error(writer, "Access to folder is denied", http.StatusForbidden)
+	case errors.Is(err, model.ErrSecretWasNotFound):
+		http.Error(writer, "Secret was not found in trash", http.StatusNotFound)
+	case errors.Is(err, model.ErrSecretExistToCurrentUser):
+		http.Error(writer, "Secret already exists to current user", http.StatusConflict)
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,248 @@
+package trash
+
+import (
+	"context"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type mockTrashService struct {
+	FindTrashFunc     func(ctx context.Context) ([]model.Secret, error)
+	RestoreSecretFunc func(ctx context.Context, name string) error
+	PurgeSecretFunc   func(ctx context.Context, name string) error
+}
+
+func (m *mockTrashService) FindTrash(ctx context.Context) ([]model.Secret, error) {
+	return m.FindTrashFunc(ctx)
+}
+
+func (m *mockTrashService) RestoreSecret(ctx context.Context, name string) error {
+	return m.RestoreSecretFunc(ctx, name)
+}
+
+func (m *mockTrashService) PurgeSecret(ctx context.Context, name string) error {
+	return m.PurgeSecretFunc(ctx, name)
+}
+
+func TestReadTrashHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	deletedAt := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		method         string
+		service        trashService
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:   "Successful operation",
+			method: http.MethodGet,
+			service: &mockTrashService{
+				FindTrashFunc: func(ctx context.Context) ([]model.Secret, error) {
+					return []model.Secret{{Name: "prod/db", Content: []byte("pwd"), Type: model.CredentialsSecretType, DeletedAt: &deletedAt}}, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+			expectedBody:   `[{"name":"prod/db","content":"cHdk","type":"CREDENTIALS","deleted_at":"2024-05-01T10:00:00Z"}]`,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:   "Trash is empty",
+			method: http.MethodGet,
+			service: &mockTrashService{
+				FindTrashFunc: func(ctx context.Context) ([]model.Secret, error) {
+					return nil, model.ErrTrashIsEmpty
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/user/trash", nil)
+			rec := httptest.NewRecorder()
+
+			handler := ReadTrashHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+
+			if tt.expectedStatus == http.StatusOK {
+				body, err := io.ReadAll(res.Body)
+				assert.NoError(t, err)
+				assert.JSONEq(t, tt.expectedBody, string(body))
+			}
+		})
+	}
+}
+
+func TestRestoreSecretHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		secretName     string
+		service        trashService
+		expectedStatus int
+	}{
+		{
+			name:       "Successful restore",
+			method:     http.MethodPost,
+			secretName: "prod/db",
+			service: &mockTrashService{
+				RestoreSecretFunc: func(ctx context.Context, name string) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodGet,
+			secretName:     "prod/db",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:           "Empty secret name",
+			method:         http.MethodPost,
+			secretName:     "",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Secret was not found in trash",
+			method:     http.MethodPost,
+			secretName: "prod/db",
+			service: &mockTrashService{
+				RestoreSecretFunc: func(ctx context.Context, name string) error {
+					return model.ErrSecretWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Secret with the same name exists",
+			method:     http.MethodPost,
+			secretName: "prod/db",
+			service: &mockTrashService{
+				RestoreSecretFunc: func(ctx context.Context, name string) error {
+					return model.ErrSecretExistToCurrentUser
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newWildcardRequest(tt.method, tt.secretName)
+			rec := httptest.NewRecorder()
+
+			handler := RestoreSecretHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func TestPurgeSecretHandler(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	defer logger.Sync()
+
+	tests := []struct {
+		name           string
+		method         string
+		secretName     string
+		service        trashService
+		expectedStatus int
+	}{
+		{
+			name:       "Successful purge",
+			method:     http.MethodDelete,
+			secretName: "prod/db",
+			service: &mockTrashService{
+				PurgeSecretFunc: func(ctx context.Context, name string) error {
+					return nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Invalid HTTP method",
+			method:         http.MethodPost,
+			secretName:     "prod/db",
+			service:        nil,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "Secret was not found in trash",
+			method:     http.MethodDelete,
+			secretName: "prod/db",
+			service: &mockTrashService{
+				PurgeSecretFunc: func(ctx context.Context, name string) error {
+					return model.ErrSecretWasNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name:       "Access to folder is denied",
+			method:     http.MethodDelete,
+			secretName: "private/db",
+			service: &mockTrashService{
+				PurgeSecretFunc: func(ctx context.Context, name string) error {
+					return model.ErrFolderAccessDenied
+				},
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := newWildcardRequest(tt.method, tt.secretName)
+			rec := httptest.NewRecorder()
+
+			handler := PurgeSecretHandler(logger, tt.service)
+			handler.ServeHTTP(rec, req)
+
+			res := rec.Result()
+			defer res.Body.Close()
+
+			assert.Equal(t, tt.expectedStatus, res.StatusCode)
+		})
+	}
+}
+
+func newWildcardRequest(method string, secretName string) *http.Request {
+	req := httptest.NewRequest(method, "/api/user/trash/"+secretName, nil)
+	routeContext := chi.NewRouteContext()
+	routeContext.URLParams.Add("*", secretName)
+	return req.WithContext(context.WithValue(req.Context(), chi.RouteCtxKey, routeContext))
+}
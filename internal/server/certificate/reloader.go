@@ -0,0 +1,144 @@
+package certificate
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"go.uber.org/zap"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Server certificate loaded from PEM files, new certificate is used without restart when files change
+type Reloader struct {
+	logger   *zap.Logger
+	certFile string
+	keyFile  string
+
+	mu          sync.RWMutex
+	certificate *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+}
+
+func NewReloader(logger *zap.Logger, certFile string, keyFile string) (*Reloader, error) {
+	reloader := &Reloader{logger: logger, certFile: certFile, keyFile: keyFile}
+	if _, err := reloader.Reload(); err != nil {
+		return nil, err
+	}
+	return reloader, nil
+}
+
+// Certificate callback of TLS config
+func (r *Reloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.certificate, nil
+}
+
+// Load certificate when files were changed since last load. Current certificate is kept when new one is invalid
+func (r *Reloader) Reload() (bool, error) {
+	certModTime, err := modTime(r.certFile)
+	if err != nil {
+		return false, err
+	}
+	keyModTime, err := modTime(r.keyFile)
+	if err != nil {
+		return false, err
+	}
+
+	r.mu.RLock()
+	changed := r.certificate == nil || !certModTime.Equal(r.certModTime) || !keyModTime.Equal(r.keyModTime)
+	r.mu.RUnlock()
+	if !changed {
+		return false, nil
+	}
+
+	certificate, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return false, fmt.Errorf("error during load server certificate: %w", err)
+	}
+
+	r.mu.Lock()
+	r.certificate = &certificate
+	r.certModTime = certModTime
+	r.keyModTime = keyModTime
+	r.mu.Unlock()
+	return true, nil
+}
+
+// Check certificate files every interval until context is done
+func (r *Reloader) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reloaded, err := r.Reload()
+		if err != nil {
+			r.logger.Error("Error during reload server certificate", zap.Error(err))
+		} else if reloaded {
+			r.logger.Info("Server certificate was reloaded", zap.String("certificate", r.certFile))
+		}
+	}
+}
+
+func modTime(path string) (time.Time, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("error during read certificate file: %w", err)
+	}
+	return info.ModTime(), nil
+}
+
+// TLS settings of server. Client certificates are verified by client CA when it is configured
+func NewServerTLSConfig(config server.Config, reloader *Reloader) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: reloader.GetCertificate,
+	}
+	if config.TLSClientCAFile == "" {
+		if config.TLSRequireClientCert {
+			return nil, errors.New("client CA file is required to verify client certificates")
+		}
+		return tlsConfig, nil
+	}
+
+	data, err := os.ReadFile(config.TLSClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error during read client CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, errors.New("client CA file has no PEM certificates")
+	}
+	tlsConfig.ClientCAs = pool
+	tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+	if config.TLSRequireClientCert {
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// User name of verified client certificate: first email address, or common name when certificate has no email.
+// Empty name is returned for requests without verified certificate
+func UserName(request *http.Request) string {
+	if request.TLS == nil || len(request.TLS.VerifiedChains) == 0 || len(request.TLS.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	certificate := request.TLS.VerifiedChains[0][0]
+	if len(certificate.EmailAddresses) > 0 {
+		return certificate.EmailAddresses[0]
+	}
+	return certificate.Subject.CommonName
+}
@@ -0,0 +1,194 @@
+package certificate
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// Certificate with private key in PEM format
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+	certPEM     []byte
+	keyPEM      []byte
+}
+
+// Create certificate signed by parent, self signed CA when parent is nil
+func newTestCertificate(t *testing.T, template *x509.Certificate, parent *testCertificate) testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	serial, err := rand.Int(rand.Reader, big.NewInt(1<<62))
+	require.NoError(t, err)
+	template.SerialNumber = serial
+	template.NotBefore = time.Now().Add(-time.Hour)
+	template.NotAfter = time.Now().Add(time.Hour)
+
+	signer, signerKey := template, key
+	if parent != nil {
+		signer, signerKey = parent.certificate, parent.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	return testCertificate{
+		certificate: certificate,
+		key:         key,
+		certPEM:     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:      pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+}
+
+func newTestCA(t *testing.T) testCertificate {
+	return newTestCertificate(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil)
+}
+
+func writeTestCertificate(t *testing.T, dir string, certificate testCertificate, modTime time.Time) (string, string) {
+	certFile, keyFile := filepath.Join(dir, "server.crt"), filepath.Join(dir, "server.key")
+	require.NoError(t, os.WriteFile(certFile, certificate.certPEM, 0600))
+	require.NoError(t, os.WriteFile(keyFile, certificate.keyPEM, 0600))
+	require.NoError(t, os.Chtimes(certFile, modTime, modTime))
+	require.NoError(t, os.Chtimes(keyFile, modTime, modTime))
+	return certFile, keyFile
+}
+
+func TestReloader(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ca := newTestCA(t)
+	serverTemplate := func() *x509.Certificate {
+		return &x509.Certificate{Subject: pkix.Name{CommonName: "localhost"}, DNSNames: []string{"localhost"},
+			ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}
+	}
+	dir := t.TempDir()
+	first := newTestCertificate(t, serverTemplate(), &ca)
+	certFile, keyFile := writeTestCertificate(t, dir, first, time.Now().Add(-time.Minute))
+
+	reloader, err := NewReloader(logger, certFile, keyFile)
+	require.NoError(t, err)
+	current, err := reloader.GetCertificate(nil)
+	require.NoError(t, err)
+	assert.Equal(t, first.certificate.Raw, current.Certificate[0])
+
+	t.Run("should not reload unchanged files", func(t *testing.T) {
+		reloaded, err := reloader.Reload()
+		require.NoError(t, err)
+		assert.False(t, reloaded)
+	})
+
+	t.Run("should keep certificate when new files are invalid", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(certFile, []byte("broken"), 0600))
+		reloaded, err := reloader.Reload()
+		assert.Error(t, err)
+		assert.False(t, reloaded)
+		current, err := reloader.GetCertificate(nil)
+		require.NoError(t, err)
+		assert.Equal(t, first.certificate.Raw, current.Certificate[0])
+	})
+
+	t.Run("should reload changed files in background", func(t *testing.T) {
+		second := newTestCertificate(t, serverTemplate(), &ca)
+		writeTestCertificate(t, dir, second, time.Now())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			reloader.Run(ctx, 10*time.Millisecond)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool {
+			current, err := reloader.GetCertificate(nil)
+			return err == nil && string(current.Certificate[0]) == string(second.certificate.Raw)
+		}, time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+	})
+
+	t.Run("should return error when files are missing", func(t *testing.T) {
+		_, err := NewReloader(logger, filepath.Join(dir, "missing.crt"), keyFile)
+		assert.Error(t, err)
+	})
+}
+
+func TestNewServerTLSConfig(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+	ca := newTestCA(t)
+	dir := t.TempDir()
+	serverCertificate := newTestCertificate(t, &x509.Certificate{Subject: pkix.Name{CommonName: "localhost"},
+		IPAddresses: []net.IP{net.ParseIP("127.0.0.1")}, ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}}, &ca)
+	certFile, keyFile := writeTestCertificate(t, dir, serverCertificate, time.Now())
+	caFile := filepath.Join(dir, "ca.crt")
+	require.NoError(t, os.WriteFile(caFile, ca.certPEM, 0600))
+
+	reloader, err := NewReloader(logger, certFile, keyFile)
+	require.NoError(t, err)
+
+	_, err = NewServerTLSConfig(server.Config{TLSRequireClientCert: true}, reloader)
+	assert.Error(t, err)
+	_, err = NewServerTLSConfig(server.Config{TLSClientCAFile: certFile + ".missing"}, reloader)
+	assert.Error(t, err)
+
+	tlsConfig, err := NewServerTLSConfig(server.Config{TLSClientCAFile: caFile}, reloader)
+	require.NoError(t, err)
+	assert.Equal(t, tls.VerifyClientCertIfGiven, tlsConfig.ClientAuth)
+
+	testServer := httptest.NewUnstartedServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		io.WriteString(writer, UserName(request))
+	}))
+	// httptest certificate would be preferred to GetCertificate, so TLS listener is created by config under test
+	testServer.Listener = tls.NewListener(testServer.Listener, tlsConfig)
+	testServer.Start()
+	defer testServer.Close()
+	url := "https://" + testServer.Listener.Addr().String()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.certificate)
+	request := func(clientCertificates ...tls.Certificate) string {
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: roots, Certificates: clientCertificates}}}
+		response, err := client.Get(url)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		body, err := io.ReadAll(response.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+	clientCertificate := func(template *x509.Certificate) tls.Certificate {
+		template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+		created := newTestCertificate(t, template, &ca)
+		pair, err := tls.X509KeyPair(created.certPEM, created.keyPEM)
+		require.NoError(t, err)
+		return pair
+	}
+
+	assert.Equal(t, "", request())
+	assert.Equal(t, "user@mail.com", request(clientCertificate(&x509.Certificate{Subject: pkix.Name{CommonName: "user"}, EmailAddresses: []string{"user@mail.com"}})))
+	assert.Equal(t, "user", request(clientCertificate(&x509.Certificate{Subject: pkix.Name{CommonName: "user"}})))
+}
@@ -11,6 +11,27 @@ type Config struct {
 	DatabaseConnString string
 	AuthKey            string
 	ExpirationMinutes  int
+	// How long deleted secrets are kept in trash
+	TrashRetentionHours int
+	// Per-user storage limits, zero means unlimited
+	MaxSecretsPerUser int64
+	MaxSecretSize     int64
+	MaxTotalSize      int64
+	// Max size of request body in bytes, zero means unlimited
+	MaxRequestSize int64
+	// PEM files with server certificate and key, HTTPS is served when both are set
+	TLSCertFile string
+	TLSKeyFile  string
+	// How often certificate files are checked for changes
+	TLSReloadSeconds int
+	// PEM file with CA certificates of client certificates, users are authenticated by certificate when it is set
+	TLSClientCAFile string
+	// Reject connections without client certificate
+	TLSRequireClientCert bool
+	// Time between readiness is turned off and listener is closed, so load balancers stop routing first
+	ShutdownDelaySeconds int
+	// Max time to drain in-flight requests on shutdown
+	ShutdownTimeoutSeconds int
 }
 
 func ParseConfig() Config {
@@ -40,11 +61,113 @@ func ParseConfig() Config {
 	}
 	expirationMinutes := flag.Int("e", defaultExpirationMinutes, "Expiration time (minutes)")
 
+	defaultTrashRetentionHours := 720
+	if envTrashRetentionHours, exists := os.LookupEnv("TRASH_RETENTION_HOURS"); exists {
+		if parsedTrashRetentionHours, err := strconv.Atoi(envTrashRetentionHours); err == nil {
+			defaultTrashRetentionHours = parsedTrashRetentionHours
+		}
+	}
+	trashRetentionHours := flag.Int("t", defaultTrashRetentionHours, "Trash retention time (hours)")
+
+	defaultMaxSecretsPerUser := int64(10000)
+	if envMaxSecretsPerUser, exists := os.LookupEnv("QUOTA_MAX_SECRETS"); exists {
+		if parsedMaxSecretsPerUser, err := strconv.ParseInt(envMaxSecretsPerUser, 10, 64); err == nil {
+			defaultMaxSecretsPerUser = parsedMaxSecretsPerUser
+		}
+	}
+	maxSecretsPerUser := flag.Int64("quota-max-secrets", defaultMaxSecretsPerUser, "Max count of secrets per user")
+
+	defaultMaxSecretSize := int64(1 << 20)
+	if envMaxSecretSize, exists := os.LookupEnv("QUOTA_MAX_SECRET_SIZE"); exists {
+		if parsedMaxSecretSize, err := strconv.ParseInt(envMaxSecretSize, 10, 64); err == nil {
+			defaultMaxSecretSize = parsedMaxSecretSize
+		}
+	}
+	maxSecretSize := flag.Int64("quota-max-secret-size", defaultMaxSecretSize, "Max size of one secret (bytes)")
+
+	defaultMaxTotalSize := int64(100 << 20)
+	if envMaxTotalSize, exists := os.LookupEnv("QUOTA_MAX_TOTAL_SIZE"); exists {
+		if parsedMaxTotalSize, err := strconv.ParseInt(envMaxTotalSize, 10, 64); err == nil {
+			defaultMaxTotalSize = parsedMaxTotalSize
+		}
+	}
+	maxTotalSize := flag.Int64("quota-max-total-size", defaultMaxTotalSize, "Max total size of secrets per user (bytes)")
+
+	defaultMaxRequestSize := int64(2 << 20)
+	if envMaxRequestSize, exists := os.LookupEnv("MAX_REQUEST_SIZE"); exists {
+		if parsedMaxRequestSize, err := strconv.ParseInt(envMaxRequestSize, 10, 64); err == nil {
+			defaultMaxRequestSize = parsedMaxRequestSize
+		}
+	}
+	maxRequestSize := flag.Int64("max-request-size", defaultMaxRequestSize, "Max size of request body (bytes)")
+
+	defaultTLSCertFile := ""
+	if envTLSCertFile, exists := os.LookupEnv("TLS_CERT_FILE"); exists {
+		defaultTLSCertFile = envTLSCertFile
+	}
+	tlsCertFile := flag.String("tls-cert", defaultTLSCertFile, "PEM file with server certificate")
+
+	defaultTLSKeyFile := ""
+	if envTLSKeyFile, exists := os.LookupEnv("TLS_KEY_FILE"); exists {
+		defaultTLSKeyFile = envTLSKeyFile
+	}
+	tlsKeyFile := flag.String("tls-key", defaultTLSKeyFile, "PEM file with server private key")
+
+	defaultTLSReloadSeconds := 60
+	if envTLSReloadSeconds, exists := os.LookupEnv("TLS_RELOAD_SECONDS"); exists {
+		if parsedTLSReloadSeconds, err := strconv.Atoi(envTLSReloadSeconds); err == nil {
+			defaultTLSReloadSeconds = parsedTLSReloadSeconds
+		}
+	}
+	tlsReloadSeconds := flag.Int("tls-reload-seconds", defaultTLSReloadSeconds, "Interval of certificate files change check (seconds)")
+
+	defaultTLSClientCAFile := ""
+	if envTLSClientCAFile, exists := os.LookupEnv("TLS_CLIENT_CA_FILE"); exists {
+		defaultTLSClientCAFile = envTLSClientCAFile
+	}
+	tlsClientCAFile := flag.String("tls-client-ca", defaultTLSClientCAFile, "PEM file with CA certificates of client certificates")
+
+	defaultTLSRequireClientCert := false
+	if envTLSRequireClientCert, exists := os.LookupEnv("TLS_REQUIRE_CLIENT_CERT"); exists {
+		if parsedTLSRequireClientCert, err := strconv.ParseBool(envTLSRequireClientCert); err == nil {
+			defaultTLSRequireClientCert = parsedTLSRequireClientCert
+		}
+	}
+	tlsRequireClientCert := flag.Bool("tls-require-client-cert", defaultTLSRequireClientCert, "Reject connections without client certificate")
+
+	defaultShutdownDelaySeconds := 5
+	if envShutdownDelaySeconds, exists := os.LookupEnv("SHUTDOWN_DELAY_SECONDS"); exists {
+		if parsedShutdownDelaySeconds, err := strconv.Atoi(envShutdownDelaySeconds); err == nil {
+			defaultShutdownDelaySeconds = parsedShutdownDelaySeconds
+		}
+	}
+	shutdownDelaySeconds := flag.Int("shutdown-delay", defaultShutdownDelaySeconds, "Delay between readiness off and listener close on shutdown (seconds)")
+
+	defaultShutdownTimeoutSeconds := 30
+	if envShutdownTimeoutSeconds, exists := os.LookupEnv("SHUTDOWN_TIMEOUT_SECONDS"); exists {
+		if parsedShutdownTimeoutSeconds, err := strconv.Atoi(envShutdownTimeoutSeconds); err == nil {
+			defaultShutdownTimeoutSeconds = parsedShutdownTimeoutSeconds
+		}
+	}
+	shutdownTimeoutSeconds := flag.Int("shutdown-timeout", defaultShutdownTimeoutSeconds, "Max time to drain requests on shutdown (seconds)")
+
 	flag.Parse()
 	return Config{
-		ServerAddress:      *address,
-		DatabaseConnString: *databaseConnString,
-		AuthKey:            *authKey,
-		ExpirationMinutes:  *expirationMinutes,
+		ServerAddress:          *address,
+		DatabaseConnString:     *databaseConnString,
+		AuthKey:                *authKey,
+		ExpirationMinutes:      *expirationMinutes,
+		TrashRetentionHours:    *trashRetentionHours,
+		MaxSecretsPerUser:      *maxSecretsPerUser,
+		MaxSecretSize:          *maxSecretSize,
+		MaxTotalSize:           *maxTotalSize,
+		MaxRequestSize:         *maxRequestSize,
+		TLSCertFile:            *tlsCertFile,
+		TLSKeyFile:             *tlsKeyFile,
+		TLSReloadSeconds:       *tlsReloadSeconds,
+		TLSClientCAFile:        *tlsClientCAFile,
+		TLSRequireClientCert:   *tlsRequireClientCert,
+		ShutdownDelaySeconds:   *shutdownDelaySeconds,
+		ShutdownTimeoutSeconds: *shutdownTimeoutSeconds,
 	}
 }
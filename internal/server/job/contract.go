@@ -0,0 +1,10 @@
+package job
+
+import (
+	"context"
+	"time"
+)
+
+type trashService interface {
+	PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error)
+}
@@ -0,0 +1,28 @@
+package job
+
+import (
+	"context"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Purge secrets kept in trash longer than retention every interval until context is done
+func RunTrashPurge(ctx context.Context, logger *zap.Logger, service trashService, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := service.PurgeExpiredTrash(ctx, retention)
+		if err != nil {
+			logger.Error("Error during purge expired trash", zap.Error(err))
+		} else if purged > 0 {
+			logger.Info("Expired secrets were purged from trash", zap.Int64("count", purged))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mockTrashService struct {
+	calls     atomic.Int32
+	retention atomic.Int64
+	err       error
+}
+
+func (m *mockTrashService) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	m.calls.Add(1)
+	m.retention.Store(int64(retention))
+	return 1, m.err
+}
+
+func TestRunTrashPurge(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should purge trash until context is done", func(t *testing.T) {
+		service := &mockTrashService{}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan struct{})
+		go func() {
+			RunTrashPurge(ctx, logger, service, time.Hour, 10*time.Millisecond)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return service.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+
+		assert.Equal(t, int64(time.Hour), service.retention.Load())
+	})
+
+	t.Run("should continue after error", func(t *testing.T) {
+		service := &mockTrashService{err: errors.New("database error")}
+		ctx, cancel := context.WithCancel(context.Background())
+
+		done := make(chan struct{})
+		go func() {
+			RunTrashPurge(ctx, logger, service, time.Hour, 10*time.Millisecond)
+			close(done)
+		}()
+
+		assert.Eventually(t, func() bool { return service.calls.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		cancel()
+		<-done
+	})
+}
@@ -0,0 +1,155 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"go.uber.org/zap"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Server accepts traffic from load balancers only when it is ready
+type Readiness struct {
+	ready atomic.Bool
+}
+
+func (r *Readiness) SetReady(ready bool) {
+	r.ready.Store(ready)
+}
+
+func (r *Readiness) IsReady() bool {
+	return r.ready.Load()
+}
+
+// Background workers stopped on shutdown after HTTP server is drained
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Run worker until shutdown, worker should return when context is done
+func (w *Workers) Go(worker func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		worker(w.ctx)
+	}()
+}
+
+// Cancel workers and wait until all of them return
+func (w *Workers) Stop() {
+	w.cancel()
+	w.wg.Wait()
+}
+
+// Resource closed last on shutdown, e.g. DB pool
+type closer struct {
+	name  string
+	close func()
+}
+
+// HTTP server with ordered shutdown: readiness is turned off, listener is closed after delay,
+// in-flight requests are drained, then workers are stopped and resources are closed
+type Server struct {
+	logger          *zap.Logger
+	httpServer      *http.Server
+	readiness       *Readiness
+	workers         *Workers
+	closers         []closer
+	shutdownDelay   time.Duration
+	shutdownTimeout time.Duration
+}
+
+func NewServer(logger *zap.Logger, httpServer *http.Server, readiness *Readiness) *Server {
+	return &Server{logger: logger, httpServer: httpServer, readiness: readiness, workers: NewWorkers()}
+}
+
+func (s *Server) WithWorkers(workers *Workers) *Server {
+	s.workers = workers
+	return s
+}
+
+// Add resource closed on shutdown, resources are closed in order of adding
+func (s *Server) WithCloser(name string, close func()) *Server {
+	s.closers = append(s.closers, closer{name: name, close: close})
+	return s
+}
+
+func (s *Server) WithShutdownDelay(delay time.Duration) *Server {
+	s.shutdownDelay = delay
+	return s
+}
+
+func (s *Server) WithShutdownTimeout(timeout time.Duration) *Server {
+	s.shutdownTimeout = timeout
+	return s
+}
+
+// Serve requests until context is done or serve fails, then shutdown server. Server becomes ready
+// only after listener is bound. Error of listen or serve is returned, closed server is not an error
+func (s *Server) Run(ctx context.Context, serve func(httpServer *http.Server, listener net.Listener) error) error {
+	listener, err := net.Listen("tcp", s.httpServer.Addr)
+	if err != nil {
+		s.logger.Error("Error during listen address", zap.String("address", s.httpServer.Addr), zap.Error(err))
+		s.shutdown(false)
+		return err
+	}
+
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(s.httpServer, listener)
+	}()
+	s.readiness.SetReady(true)
+	s.logger.Info("Server is ready", zap.String("address", listener.Addr().String()))
+
+	select {
+	case <-ctx.Done():
+		s.logger.Info("Shutdown signal received")
+	case err = <-served:
+		if errors.Is(err, http.ErrServerClosed) {
+			err = nil
+		} else {
+			s.logger.Error("Error during serve requests", zap.Error(err))
+		}
+	}
+
+	s.shutdown(err == nil)
+	return err
+}
+
+func (s *Server) shutdown(serving bool) {
+	s.readiness.SetReady(false)
+	if serving && s.shutdownDelay > 0 {
+		s.logger.Info("Server is not ready, waiting for load balancers", zap.Duration("delay", s.shutdownDelay))
+		time.Sleep(s.shutdownDelay)
+	}
+
+	ctx := context.Background()
+	if s.shutdownTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.shutdownTimeout)
+		defer cancel()
+	}
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		s.logger.Error("Error during drain requests, connections are closed", zap.Error(err))
+		s.httpServer.Close()
+	}
+	s.logger.Info("HTTP server was stopped")
+
+	s.workers.Stop()
+	s.logger.Info("Background workers were stopped")
+
+	for _, closer := range s.closers {
+		closer.close()
+		s.logger.Info("Resource was closed", zap.String("name", closer.name))
+	}
+}
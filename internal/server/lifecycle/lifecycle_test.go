@@ -0,0 +1,133 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap/zaptest"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// Record of shutdown steps in order of execution
+type steps struct {
+	mu    sync.Mutex
+	names []string
+}
+
+func (s *steps) add(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = append(s.names, name)
+}
+
+func (s *steps) list() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.names...)
+}
+
+func TestServer_Run(t *testing.T) {
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should drain requests and stop workers and resources in order", func(t *testing.T) {
+		recorded := &steps{}
+		started := make(chan struct{})
+		release := make(chan struct{})
+		httpServer := &http.Server{Addr: "127.0.0.1:0", Handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			close(started)
+			<-release
+			io.WriteString(writer, "done")
+			recorded.add("request")
+		})}
+
+		readiness := &Readiness{}
+		workers := NewWorkers()
+		workers.Go(func(ctx context.Context) {
+			<-ctx.Done()
+			recorded.add("worker")
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		address := make(chan string, 1)
+		result := make(chan error, 1)
+		go func() {
+			result <- NewServer(logger, httpServer, readiness).
+				WithWorkers(workers).
+				WithCloser("pool", func() { recorded.add("pool") }).
+				WithShutdownDelay(50*time.Millisecond).
+				WithShutdownTimeout(time.Second).
+				Run(ctx, func(httpServer *http.Server, listener net.Listener) error {
+					address <- listener.Addr().String()
+					return httpServer.Serve(listener)
+				})
+		}()
+		addr := <-address
+		assert.Eventually(t, readiness.IsReady, time.Second, time.Millisecond)
+
+		response := make(chan string, 1)
+		go func() {
+			resp, err := http.Get("http://" + addr)
+			if err != nil {
+				response <- err.Error()
+				return
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			response <- string(body)
+		}()
+		<-started
+
+		cancel()
+		assert.Eventually(t, func() bool { return !readiness.IsReady() }, time.Second, time.Millisecond)
+		time.Sleep(100 * time.Millisecond)
+		assert.Empty(t, recorded.list(), "workers should wait for in-flight requests")
+		close(release)
+
+		require.NoError(t, <-result)
+		assert.Equal(t, "done", <-response)
+		assert.Equal(t, []string{"request", "worker", "pool"}, recorded.list())
+	})
+
+	t.Run("should return serve error and shutdown without delay", func(t *testing.T) {
+		closed := false
+		readiness := &Readiness{}
+		serveErr := errors.New("tls: no certificates configured")
+
+		start := time.Now()
+		err := NewServer(logger, &http.Server{Addr: "127.0.0.1:0"}, readiness).
+			WithCloser("pool", func() { closed = true }).
+			WithShutdownDelay(time.Hour).
+			Run(context.Background(), func(httpServer *http.Server, listener net.Listener) error { return serveErr })
+		assert.ErrorIs(t, err, serveErr)
+		assert.True(t, closed)
+		assert.False(t, readiness.IsReady())
+		assert.Less(t, time.Since(start), time.Minute)
+	})
+
+	t.Run("should not become ready when address is in use", func(t *testing.T) {
+		busy, err := net.Listen("tcp", "127.0.0.1:0")
+		require.NoError(t, err)
+		defer busy.Close()
+
+		closed := false
+		served := false
+		readiness := &Readiness{}
+		err = NewServer(logger, &http.Server{Addr: busy.Addr().String()}, readiness).
+			WithCloser("pool", func() { closed = true }).
+			WithShutdownDelay(time.Hour).
+			Run(context.Background(), func(httpServer *http.Server, listener net.Listener) error {
+				served = true
+				return nil
+			})
+		assert.Error(t, err)
+		assert.False(t, served)
+		assert.True(t, closed)
+		assert.False(t, readiness.IsReady())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"github.com/desepticon55/gophkeeper/internal/model"
 	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/desepticon55/gophkeeper/internal/server/certificate"
 	"github.com/golang-jwt/jwt/v4"
 	"go.uber.org/zap"
 	"io"
@@ -17,6 +18,13 @@ func CheckAuthMiddleware(logger *zap.Logger, config server.Config) func(http.Han
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
 			const bearerPrefix = "Bearer "
 			authHeader := request.Header.Get("Authorization")
+			certificateUser := certificate.UserName(request)
+			if authHeader == "" && certificateUser != "" {
+				ctx := context.WithValue(request.Context(), server.UserNameContextKey, certificateUser)
+				next.ServeHTTP(writer, request.WithContext(ctx))
+				return
+			}
+
 			if authHeader == "" {
 				logger.Error("Authorization header is missing", zap.String("Authorization", authHeader))
 				http.Error(writer, "Invalid token", http.StatusUnauthorized)
@@ -47,6 +55,13 @@ func CheckAuthMiddleware(logger *zap.Logger, config server.Config) func(http.Han
 				return
 			}
 
+			if certificateUser != "" && certificateUser != claims.Username {
+				logger.Error("Token user does not match client certificate",
+					zap.String("token user", claims.Username), zap.String("certificate user", certificateUser))
+				http.Error(writer, "Token does not match client certificate", http.StatusForbidden)
+				return
+			}
+
 			ctx := context.WithValue(request.Context(), server.UserNameContextKey, claims.Username)
 			next.ServeHTTP(writer, request.WithContext(ctx))
 		})
@@ -70,6 +85,18 @@ func DecompressingMiddleware() func(http.Handler) http.Handler {
 	}
 }
 
+// Limit size of request body, reading more than maxBytes returns *http.MaxBytesError. Zero means unlimited
+func LimitRequestSizeMiddleware(maxBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if maxBytes > 0 {
+				request.Body = http.MaxBytesReader(writer, request.Body, maxBytes)
+			}
+			next.ServeHTTP(writer, request)
+		})
+	}
+}
+
 func CompressingMiddleware() func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
@@ -0,0 +1,64 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"go.uber.org/zap"
+)
+
+// Create or update secrets in one transaction. Either all secrets are saved or none of them
+func (s *SecretService) UpsertSecrets(ctx context.Context, secrets []model.Secret) (model.SecretBatchResult, error) {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	if len(secrets) == 0 {
+		return model.SecretBatchResult{}, model.ErrSecretBatchIsNotValid
+	}
+
+	names := make([]string, 0, len(secrets))
+	seen := make(map[string]bool, len(secrets))
+	for i := range secrets {
+		secretName, err := model.CleanSecretName(secrets[i].Name)
+		if err != nil {
+			return model.SecretBatchResult{}, err
+		}
+		if seen[secretName] {
+			return model.SecretBatchResult{}, model.ErrSecretBatchIsNotValid
+		}
+		seen[secretName] = true
+		secrets[i].Name = secretName
+		names = append(names, secretName)
+
+		if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secretName), model.FolderWriteAction); err != nil {
+			return model.SecretBatchResult{}, err
+		}
+		if err := s.checkSecretSize(currentUserName, secrets[i]); err != nil {
+			return model.SecretBatchResult{}, err
+		}
+	}
+
+	existingSizes, err := s.repository.FindSecretSizes(ctx, currentUserName, names)
+	if err != nil {
+		s.logger.Error("Error during find secret sizes", zap.String("userName", currentUserName), zap.Error(err))
+		return model.SecretBatchResult{}, err
+	}
+
+	addedSecrets, addedSize := int64(0), int64(0)
+	for _, secret := range secrets {
+		existingSize, exists := existingSizes[secret.Name]
+		if !exists {
+			addedSecrets++
+		}
+		addedSize += secretSize(secret) - existingSize
+	}
+	if err := s.checkQuota(ctx, currentUserName, addedSecrets, addedSize); err != nil {
+		return model.SecretBatchResult{}, err
+	}
+
+	result, err := s.repository.UpsertSecrets(ctx, currentUserName, secrets)
+	if err != nil {
+		s.logger.Error("Error during upsert secrets", zap.String("userName", currentUserName), zap.Int("count", len(secrets)), zap.Error(err))
+		return model.SecretBatchResult{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,101 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"testing"
+)
+
+func TestSecretService_UpsertSecrets(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should upsert secrets with cleaned names", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		secrets := []model.Secret{{Name: "/prod/db", Content: []byte("1")}, {Name: "stage/db", Content: []byte("2")}}
+		expected := []model.Secret{{Name: "prod/db", Content: []byte("1")}, {Name: "stage/db", Content: []byte("2")}}
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"prod/db", "stage/db"}).Return(map[string]int64{}, nil)
+		mockRepo.On("UpsertSecrets", ctx, "testUser", expected).Return(model.SecretBatchResult{Created: 1, Updated: 1}, nil)
+
+		result, err := service.UpsertSecrets(ctx, secrets)
+		assert.NoError(t, err)
+		assert.Equal(t, model.SecretBatchResult{Created: 1, Updated: 1}, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if batch is empty", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		_, err := service.UpsertSecrets(ctx, nil)
+		assert.Equal(t, model.ErrSecretBatchIsNotValid, err)
+	})
+
+	t.Run("should return error if batch contains duplicate names", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		_, err := service.UpsertSecrets(ctx, []model.Secret{{Name: "prod/db"}, {Name: "/prod/db/"}})
+		assert.Equal(t, model.ErrSecretBatchIsNotValid, err)
+
+		mockRepo.AssertNotCalled(t, "UpsertSecrets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if secret name is not valid", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		_, err := service.UpsertSecrets(ctx, []model.Secret{{Name: "prod/../db"}})
+		assert.Equal(t, model.ErrSecretNameIsNotValid, err)
+	})
+
+	t.Run("should count only new secrets and size difference in quota", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecrets: 3, MaxTotalSize: 10})
+
+		secrets := []model.Secret{{Name: "a", Content: []byte("1234")}, {Name: "b", Content: []byte("12")}}
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"a", "b"}).Return(map[string]int64{"a": 6}, nil)
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 2, TotalSize: 10}, nil)
+		mockRepo.On("UpsertSecrets", ctx, "testUser", secrets).Return(model.SecretBatchResult{Created: 1, Updated: 1}, nil)
+
+		_, err := service.UpsertSecrets(ctx, secrets)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if quota is exceeded", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecrets: 3})
+
+		secrets := []model.Secret{{Name: "a", Content: []byte("1")}, {Name: "b", Content: []byte("2")}}
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"a", "b"}).Return(map[string]int64{}, nil)
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 2}, nil)
+
+		_, err := service.UpsertSecrets(ctx, secrets)
+		assert.Equal(t, model.ErrQuotaExceeded, err)
+
+		mockRepo.AssertNotCalled(t, "UpsertSecrets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		secrets := []model.Secret{{Name: "a", Content: []byte("1")}}
+		expectedError := errors.New("database error")
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"a"}).Return(map[string]int64{}, nil)
+		mockRepo.On("UpsertSecrets", ctx, "testUser", secrets).Return(model.SecretBatchResult{}, expectedError)
+
+		_, err := service.UpsertSecrets(ctx, secrets)
+		assert.Equal(t, expectedError, err)
+	})
+}
@@ -3,6 +3,7 @@ package secret
 import (
 	"context"
 	"github.com/desepticon55/gophkeeper/internal/model"
+	"time"
 )
 
 type secretRepository interface {
@@ -12,7 +13,31 @@ type secretRepository interface {
 
 	ExistSecret(ctx context.Context, userName string, secretName string) (bool, error)
 
-	FindAllSecrets(ctx context.Context, userName string) ([]model.Secret, error)
+	FindAllSecrets(ctx context.Context, userName string, filter model.SecretFilter) ([]model.Secret, error)
 
-	DeleteSecret(ctx context.Context, userName string, secretName string) error
+	TouchSecret(ctx context.Context, userName string, secretName string) error
+
+	BurnSecret(ctx context.Context, userName string, secretName string) (model.Secret, error)
+
+	TrashSecret(ctx context.Context, userName string, secretName string) error
+
+	FindTrash(ctx context.Context, userName string) ([]model.Secret, error)
+
+	RestoreSecret(ctx context.Context, userName string, secretName string) error
+
+	PurgeSecret(ctx context.Context, userName string, secretName string) error
+
+	PurgeTrashBefore(ctx context.Context, before time.Time) (int64, error)
+
+	RenameSecret(ctx context.Context, userName string, from string, to string) error
+
+	MoveFolder(ctx context.Context, userName string, from string, to string) (int64, error)
+
+	FindUsage(ctx context.Context, userName string) (model.Usage, error)
+
+	UpsertSecrets(ctx context.Context, userName string, secrets []model.Secret) (model.SecretBatchResult, error)
+
+	UpdateSecret(ctx context.Context, userName string, secret model.Secret) error
+
+	FindSecretSizes(ctx context.Context, userName string, secretNames []string) (map[string]int64, error)
 }
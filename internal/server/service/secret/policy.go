@@ -0,0 +1,35 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"go.uber.org/zap"
+)
+
+// Hook to check access of user to folder. Service allows everything when policy is not set
+type FolderPolicy interface {
+	CheckFolderAccess(ctx context.Context, userName string, folder string, action model.FolderAction) error
+}
+
+// Set policy to check access to folders
+func (s *SecretService) WithFolderPolicy(policy FolderPolicy) *SecretService {
+	s.policy = policy
+	return s
+}
+
+func (s *SecretService) checkFolderAccess(ctx context.Context, userName string, folder string, action model.FolderAction) error {
+	if s.policy == nil {
+		return nil
+	}
+
+	if err := s.policy.CheckFolderAccess(ctx, userName, folder, action); err != nil {
+		s.logger.Warn("Access to folder is denied",
+			zap.String("folder", folder),
+			zap.String("action", string(action)),
+			zap.String("userName", userName),
+			zap.Error(err))
+		return fmt.Errorf("%w: %s", model.ErrFolderAccessDenied, err.Error())
+	}
+	return nil
+}
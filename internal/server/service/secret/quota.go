@@ -0,0 +1,74 @@
+package secret
+
+import (
+	"context"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"go.uber.org/zap"
+)
+
+// Per-user storage limits. Zero limit means unlimited
+type Quota struct {
+	MaxSecrets    int64
+	MaxSecretSize int64
+	MaxTotalSize  int64
+}
+
+// Set per-user storage limits
+func (s *SecretService) WithQuota(quota Quota) *SecretService {
+	s.quota = quota
+	return s
+}
+
+// Find storage usage of current user with configured limits
+func (s *SecretService) FindUsage(ctx context.Context) (model.Usage, error) {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	usage, err := s.repository.FindUsage(ctx, currentUserName)
+	if err != nil {
+		s.logger.Error("Error during find usage", zap.String("userName", currentUserName), zap.Error(err))
+		return model.Usage{}, err
+	}
+
+	usage.MaxSecrets = s.quota.MaxSecrets
+	usage.MaxSecretSize = s.quota.MaxSecretSize
+	usage.MaxTotalSize = s.quota.MaxTotalSize
+	return usage, nil
+}
+
+// Check size of one secret
+func (s *SecretService) checkSecretSize(userName string, secret model.Secret) error {
+	if size := secretSize(secret); s.quota.MaxSecretSize > 0 && size > s.quota.MaxSecretSize {
+		s.logger.Warn("Secret is too large", zap.String("name", secret.Name), zap.String("userName", userName), zap.Int64("size", size))
+		return model.ErrSecretTooLarge
+	}
+	return nil
+}
+
+// Check that user can store more secrets. Concurrent requests of the same user can slightly exceed the quota
+func (s *SecretService) checkQuota(ctx context.Context, userName string, addedSecrets int64, addedSize int64) error {
+	if s.quota.MaxSecrets == 0 && s.quota.MaxTotalSize == 0 {
+		return nil
+	}
+
+	usage, err := s.repository.FindUsage(ctx, userName)
+	if err != nil {
+		s.logger.Error("Error during find usage", zap.String("userName", userName), zap.Error(err))
+		return err
+	}
+
+	if s.quota.MaxSecrets > 0 && addedSecrets > 0 && usage.Secrets+usage.TrashSecrets+addedSecrets > s.quota.MaxSecrets {
+		s.logger.Warn("Secrets count quota is exceeded", zap.String("userName", userName))
+		return model.ErrQuotaExceeded
+	}
+	if s.quota.MaxTotalSize > 0 && addedSize > 0 && usage.TotalSize+addedSize > s.quota.MaxTotalSize {
+		s.logger.Warn("Total size quota is exceeded", zap.String("userName", userName))
+		return model.ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Size of encrypted secret data stored in database
+func secretSize(secret model.Secret) int64 {
+	return int64(len(secret.Content) + len(secret.Notes))
+}
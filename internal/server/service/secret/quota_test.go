@@ -0,0 +1,102 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zaptest"
+	"testing"
+)
+
+func TestSecretService_CreateSecretWithQuota(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+	secret := model.Secret{Name: "testSecret", Type: model.TextSecretType, Content: []byte("content"), Notes: []byte("notes")}
+
+	t.Run("should create secret within quota", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecrets: 10, MaxSecretSize: 100, MaxTotalSize: 1000})
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(false, nil)
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 8, TrashSecrets: 1, TotalSize: 988}, nil)
+		mockRepo.On("CreateSecret", ctx, "testUser", secret).Return(nil)
+
+		err := service.CreateSecret(ctx, secret)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret is too large", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecretSize: 11})
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(false, nil)
+
+		err := service.CreateSecret(ctx, secret)
+		assert.Equal(t, model.ErrSecretTooLarge, err)
+
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateSecret", ctx, "testUser", secret)
+	})
+
+	t.Run("should return error if secrets count quota is exceeded", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecrets: 10})
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(false, nil)
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 9, TrashSecrets: 1}, nil)
+
+		err := service.CreateSecret(ctx, secret)
+		assert.Equal(t, model.ErrQuotaExceeded, err)
+
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "CreateSecret", ctx, "testUser", secret)
+	})
+
+	t.Run("should return error if total size quota is exceeded", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxTotalSize: 1000})
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(false, nil)
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 1, TotalSize: 989}, nil)
+
+		err := service.CreateSecret(ctx, secret)
+		assert.Equal(t, model.ErrQuotaExceeded, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_FindUsage(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should return usage with limits", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithQuota(Quota{MaxSecrets: 10, MaxSecretSize: 100, MaxTotalSize: 1000})
+
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{Secrets: 2, TrashSecrets: 1, TotalSize: 300}, nil)
+
+		usage, err := service.FindUsage(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, model.Usage{Secrets: 2, TrashSecrets: 1, TotalSize: 300, MaxSecrets: 10, MaxSecretSize: 100, MaxTotalSize: 1000}, usage)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo)
+
+		expectedError := errors.New("database error")
+		mockRepo.On("FindUsage", ctx, "testUser").Return(model.Usage{}, expectedError)
+
+		_, err := service.FindUsage(ctx)
+		assert.Equal(t, expectedError, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
@@ -8,11 +8,20 @@ import (
 	"github.com/desepticon55/gophkeeper/internal/server"
 	"github.com/jackc/pgx/v4"
 	"go.uber.org/zap"
+	"sort"
+	"strings"
+)
+
+const (
+	defaultSecretsPageSize = 100
+	maxSecretsPageSize     = 1000
 )
 
 type SecretService struct {
 	logger     *zap.Logger
 	repository secretRepository
+	policy     FolderPolicy
+	quota      Quota
 }
 
 func NewSecretService(l *zap.Logger, r secretRepository) *SecretService {
@@ -21,8 +30,14 @@ func NewSecretService(l *zap.Logger, r secretRepository) *SecretService {
 
 func (s *SecretService) CreateSecret(ctx context.Context, secret model.Secret) error {
 	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
-	if secret.Name == "" {
-		return model.ErrSecretNameIsEmpty
+	secretName, err := model.CleanSecretName(secret.Name)
+	if err != nil {
+		return err
+	}
+	secret.Name = secretName
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secret.Name), model.FolderWriteAction); err != nil {
+		return err
 	}
 
 	existSecret, err := s.repository.ExistSecret(ctx, currentUserName, secret.Name)
@@ -35,6 +50,13 @@ func (s *SecretService) CreateSecret(ctx context.Context, secret model.Secret) e
 		return model.ErrSecretExistToCurrentUser
 	}
 
+	if err := s.checkSecretSize(currentUserName, secret); err != nil {
+		return err
+	}
+	if err := s.checkQuota(ctx, currentUserName, 1, secretSize(secret)); err != nil {
+		return err
+	}
+
 	err = s.repository.CreateSecret(ctx, currentUserName, secret)
 	if err != nil {
 		s.logger.Error("Error during create secret", zap.String("name", secret.Name), zap.String("userName", currentUserName), zap.Error(err))
@@ -46,6 +68,15 @@ func (s *SecretService) CreateSecret(ctx context.Context, secret model.Secret) e
 
 func (s *SecretService) FindSecret(ctx context.Context, secretName string) (model.Secret, error) {
 	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	secretName, err := model.CleanSecretName(secretName)
+	if err != nil {
+		return model.Secret{}, err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secretName), model.FolderReadAction); err != nil {
+		return model.Secret{}, err
+	}
+
 	secret, err := s.repository.FindSecret(ctx, currentUserName, secretName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -56,22 +87,103 @@ func (s *SecretService) FindSecret(ctx context.Context, secretName string) (mode
 		s.logger.Error("Error during find secret", zap.String("name", secretName), zap.String("userName", currentUserName), zap.Error(err))
 		return model.Secret{}, err
 	}
+
+	if secret.BurnAfterReading {
+		return s.burnSecret(ctx, currentUserName, secretName)
+	}
+
+	if err := s.repository.TouchSecret(ctx, currentUserName, secretName); err != nil {
+		s.logger.Warn("Error during update secret access time", zap.String("name", secretName), zap.String("userName", currentUserName), zap.Error(err))
+	}
 	return secret, nil
 }
 
-func (s *SecretService) FindAllSecrets(ctx context.Context) ([]model.Secret, error) {
+func (s *SecretService) FindAllSecrets(ctx context.Context, filter model.SecretFilter) (model.SecretPage, error) {
 	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
-	secrets, err := s.repository.FindAllSecrets(ctx, currentUserName)
-	if errors.Is(err, pgx.ErrNoRows) {
-		s.logger.Warn("Secrets was not found", zap.String("userName", currentUserName))
-		return nil, model.ErrSecretsWasNotFound
+	if filter.Limit < 0 {
+		return model.SecretPage{}, model.ErrSecretFilterIsNotValid
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultSecretsPageSize
+	}
+	if filter.Limit > maxSecretsPageSize {
+		filter.Limit = maxSecretsPageSize
+	}
+
+	folder, err := model.CleanFolderPath(filter.Folder)
+	if err != nil {
+		return model.SecretPage{}, model.ErrSecretFilterIsNotValid
+	}
+	filter.Folder = folder
+
+	// request one extra secret to know whether the next page exists
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+	secrets, err := s.repository.FindAllSecrets(ctx, currentUserName, filter)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secrets was not found", zap.String("userName", currentUserName))
+			return model.SecretPage{}, model.ErrSecretsWasNotFound
+		}
+
+		s.logger.Error("Error during find secrets", zap.String("userName", currentUserName), zap.Error(err))
+		return model.SecretPage{}, err
+	}
+
+	page := model.SecretPage{}
+	if len(secrets) > pageSize {
+		secrets = secrets[:pageSize]
+		page.NextCursor = secrets[pageSize-1].Name
 	}
-	return secrets, nil
+
+	// secrets from folders denied by policy are skipped, page can be smaller than requested
+	for _, secret := range secrets {
+		if s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secret.Name), model.FolderReadAction) == nil {
+			page.Secrets = append(page.Secrets, hideOneTimeContent(secret))
+		}
+	}
+	return page, nil
 }
 
+// Delete one-time secret, the secret is returned only if it was not burned by a concurrent read
+func (s *SecretService) burnSecret(ctx context.Context, userName string, secretName string) (model.Secret, error) {
+	secret, err := s.repository.BurnSecret(ctx, userName, secretName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secret was already burned", zap.String("name", secretName), zap.String("userName", userName))
+			return model.Secret{}, model.ErrSecretWasNotFound
+		}
+
+		s.logger.Error("Error during burn secret", zap.String("name", secretName), zap.String("userName", userName), zap.Error(err))
+		return model.Secret{}, err
+	}
+
+	s.logger.Info("Secret was burned after reading", zap.String("name", secretName), zap.String("userName", userName))
+	return secret, nil
+}
+
+// Content of one-time secret is returned on read by name only
+func hideOneTimeContent(secret model.Secret) model.Secret {
+	if secret.BurnAfterReading {
+		secret.Content = nil
+		secret.Notes = nil
+	}
+	return secret
+}
+
+// Move secret to trash, it can be restored until trash retention is expired
 func (s *SecretService) DeleteSecret(ctx context.Context, secretName string) error {
 	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
-	err := s.repository.DeleteSecret(ctx, currentUserName, secretName)
+	secretName, err := model.CleanSecretName(secretName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secretName), model.FolderWriteAction); err != nil {
+		return err
+	}
+
+	err = s.repository.TrashSecret(ctx, currentUserName, secretName)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			s.logger.Warn("Secret was not found", zap.String("name", secretName), zap.String("userName", currentUserName))
@@ -81,3 +193,177 @@ func (s *SecretService) DeleteSecret(ctx context.Context, secretName string) err
 	}
 	return nil
 }
+
+// Rename secret, target name should not be used by another secret
+func (s *SecretService) RenameSecret(ctx context.Context, from string, to string) error {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	from, err := model.CleanSecretName(from)
+	if err != nil {
+		return err
+	}
+	to, err = model.CleanSecretName(to)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(from), model.FolderWriteAction); err != nil {
+		return err
+	}
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(to), model.FolderWriteAction); err != nil {
+		return err
+	}
+
+	if from == to {
+		return nil
+	}
+
+	err = s.repository.RenameSecret(ctx, currentUserName, from, to)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secret was not found", zap.String("name", from), zap.String("userName", currentUserName))
+			return model.ErrSecretWasNotFound
+		}
+		if errors.Is(err, model.ErrSecretExistToCurrentUser) {
+			s.logger.Warn("Secret already exists", zap.String("name", to), zap.String("userName", currentUserName))
+			return err
+		}
+
+		s.logger.Error("Error during rename secret", zap.String("from", from), zap.String("to", to), zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Update secret read with given version, secret changed by another request is not overwritten
+func (s *SecretService) UpdateSecret(ctx context.Context, secret model.Secret) error {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	secretName, err := model.CleanSecretName(secret.Name)
+	if err != nil {
+		return err
+	}
+	secret.Name = secretName
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secret.Name), model.FolderWriteAction); err != nil {
+		return err
+	}
+	if err := s.checkSecretSize(currentUserName, secret); err != nil {
+		return err
+	}
+
+	existingSizes, err := s.repository.FindSecretSizes(ctx, currentUserName, []string{secret.Name})
+	if err != nil {
+		s.logger.Error("Error during find secret sizes", zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+	existingSize, exists := existingSizes[secret.Name]
+	if !exists {
+		return model.ErrSecretWasNotFound
+	}
+	if err := s.checkQuota(ctx, currentUserName, 0, secretSize(secret)-existingSize); err != nil {
+		return err
+	}
+
+	err = s.repository.UpdateSecret(ctx, currentUserName, secret)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secret was not found", zap.String("name", secret.Name), zap.String("userName", currentUserName))
+			return model.ErrSecretWasNotFound
+		}
+		if errors.Is(err, model.ErrSecretVersionConflict) {
+			s.logger.Warn("Secret was changed by another request", zap.String("name", secret.Name), zap.String("userName", currentUserName))
+			return err
+		}
+
+		s.logger.Error("Error during update secret", zap.String("name", secret.Name), zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Read direct subfolders and secrets of folder. Empty path means root folder
+func (s *SecretService) ListFolder(ctx context.Context, path string) (model.Folder, error) {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	path, err := model.CleanFolderPath(path)
+	if err != nil {
+		return model.Folder{}, err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, path, model.FolderReadAction); err != nil {
+		return model.Folder{}, err
+	}
+
+	folder := model.Folder{Path: path, Folders: []string{}, Secrets: []model.Secret{}}
+	secrets, err := s.repository.FindAllSecrets(ctx, currentUserName, model.SecretFilter{Folder: path})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if path == "" {
+				return folder, nil
+			}
+			s.logger.Warn("Folder was not found", zap.String("path", path), zap.String("userName", currentUserName))
+			return model.Folder{}, model.ErrFolderWasNotFound
+		}
+
+		s.logger.Error("Error during list folder", zap.String("path", path), zap.String("userName", currentUserName), zap.Error(err))
+		return model.Folder{}, err
+	}
+
+	prefix := ""
+	if path != "" {
+		prefix = path + model.SecretPathSeparator
+	}
+	subfolders := make(map[string]bool)
+	for _, secret := range secrets {
+		relativeName := strings.TrimPrefix(secret.Name, prefix)
+		if index := strings.Index(relativeName, model.SecretPathSeparator); index >= 0 {
+			subfolders[relativeName[:index]] = true
+			continue
+		}
+		folder.Secrets = append(folder.Secrets, hideOneTimeContent(secret))
+	}
+
+	for subfolder := range subfolders {
+		folder.Folders = append(folder.Folders, subfolder)
+	}
+	sort.Strings(folder.Folders)
+	return folder, nil
+}
+
+// Move folder with all nested secrets to another path
+func (s *SecretService) MoveFolder(ctx context.Context, from string, to string) error {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	from, err := model.CleanFolderPath(from)
+	if err != nil {
+		return err
+	}
+	to, err = model.CleanFolderPath(to)
+	if err != nil {
+		return err
+	}
+
+	if from == "" || to == "" || from == to || strings.HasPrefix(to, from+model.SecretPathSeparator) {
+		return model.ErrFolderMoveIsNotValid
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, from, model.FolderWriteAction); err != nil {
+		return err
+	}
+	if err := s.checkFolderAccess(ctx, currentUserName, to, model.FolderWriteAction); err != nil {
+		return err
+	}
+
+	moved, err := s.repository.MoveFolder(ctx, currentUserName, from, to)
+	if err != nil {
+		if errors.Is(err, model.ErrSecretExistToCurrentUser) {
+			s.logger.Warn("Secret already exists in target folder", zap.String("from", from), zap.String("to", to), zap.String("userName", currentUserName))
+			return err
+		}
+
+		s.logger.Error("Error during move folder", zap.String("from", from), zap.String("to", to), zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+
+	if moved == 0 {
+		return model.ErrFolderWasNotFound
+	}
+	return nil
+}
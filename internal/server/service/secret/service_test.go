@@ -1 +1,721 @@
 package secret
+
+import (
+	"context"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"testing"
+	"time"
+)
+
+type MockSecretRepository struct {
+	mock.Mock
+}
+
+func (m *MockSecretRepository) CreateSecret(ctx context.Context, userName string, secret model.Secret) error {
+	args := m.Called(ctx, userName, secret)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) FindSecret(ctx context.Context, userName string, secretName string) (model.Secret, error) {
+	args := m.Called(ctx, userName, secretName)
+	return args.Get(0).(model.Secret), args.Error(1)
+}
+
+func (m *MockSecretRepository) ExistSecret(ctx context.Context, userName string, secretName string) (bool, error) {
+	args := m.Called(ctx, userName, secretName)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSecretRepository) FindAllSecrets(ctx context.Context, userName string, filter model.SecretFilter) ([]model.Secret, error) {
+	args := m.Called(ctx, userName, filter)
+	return args.Get(0).([]model.Secret), args.Error(1)
+}
+
+func (m *MockSecretRepository) UpsertSecrets(ctx context.Context, userName string, secrets []model.Secret) (model.SecretBatchResult, error) {
+	args := m.Called(ctx, userName, secrets)
+	return args.Get(0).(model.SecretBatchResult), args.Error(1)
+}
+
+func (m *MockSecretRepository) UpdateSecret(ctx context.Context, userName string, secret model.Secret) error {
+	args := m.Called(ctx, userName, secret)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) FindSecretSizes(ctx context.Context, userName string, secretNames []string) (map[string]int64, error) {
+	args := m.Called(ctx, userName, secretNames)
+	return args.Get(0).(map[string]int64), args.Error(1)
+}
+
+func (m *MockSecretRepository) FindUsage(ctx context.Context, userName string) (model.Usage, error) {
+	args := m.Called(ctx, userName)
+	return args.Get(0).(model.Usage), args.Error(1)
+}
+
+func (m *MockSecretRepository) BurnSecret(ctx context.Context, userName string, secretName string) (model.Secret, error) {
+	args := m.Called(ctx, userName, secretName)
+	return args.Get(0).(model.Secret), args.Error(1)
+}
+
+func (m *MockSecretRepository) TouchSecret(ctx context.Context, userName string, secretName string) error {
+	args := m.Called(ctx, userName, secretName)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) TrashSecret(ctx context.Context, userName string, secretName string) error {
+	args := m.Called(ctx, userName, secretName)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) FindTrash(ctx context.Context, userName string) ([]model.Secret, error) {
+	args := m.Called(ctx, userName)
+	return args.Get(0).([]model.Secret), args.Error(1)
+}
+
+func (m *MockSecretRepository) RestoreSecret(ctx context.Context, userName string, secretName string) error {
+	args := m.Called(ctx, userName, secretName)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) PurgeSecret(ctx context.Context, userName string, secretName string) error {
+	args := m.Called(ctx, userName, secretName)
+	return args.Error(0)
+}
+
+func (m *MockSecretRepository) PurgeTrashBefore(ctx context.Context, before time.Time) (int64, error) {
+	args := m.Called(ctx, before)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSecretRepository) MoveFolder(ctx context.Context, userName string, from string, to string) (int64, error) {
+	args := m.Called(ctx, userName, from, to)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockSecretRepository) RenameSecret(ctx context.Context, userName string, from string, to string) error {
+	args := m.Called(ctx, userName, from, to)
+	return args.Error(0)
+}
+
+type denyFolderPolicy struct {
+	folder string
+}
+
+func (p denyFolderPolicy) CheckFolderAccess(ctx context.Context, userName string, folder string, action model.FolderAction) error {
+	if folder == p.folder {
+		return errors.New("folder is private")
+	}
+	return nil
+}
+
+func TestSecretService_CreateSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should successfully create secret with metadata", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secret := model.Secret{
+			Name:    "testSecret",
+			Type:    model.CredentialsSecretType,
+			Content: []byte("content"),
+			Notes:   []byte("notes"),
+			Tags:    []string{"work"},
+			URL:     "https://example.com",
+		}
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(false, nil)
+		mockRepo.On("CreateSecret", ctx, "testUser", secret).Return(nil)
+
+		err := service.CreateSecret(ctx, secret)
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret name is empty", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		err := service.CreateSecret(ctx, model.Secret{})
+		assert.Equal(t, model.ErrSecretNameIsEmpty, err)
+
+		mockRepo.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should normalize path-style secret name", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "prod/db/postgres").Return(false, nil)
+		mockRepo.On("CreateSecret", ctx, "testUser", model.Secret{Name: "prod/db/postgres"}).Return(nil)
+
+		err := service.CreateSecret(ctx, model.Secret{Name: "/prod/db/postgres/"})
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret name is not valid", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		err := service.CreateSecret(ctx, model.Secret{Name: "prod/../postgres"})
+		assert.Equal(t, model.ErrSecretNameIsNotValid, err)
+
+		mockRepo.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if folder access is denied", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithFolderPolicy(denyFolderPolicy{folder: "prod/db"})
+
+		err := service.CreateSecret(ctx, model.Secret{Name: "prod/db/postgres"})
+		assert.ErrorIs(t, err, model.ErrFolderAccessDenied)
+
+		mockRepo.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if secret exist", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("ExistSecret", ctx, "testUser", "testSecret").Return(true, nil)
+
+		err := service.CreateSecret(ctx, model.Secret{Name: "testSecret"})
+		assert.Equal(t, model.ErrSecretExistToCurrentUser, err)
+
+		mockRepo.AssertNotCalled(t, "CreateSecret", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_FindSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should return secret and update access time", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secret := model.Secret{Name: "testSecret", Username: "testUser"}
+		mockRepo.On("FindSecret", ctx, "testUser", "testSecret").Return(secret, nil)
+		mockRepo.On("TouchSecret", ctx, "testUser", "testSecret").Return(nil)
+
+		result, err := service.FindSecret(ctx, "testSecret")
+		assert.NoError(t, err)
+		assert.Equal(t, secret, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should burn one-time secret after reading", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secret := model.Secret{Name: "testSecret", Username: "testUser", Content: []byte("1"), BurnAfterReading: true}
+		mockRepo.On("FindSecret", ctx, "testUser", "testSecret").Return(secret, nil)
+		mockRepo.On("BurnSecret", ctx, "testUser", "testSecret").Return(secret, nil)
+
+		result, err := service.FindSecret(ctx, "testSecret")
+		assert.NoError(t, err)
+		assert.Equal(t, secret, result)
+
+		mockRepo.AssertExpectations(t)
+		mockRepo.AssertNotCalled(t, "TouchSecret", ctx, "testUser", "testSecret")
+	})
+
+	t.Run("should return error if one-time secret was burned by another read", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secret := model.Secret{Name: "testSecret", Username: "testUser", Content: []byte("1"), BurnAfterReading: true}
+		mockRepo.On("FindSecret", ctx, "testUser", "testSecret").Return(secret, nil)
+		mockRepo.On("BurnSecret", ctx, "testUser", "testSecret").Return(model.Secret{}, pgx.ErrNoRows)
+
+		_, err := service.FindSecret(ctx, "testSecret")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return secret if access time was not updated", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secret := model.Secret{Name: "testSecret", Username: "testUser"}
+		mockRepo.On("FindSecret", ctx, "testUser", "testSecret").Return(secret, nil)
+		mockRepo.On("TouchSecret", ctx, "testUser", "testSecret").Return(errors.New("database error"))
+
+		result, err := service.FindSecret(ctx, "testSecret")
+		assert.NoError(t, err)
+		assert.Equal(t, secret, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindSecret", ctx, "testUser", "testSecret").Return(model.Secret{}, pgx.ErrNoRows)
+
+		_, err := service.FindSecret(ctx, "testSecret")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertNotCalled(t, "TouchSecret", mock.Anything, mock.Anything, mock.Anything)
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_FindAllSecrets(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should return page with next cursor", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secrets := []model.Secret{{Name: "a"}, {Name: "b"}, {Name: "c"}}
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Tag: "work", Limit: 3}).Return(secrets, nil)
+
+		page, err := service.FindAllSecrets(ctx, model.SecretFilter{Tag: "work", Limit: 2})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Secret{{Name: "a"}, {Name: "b"}}, page.Secrets)
+		assert.Equal(t, "b", page.NextCursor)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should hide content of one-time secrets", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secrets := []model.Secret{{Name: "a", Content: []byte("1")}, {Name: "b", Content: []byte("2"), Notes: []byte("3"), BurnAfterReading: true}}
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Limit: defaultSecretsPageSize + 1}).Return(secrets, nil)
+
+		page, err := service.FindAllSecrets(ctx, model.SecretFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Secret{{Name: "a", Content: []byte("1")}, {Name: "b", BurnAfterReading: true}}, page.Secrets)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return last page without cursor", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secrets := []model.Secret{{Name: "a"}}
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Limit: defaultSecretsPageSize + 1}).Return(secrets, nil)
+
+		page, err := service.FindAllSecrets(ctx, model.SecretFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, secrets, page.Secrets)
+		assert.Empty(t, page.NextCursor)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should limit page size", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Limit: maxSecretsPageSize + 1}).Return([]model.Secret{{Name: "a"}}, nil)
+
+		_, err := service.FindAllSecrets(ctx, model.SecretFilter{Limit: maxSecretsPageSize * 2})
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if limit is negative", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		_, err := service.FindAllSecrets(ctx, model.SecretFilter{Limit: -1})
+		assert.Equal(t, model.ErrSecretFilterIsNotValid, err)
+
+		mockRepo.AssertNotCalled(t, "FindAllSecrets", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if secrets were not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindAllSecrets", ctx, "testUser", mock.Anything).Return([]model.Secret(nil), pgx.ErrNoRows)
+
+		_, err := service.FindAllSecrets(ctx, model.SecretFilter{})
+		assert.Equal(t, model.ErrSecretsWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		expectedError := errors.New("database error")
+		mockRepo.On("FindAllSecrets", ctx, "testUser", mock.Anything).Return([]model.Secret(nil), expectedError)
+
+		_, err := service.FindAllSecrets(ctx, model.SecretFilter{})
+		assert.Equal(t, expectedError, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_ListFolder(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should return subfolders and secrets of folder", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secrets := []model.Secret{
+			{Name: "prod/api-key"},
+			{Name: "prod/db/mysql"},
+			{Name: "prod/db/postgres"},
+			{Name: "prod/cache/redis"},
+		}
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Folder: "prod"}).Return(secrets, nil)
+
+		folder, err := service.ListFolder(ctx, "/prod/")
+		assert.NoError(t, err)
+		assert.Equal(t, "prod", folder.Path)
+		assert.Equal(t, []string{"cache", "db"}, folder.Folders)
+		assert.Equal(t, []model.Secret{{Name: "prod/api-key"}}, folder.Secrets)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return empty root folder", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{}).Return([]model.Secret(nil), pgx.ErrNoRows)
+
+		folder, err := service.ListFolder(ctx, "")
+		assert.NoError(t, err)
+		assert.Empty(t, folder.Folders)
+		assert.Empty(t, folder.Secrets)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if folder was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindAllSecrets", ctx, "testUser", model.SecretFilter{Folder: "stage"}).Return([]model.Secret(nil), pgx.ErrNoRows)
+
+		_, err := service.ListFolder(ctx, "stage")
+		assert.Equal(t, model.ErrFolderWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_MoveFolder(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should move folder", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("MoveFolder", ctx, "testUser", "prod/db", "stage/db").Return(int64(2), nil)
+
+		err := service.MoveFolder(ctx, "prod/db/", "/stage/db")
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if folder is moved into itself", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		for _, to := range []string{"prod", "prod/db", ""} {
+			err := service.MoveFolder(ctx, "prod", to)
+			assert.Equal(t, model.ErrFolderMoveIsNotValid, err)
+		}
+
+		mockRepo.AssertNotCalled(t, "MoveFolder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if folder was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("MoveFolder", ctx, "testUser", "prod", "stage").Return(int64(0), nil)
+
+		err := service.MoveFolder(ctx, "prod", "stage")
+		assert.Equal(t, model.ErrFolderWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret exists in target folder", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("MoveFolder", ctx, "testUser", "prod", "stage").Return(int64(0), model.ErrSecretExistToCurrentUser)
+
+		err := service.MoveFolder(ctx, "prod", "stage")
+		assert.Equal(t, model.ErrSecretExistToCurrentUser, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if access to target folder is denied", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithFolderPolicy(denyFolderPolicy{folder: "stage"})
+
+		err := service.MoveFolder(ctx, "prod", "stage")
+		assert.ErrorIs(t, err, model.ErrFolderAccessDenied)
+
+		mockRepo.AssertNotCalled(t, "MoveFolder", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSecretService_RenameSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should rename secret", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RenameSecret", ctx, "testUser", "prod/db", "stage/db").Return(nil)
+
+		err := service.RenameSecret(ctx, "/prod/db", "stage/db/")
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should do nothing if names are equal", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		err := service.RenameSecret(ctx, "prod/db", "prod/db")
+		assert.NoError(t, err)
+
+		mockRepo.AssertNotCalled(t, "RenameSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should return error if secret was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RenameSecret", ctx, "testUser", "prod/db", "stage/db").Return(pgx.ErrNoRows)
+
+		err := service.RenameSecret(ctx, "prod/db", "stage/db")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if target secret exists", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RenameSecret", ctx, "testUser", "prod/db", "stage/db").Return(model.ErrSecretExistToCurrentUser)
+
+		err := service.RenameSecret(ctx, "prod/db", "stage/db")
+		assert.Equal(t, model.ErrSecretExistToCurrentUser, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if target name is empty", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		err := service.RenameSecret(ctx, "prod/db", "")
+		assert.Equal(t, model.ErrSecretNameIsEmpty, err)
+
+		mockRepo.AssertNotCalled(t, "RenameSecret", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	})
+}
+
+func TestSecretService_DeleteSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should move secret to trash", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("TrashSecret", ctx, "testUser", "prod/db").Return(nil)
+
+		err := service.DeleteSecret(ctx, "prod/db")
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("TrashSecret", ctx, "testUser", "prod/db").Return(pgx.ErrNoRows)
+
+		err := service.DeleteSecret(ctx, "prod/db")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_UpdateSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+	secret := model.Secret{Name: "otp/github", Type: model.OTPSecretType, Content: []byte("new"), Version: 3}
+
+	t.Run("should update secret", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"otp/github"}).Return(map[string]int64{"otp/github": 3}, nil)
+		mockRepo.On("UpdateSecret", ctx, "testUser", secret).Return(nil)
+
+		err := service.UpdateSecret(ctx, model.Secret{Name: "/otp/github/", Type: model.OTPSecretType, Content: []byte("new"), Version: 3})
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was changed", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"otp/github"}).Return(map[string]int64{"otp/github": 3}, nil)
+		mockRepo.On("UpdateSecret", ctx, "testUser", secret).Return(model.ErrSecretVersionConflict)
+
+		err := service.UpdateSecret(ctx, secret)
+		assert.ErrorIs(t, err, model.ErrSecretVersionConflict)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was not found", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindSecretSizes", ctx, "testUser", []string{"otp/github"}).Return(map[string]int64{}, nil)
+
+		err := service.UpdateSecret(ctx, secret)
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertNotCalled(t, "UpdateSecret", mock.Anything, mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,101 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/jackc/pgx/v4"
+	"go.uber.org/zap"
+	"time"
+)
+
+// Find secrets of current user in trash
+func (s *SecretService) FindTrash(ctx context.Context) ([]model.Secret, error) {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	secrets, err := s.repository.FindTrash(ctx, currentUserName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, model.ErrTrashIsEmpty
+		}
+
+		s.logger.Error("Error during find trash", zap.String("userName", currentUserName), zap.Error(err))
+		return nil, err
+	}
+
+	var result []model.Secret
+	for _, secret := range secrets {
+		if s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secret.Name), model.FolderReadAction) == nil {
+			result = append(result, hideOneTimeContent(secret))
+		}
+	}
+	if len(result) == 0 {
+		return nil, model.ErrTrashIsEmpty
+	}
+	return result, nil
+}
+
+// Restore the most recently deleted secret with name from trash
+func (s *SecretService) RestoreSecret(ctx context.Context, secretName string) error {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	secretName, err := model.CleanSecretName(secretName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secretName), model.FolderWriteAction); err != nil {
+		return err
+	}
+
+	err = s.repository.RestoreSecret(ctx, currentUserName, secretName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secret was not found in trash", zap.String("name", secretName), zap.String("userName", currentUserName))
+			return model.ErrSecretWasNotFound
+		}
+		if errors.Is(err, model.ErrSecretExistToCurrentUser) {
+			s.logger.Warn("Secret already exists", zap.String("name", secretName), zap.String("userName", currentUserName))
+			return err
+		}
+
+		s.logger.Error("Error during restore secret", zap.String("name", secretName), zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Permanently delete secret with name from trash
+func (s *SecretService) PurgeSecret(ctx context.Context, secretName string) error {
+	currentUserName := fmt.Sprintf("%v", ctx.Value(server.UserNameContextKey))
+	secretName, err := model.CleanSecretName(secretName)
+	if err != nil {
+		return err
+	}
+
+	if err := s.checkFolderAccess(ctx, currentUserName, model.SecretFolder(secretName), model.FolderWriteAction); err != nil {
+		return err
+	}
+
+	err = s.repository.PurgeSecret(ctx, currentUserName, secretName)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			s.logger.Warn("Secret was not found in trash", zap.String("name", secretName), zap.String("userName", currentUserName))
+			return model.ErrSecretWasNotFound
+		}
+
+		s.logger.Error("Error during purge secret", zap.String("name", secretName), zap.String("userName", currentUserName), zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// Permanently delete secrets of all users kept in trash longer than retention
+func (s *SecretService) PurgeExpiredTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	purged, err := s.repository.PurgeTrashBefore(ctx, time.Now().Add(-retention))
+	if err != nil {
+		s.logger.Error("Error during purge expired trash", zap.Duration("retention", retention), zap.Error(err))
+		return 0, err
+	}
+	return purged, nil
+}
@@ -0,0 +1,188 @@
+package secret
+
+import (
+	"context"
+	"errors"
+	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/desepticon55/gophkeeper/internal/server"
+	"github.com/jackc/pgx/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap/zaptest"
+	"testing"
+	"time"
+)
+
+func TestSecretService_FindTrash(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should return secrets in trash", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		secrets := []model.Secret{{Name: "prod/db"}, {Name: "stage/db"}}
+		mockRepo.On("FindTrash", ctx, "testUser").Return(secrets, nil)
+
+		result, err := service.FindTrash(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, secrets, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should skip secrets from denied folders", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := NewSecretService(logger, mockRepo).WithFolderPolicy(denyFolderPolicy{folder: "prod"})
+
+		mockRepo.On("FindTrash", ctx, "testUser").Return([]model.Secret{{Name: "prod/db"}, {Name: "stage/db"}}, nil)
+
+		result, err := service.FindTrash(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, []model.Secret{{Name: "stage/db"}}, result)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if trash is empty", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("FindTrash", ctx, "testUser").Return([]model.Secret(nil), pgx.ErrNoRows)
+
+		_, err := service.FindTrash(ctx)
+		assert.Equal(t, model.ErrTrashIsEmpty, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_RestoreSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should restore secret", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RestoreSecret", ctx, "testUser", "prod/db").Return(nil)
+
+		err := service.RestoreSecret(ctx, "/prod/db")
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was not found in trash", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RestoreSecret", ctx, "testUser", "prod/db").Return(pgx.ErrNoRows)
+
+		err := service.RestoreSecret(ctx, "prod/db")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret with the same name exists", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("RestoreSecret", ctx, "testUser", "prod/db").Return(model.ErrSecretExistToCurrentUser)
+
+		err := service.RestoreSecret(ctx, "prod/db")
+		assert.Equal(t, model.ErrSecretExistToCurrentUser, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_PurgeSecret(t *testing.T) {
+	ctx := context.WithValue(context.Background(), server.UserNameContextKey, "testUser")
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should purge secret", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("PurgeSecret", ctx, "testUser", "prod/db").Return(nil)
+
+		err := service.PurgeSecret(ctx, "prod/db")
+		assert.NoError(t, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return error if secret was not found in trash", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("PurgeSecret", ctx, "testUser", "prod/db").Return(pgx.ErrNoRows)
+
+		err := service.PurgeSecret(ctx, "prod/db")
+		assert.Equal(t, model.ErrSecretWasNotFound, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
+
+func TestSecretService_PurgeExpiredTrash(t *testing.T) {
+	ctx := context.Background()
+	logger := zaptest.NewLogger(t)
+
+	t.Run("should purge secrets deleted before retention", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		mockRepo.On("PurgeTrashBefore", ctx, mock.MatchedBy(func(before time.Time) bool {
+			return time.Until(before) < -23*time.Hour && time.Until(before) > -25*time.Hour
+		})).Return(int64(3), nil)
+
+		purged, err := service.PurgeExpiredTrash(ctx, 24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(3), purged)
+
+		mockRepo.AssertExpectations(t)
+	})
+
+	t.Run("should return repository error", func(t *testing.T) {
+		mockRepo := new(MockSecretRepository)
+		service := &SecretService{
+			repository: mockRepo,
+			logger:     logger,
+		}
+
+		expectedError := errors.New("database error")
+		mockRepo.On("PurgeTrashBefore", ctx, mock.Anything).Return(int64(0), expectedError)
+
+		_, err := service.PurgeExpiredTrash(ctx, time.Hour)
+		assert.Equal(t, expectedError, err)
+
+		mockRepo.AssertExpectations(t)
+	})
+}
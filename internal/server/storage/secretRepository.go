@@ -2,11 +2,21 @@ package storage
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/desepticon55/gophkeeper/internal/model"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
 )
 
+// Postgres error code of unique constraint violation
+const uniqueViolationCode = "23505"
+
+const secretColumns = "name, username, content, type, opt_lock, notes, tags, url, created_at, updated_at, last_accessed_at, expires_at, rotate_after, burn_after_reading"
+
 type SecretRepository struct {
 	pool *pgxpool.Pool
 }
@@ -29,8 +39,12 @@ func (r *SecretRepository) ExistSecret(ctx context.Context, userName string, sec
 }
 
 func (r *SecretRepository) CreateSecret(ctx context.Context, userName string, secret model.Secret) error {
-	query := "insert into gophkeeper.secret(name, username, content, type, opt_lock) values ($1, $2, $3, $4, $5)"
-	_, err := r.pool.Exec(ctx, query, secret.Name, userName, secret.Content, secret.Type, 0)
+	query := `
+		insert into gophkeeper.secret(name, username, content, type, opt_lock, notes, tags, url, expires_at, rotate_after, burn_after_reading)
+		values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`
+	_, err := r.pool.Exec(ctx, query, secret.Name, userName, secret.Content, secret.Type, 0, secret.Notes, tagsOrEmpty(secret.Tags), secret.URL,
+		secret.ExpiresAt, secret.RotateAfter, secret.BurnAfterReading)
 	if err != nil {
 		return err
 	}
@@ -39,9 +53,8 @@ func (r *SecretRepository) CreateSecret(ctx context.Context, userName string, se
 }
 
 func (r *SecretRepository) FindSecret(ctx context.Context, userName string, secretName string) (model.Secret, error) {
-	var secret model.Secret
-	query := "select name, username, content, type, opt_lock from gophkeeper.secret where username = $1 and name = $2"
-	err := r.pool.QueryRow(ctx, query, userName, secretName).Scan(&secret.Name, &secret.Username, &secret.Content, &secret.Type, &secret.Version)
+	query := "select " + secretColumns + " from gophkeeper.secret where username = $1 and name = $2"
+	secret, err := scanSecret(r.pool.QueryRow(ctx, query, userName, secretName))
 	if err != nil {
 		return model.Secret{}, err
 	}
@@ -49,13 +62,185 @@ func (r *SecretRepository) FindSecret(ctx context.Context, userName string, secr
 	return secret, nil
 }
 
-func (r *SecretRepository) FindAllSecrets(ctx context.Context, userName string) ([]model.Secret, error) {
+// Find user secrets matching filter. Returns up to filter.Limit secrets with names after filter.Cursor
+func (r *SecretRepository) FindAllSecrets(ctx context.Context, userName string, filter model.SecretFilter) ([]model.Secret, error) {
+	conditions := []string{"username = $1"}
+	args := []interface{}{userName}
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Name != "" {
+		addCondition("name LIKE $%d", namePatternToLike(filter.Name))
+	}
+	if filter.Folder != "" {
+		addCondition("name LIKE $%d", folderToLike(filter.Folder))
+	}
+	if filter.Type != "" {
+		addCondition("type = $%d", filter.Type)
+	}
+	if filter.Tag != "" {
+		addCondition("tags @> $%d", []string{filter.Tag})
+	}
+	if filter.DueBefore != nil {
+		addCondition("(expires_at < $%[1]d OR rotate_after < $%[1]d)", *filter.DueBefore)
+	}
+	if filter.Cursor != "" {
+		addCondition("name > $%d", filter.Cursor)
+	}
+
 	query := `
-		SELECT name, username, content, type, opt_lock
+		SELECT ` + secretColumns + `
 		FROM gophkeeper.secret
-		WHERE username = $1
+		WHERE ` + strings.Join(conditions, " AND ") + `
 		ORDER BY name
 	`
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+
+	rows, err := r.pool.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var secrets []model.Secret
+	for rows.Next() {
+		secret, err := scanSecret(rows)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, secret)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(secrets) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+
+	return secrets, nil
+}
+
+// Mark secret as accessed right now
+func (r *SecretRepository) TouchSecret(ctx context.Context, userName string, secretName string) error {
+	query := "UPDATE gophkeeper.secret SET last_accessed_at = now() WHERE username = $1 AND name = $2"
+	result, err := r.pool.Exec(ctx, query, userName, secretName)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete secret marked as burn after reading and return it. Only one of concurrent readers gets the secret
+func (r *SecretRepository) BurnSecret(ctx context.Context, userName string, secretName string) (model.Secret, error) {
+	query := `
+		DELETE FROM gophkeeper.secret
+		WHERE username = $1 AND name = $2 AND burn_after_reading
+		RETURNING ` + secretColumns
+	secret, err := scanSecret(r.pool.QueryRow(ctx, query, userName, secretName))
+	if err != nil {
+		return model.Secret{}, err
+	}
+
+	return secret, nil
+}
+
+// Rename secret keeping its content, metadata and version
+func (r *SecretRepository) RenameSecret(ctx context.Context, userName string, from string, to string) error {
+	query := "UPDATE gophkeeper.secret SET name = $3, updated_at = now() WHERE username = $1 AND name = $2"
+	result, err := r.pool.Exec(ctx, query, userName, from, to)
+	if err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrSecretExistToCurrentUser
+		}
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Update secret when it was not changed since it was read with given version. Version is incremented,
+// ErrSecretVersionConflict is returned for stale version and pgx.ErrNoRows for missing secret
+func (r *SecretRepository) UpdateSecret(ctx context.Context, userName string, secret model.Secret) error {
+	query := `
+		UPDATE gophkeeper.secret SET
+			content = $4,
+			type = $5,
+			notes = $6,
+			tags = $7,
+			url = $8,
+			expires_at = $9,
+			rotate_after = $10,
+			burn_after_reading = $11,
+			opt_lock = opt_lock + 1,
+			updated_at = now()
+		WHERE username = $1 AND name = $2 AND opt_lock = $3
+	`
+	result, err := r.pool.Exec(ctx, query, userName, secret.Name, secret.Version, secret.Content, secret.Type, secret.Notes,
+		tagsOrEmpty(secret.Tags), secret.URL, secret.ExpiresAt, secret.RotateAfter, secret.BurnAfterReading)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		exists, err := r.ExistSecret(ctx, userName, secret.Name)
+		if err != nil {
+			return err
+		}
+		if exists {
+			return model.ErrSecretVersionConflict
+		}
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Move secret to trash
+func (r *SecretRepository) TrashSecret(ctx context.Context, userName string, secretName string) error {
+	query := `
+		WITH deleted AS (
+			DELETE FROM gophkeeper.secret
+			WHERE username = $1 AND name = $2
+			RETURNING ` + secretColumns + `
+		)
+		INSERT INTO gophkeeper.secret_trash (` + secretColumns + `)
+		SELECT ` + secretColumns + ` FROM deleted
+	`
+	result, err := r.pool.Exec(ctx, query, userName, secretName)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Find all user secrets in trash, recently deleted first
+func (r *SecretRepository) FindTrash(ctx context.Context, userName string) ([]model.Secret, error) {
+	query := `
+		SELECT ` + secretColumns + `, deleted_at
+		FROM gophkeeper.secret_trash
+		WHERE username = $1
+		ORDER BY deleted_at DESC, name
+	`
 
 	rows, err := r.pool.Query(ctx, query, userName)
 	if err != nil {
@@ -65,10 +250,12 @@ func (r *SecretRepository) FindAllSecrets(ctx context.Context, userName string)
 
 	var secrets []model.Secret
 	for rows.Next() {
-		var secret model.Secret
-		if err := rows.Scan(&secret.Name, &secret.Username, &secret.Content, &secret.Type, &secret.Version); err != nil {
+		var deletedAt time.Time
+		secret, err := scanSecret(rows, &deletedAt)
+		if err != nil {
 			return nil, err
 		}
+		secret.DeletedAt = &deletedAt
 		secrets = append(secrets, secret)
 	}
 
@@ -83,10 +270,24 @@ func (r *SecretRepository) FindAllSecrets(ctx context.Context, userName string)
 	return secrets, nil
 }
 
-func (r *SecretRepository) DeleteSecret(ctx context.Context, userName string, secretName string) error {
-	query := "DELETE FROM gophkeeper.secret WHERE username = $1 AND name = $2"
+// Restore the most recently deleted secret with name from trash
+func (r *SecretRepository) RestoreSecret(ctx context.Context, userName string, secretName string) error {
+	query := `
+		WITH restored AS (
+			DELETE FROM gophkeeper.secret_trash
+			WHERE username = $1 AND name = $2 AND deleted_at = (
+				SELECT max(deleted_at) FROM gophkeeper.secret_trash WHERE username = $1 AND name = $2
+			)
+			RETURNING ` + secretColumns + `
+		)
+		INSERT INTO gophkeeper.secret (` + secretColumns + `)
+		SELECT ` + secretColumns + ` FROM restored
+	`
 	result, err := r.pool.Exec(ctx, query, userName, secretName)
 	if err != nil {
+		if isUniqueViolation(err) {
+			return model.ErrSecretExistToCurrentUser
+		}
 		return err
 	}
 
@@ -96,3 +297,210 @@ func (r *SecretRepository) DeleteSecret(ctx context.Context, userName string, se
 
 	return nil
 }
+
+// Permanently delete all secrets with name from trash
+func (r *SecretRepository) PurgeSecret(ctx context.Context, userName string, secretName string) error {
+	query := "DELETE FROM gophkeeper.secret_trash WHERE username = $1 AND name = $2"
+	result, err := r.pool.Exec(ctx, query, userName, secretName)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Permanently delete secrets of all users moved to trash before time. Returns count of deleted secrets
+func (r *SecretRepository) PurgeTrashBefore(ctx context.Context, before time.Time) (int64, error) {
+	query := "DELETE FROM gophkeeper.secret_trash WHERE deleted_at < $1"
+	result, err := r.pool.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Create or update secrets in one transaction. Version of updated secret is incremented,
+// created secret keeps the given version. Returns counts of created and updated secrets
+func (r *SecretRepository) UpsertSecrets(ctx context.Context, userName string, secrets []model.Secret) (model.SecretBatchResult, error) {
+	query := `
+		INSERT INTO gophkeeper.secret AS s (name, username, content, type, opt_lock, notes, tags, url, expires_at, rotate_after, burn_after_reading)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (username, name) DO UPDATE SET
+			content = excluded.content,
+			type = excluded.type,
+			opt_lock = s.opt_lock + 1,
+			notes = excluded.notes,
+			tags = excluded.tags,
+			url = excluded.url,
+			expires_at = excluded.expires_at,
+			rotate_after = excluded.rotate_after,
+			burn_after_reading = excluded.burn_after_reading,
+			updated_at = now()
+		RETURNING xmax = 0
+	`
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return model.SecretBatchResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	var result model.SecretBatchResult
+	for _, secret := range secrets {
+		var inserted bool
+		err := tx.QueryRow(ctx, query, secret.Name, userName, secret.Content, secret.Type, secret.Version, secret.Notes,
+			tagsOrEmpty(secret.Tags), secret.URL, secret.ExpiresAt, secret.RotateAfter, secret.BurnAfterReading).Scan(&inserted)
+		if err != nil {
+			return model.SecretBatchResult{}, fmt.Errorf("error during upsert secret \"%s\": %w", secret.Name, err)
+		}
+		if inserted {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return model.SecretBatchResult{}, err
+	}
+	return result, nil
+}
+
+// Find sizes of existing user secrets by names
+func (r *SecretRepository) FindSecretSizes(ctx context.Context, userName string, secretNames []string) (map[string]int64, error) {
+	query := `
+		SELECT name, octet_length(content) + coalesce(octet_length(notes), 0)
+		FROM gophkeeper.secret
+		WHERE username = $1 AND name = ANY($2)
+	`
+	rows, err := r.pool.Query(ctx, query, userName, secretNames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sizes := make(map[string]int64)
+	for rows.Next() {
+		var name string
+		var size int64
+		if err := rows.Scan(&name, &size); err != nil {
+			return nil, err
+		}
+		sizes[name] = size
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sizes, nil
+}
+
+// Count user secrets and their size in bytes, including secrets in trash. Limits are not filled
+func (r *SecretRepository) FindUsage(ctx context.Context, userName string) (model.Usage, error) {
+	query := `
+		SELECT
+			(SELECT count(*) FROM gophkeeper.secret WHERE username = $1),
+			(SELECT count(*) FROM gophkeeper.secret_trash WHERE username = $1),
+			(SELECT coalesce(sum(octet_length(content) + coalesce(octet_length(notes), 0)), 0) FROM gophkeeper.secret WHERE username = $1) +
+			(SELECT coalesce(sum(octet_length(content) + coalesce(octet_length(notes), 0)), 0) FROM gophkeeper.secret_trash WHERE username = $1)
+	`
+	var usage model.Usage
+	err := r.pool.QueryRow(ctx, query, userName).Scan(&usage.Secrets, &usage.TrashSecrets, &usage.TotalSize)
+	if err != nil {
+		return model.Usage{}, err
+	}
+
+	return usage, nil
+}
+
+// Move all secrets from folder to another one. Returns count of moved secrets
+func (r *SecretRepository) MoveFolder(ctx context.Context, userName string, from string, to string) (int64, error) {
+	query := `
+		UPDATE gophkeeper.secret
+		SET name = $3 || substr(name, length($2) + 1), updated_at = now()
+		WHERE username = $1 AND name LIKE $4
+	`
+	result, err := r.pool.Exec(ctx, query, userName, from, to, folderToLike(from))
+	if err != nil {
+		if isUniqueViolation(err) {
+			return 0, model.ErrSecretExistToCurrentUser
+		}
+		return 0, err
+	}
+
+	return result.RowsAffected(), nil
+}
+
+// Scan secret columns, extra destinations are used for columns selected after them
+func scanSecret(row pgx.Row, extra ...interface{}) (model.Secret, error) {
+	var secret model.Secret
+	dest := []interface{}{
+		&secret.Name,
+		&secret.Username,
+		&secret.Content,
+		&secret.Type,
+		&secret.Version,
+		&secret.Notes,
+		&secret.Tags,
+		&secret.URL,
+		&secret.CreatedAt,
+		&secret.UpdatedAt,
+		&secret.LastAccessedAt,
+		&secret.ExpiresAt,
+		&secret.RotateAfter,
+		&secret.BurnAfterReading,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return model.Secret{}, err
+	}
+
+	return secret, nil
+}
+
+// Convert name glob pattern to LIKE pattern. Pattern without wildcards is treated as prefix
+func namePatternToLike(pattern string) string {
+	if !strings.ContainsAny(pattern, "*?") {
+		return escapeLike(pattern) + "%"
+	}
+
+	var builder strings.Builder
+	for _, ch := range pattern {
+		switch ch {
+		case '*':
+			builder.WriteRune('%')
+		case '?':
+			builder.WriteRune('_')
+		default:
+			builder.WriteString(escapeLike(string(ch)))
+		}
+	}
+	return builder.String()
+}
+
+// LIKE pattern matching all secrets in folder and subfolders
+func folderToLike(folder string) string {
+	return escapeLike(folder) + model.SecretPathSeparator + "%"
+}
+
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer("\\", "\\\\", "%", "\\%", "_", "\\_")
+	return replacer.Replace(value)
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == uniqueViolationCode
+}
+
+func tagsOrEmpty(tags []string) []string {
+	if tags == nil {
+		return []string{}
+	}
+	return tags
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"github.com/desepticon55/gophkeeper/internal/model"
 	"github.com/desepticon55/gophkeeper/pkg/utils"
+	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/zap/zaptest"
 	"testing"
+	"time"
 )
 
 func TestSecretRepository(t *testing.T) {
@@ -82,4 +84,412 @@ func TestSecretRepository(t *testing.T) {
 		assert.Equal(t, model.CredentialsSecretType, result.Type)
 		assert.Equal(t, []byte("Hello"), result.Content)
 	})
+
+	t.Run("FindSecretWithMetadata", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{
+			Name:    "testName",
+			Type:    model.CredentialsSecretType,
+			Content: []byte("Hello"),
+			Notes:   []byte("Notes"),
+			Tags:    []string{"work", "mail"},
+			URL:     "https://mail.example.com",
+		})
+		assert.NoError(t, err)
+
+		result, err := secretRepository.FindSecret(ctx, "testUser", "testName")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("Notes"), result.Notes)
+		assert.Equal(t, []string{"work", "mail"}, result.Tags)
+		assert.Equal(t, "https://mail.example.com", result.URL)
+		assert.NotNil(t, result.CreatedAt)
+		assert.NotNil(t, result.UpdatedAt)
+		assert.Nil(t, result.LastAccessedAt)
+	})
+
+	t.Run("TouchSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{
+			Name:    "testName",
+			Type:    model.CredentialsSecretType,
+			Content: []byte("Hello"),
+		})
+		assert.NoError(t, err)
+
+		err = secretRepository.TouchSecret(ctx, "testUser", "testName")
+		assert.NoError(t, err)
+
+		result, err := secretRepository.FindSecret(ctx, "testUser", "testName")
+		assert.NoError(t, err)
+		assert.NotNil(t, result.LastAccessedAt)
+		assert.Empty(t, result.Tags)
+	})
+
+	t.Run("FindAllSecrets", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		secrets := []model.Secret{
+			{Name: "prod-db", Type: model.CredentialsSecretType, Content: []byte("1"), Tags: []string{"prod"}},
+			{Name: "prod-card", Type: model.CardSecretType, Content: []byte("2"), Tags: []string{"prod", "bank"}},
+			{Name: "stage-db", Type: model.CredentialsSecretType, Content: []byte("3")},
+			{Name: "100%_sure", Type: model.TextSecretType, Content: []byte("4")},
+		}
+		for _, secret := range secrets {
+			assert.NoError(t, secretRepository.CreateSecret(ctx, "testUser", secret))
+		}
+
+		names := func(filter model.SecretFilter) []string {
+			result, err := secretRepository.FindAllSecrets(ctx, "testUser", filter)
+			assert.NoError(t, err)
+			var names []string
+			for _, secret := range result {
+				names = append(names, secret.Name)
+			}
+			return names
+		}
+
+		assert.Equal(t, []string{"100%_sure", "prod-card", "prod-db", "stage-db"}, names(model.SecretFilter{}))
+		assert.Equal(t, []string{"prod-card", "prod-db"}, names(model.SecretFilter{Name: "prod"}))
+		assert.Equal(t, []string{"prod-db", "stage-db"}, names(model.SecretFilter{Name: "*-db"}))
+		assert.Equal(t, []string{"100%_sure"}, names(model.SecretFilter{Name: "100%_"}))
+		assert.Equal(t, []string{"prod-card"}, names(model.SecretFilter{Tag: "bank"}))
+		assert.Equal(t, []string{"prod-db", "stage-db"}, names(model.SecretFilter{Type: model.CredentialsSecretType}))
+		assert.Equal(t, []string{"prod-db"}, names(model.SecretFilter{Cursor: "prod-card", Limit: 1}))
+
+		_, err := secretRepository.FindAllSecrets(ctx, "otherUser", model.SecretFilter{})
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("FindDueSecrets", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		now := time.Now()
+		past, future := now.Add(-time.Hour), now.Add(48*time.Hour)
+		secrets := []model.Secret{
+			{Name: "expired-card", Type: model.CardSecretType, Content: []byte("1"), ExpiresAt: &past},
+			{Name: "old-password", Type: model.CredentialsSecretType, Content: []byte("2"), RotateAfter: &past, ExpiresAt: &future},
+			{Name: "new-password", Type: model.CredentialsSecretType, Content: []byte("3"), RotateAfter: &future},
+			{Name: "note", Type: model.TextSecretType, Content: []byte("4")},
+		}
+		for _, secret := range secrets {
+			assert.NoError(t, secretRepository.CreateSecret(ctx, "testUser", secret))
+		}
+
+		result, err := secretRepository.FindAllSecrets(ctx, "testUser", model.SecretFilter{DueBefore: &now})
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		assert.Equal(t, "expired-card", result[0].Name)
+		assert.NotNil(t, result[0].ExpiresAt)
+		assert.Equal(t, "old-password", result[1].Name)
+		assert.NotNil(t, result[1].RotateAfter)
+
+		week := now.Add(7 * 24 * time.Hour)
+		result, err = secretRepository.FindAllSecrets(ctx, "testUser", model.SecretFilter{DueBefore: &week})
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+	})
+
+	t.Run("BurnSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "one-time", Type: model.TextSecretType, Content: []byte("1"), BurnAfterReading: true})
+		assert.NoError(t, err)
+		err = secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "regular", Type: model.TextSecretType, Content: []byte("2")})
+		assert.NoError(t, err)
+
+		_, err = secretRepository.BurnSecret(ctx, "testUser", "regular")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+		result, err := secretRepository.BurnSecret(ctx, "testUser", "one-time")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("1"), result.Content)
+		assert.True(t, result.BurnAfterReading)
+
+		_, err = secretRepository.BurnSecret(ctx, "testUser", "one-time")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+		_, err = secretRepository.FindSecret(ctx, "testUser", "one-time")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("FindUsage", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		usage, err := secretRepository.FindUsage(ctx, "testUser")
+		assert.NoError(t, err)
+		assert.Equal(t, model.Usage{}, usage)
+
+		err = secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "a", Type: model.TextSecretType, Content: []byte("1234"), Notes: []byte("56")})
+		assert.NoError(t, err)
+		err = secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "b", Type: model.TextSecretType, Content: []byte("123")})
+		assert.NoError(t, err)
+		err = secretRepository.CreateSecret(ctx, "otherUser", model.Secret{Name: "a", Type: model.TextSecretType, Content: []byte("123")})
+		assert.NoError(t, err)
+		err = secretRepository.TrashSecret(ctx, "testUser", "b")
+		assert.NoError(t, err)
+
+		usage, err = secretRepository.FindUsage(ctx, "testUser")
+		assert.NoError(t, err)
+		assert.Equal(t, model.Usage{Secrets: 1, TrashSecrets: 1, TotalSize: 9}, usage)
+	})
+
+	t.Run("UpsertSecrets", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "prod/db", Type: model.TextSecretType, Content: []byte("old")})
+		assert.NoError(t, err)
+
+		result, err := secretRepository.UpsertSecrets(ctx, "testUser", []model.Secret{
+			{Name: "prod/db", Type: model.CredentialsSecretType, Content: []byte("new"), Tags: []string{"db"}},
+			{Name: "stage/db", Type: model.TextSecretType, Content: []byte("1"), Version: 5},
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, model.SecretBatchResult{Created: 1, Updated: 1}, result)
+
+		updated, err := secretRepository.FindSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("new"), updated.Content)
+		assert.Equal(t, model.CredentialsSecretType, updated.Type)
+		assert.Equal(t, []string{"db"}, updated.Tags)
+		assert.Equal(t, int64(1), updated.Version)
+
+		created, err := secretRepository.FindSecret(ctx, "testUser", "stage/db")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(5), created.Version)
+
+		sizes, err := secretRepository.FindSecretSizes(ctx, "testUser", []string{"prod/db", "archive/db"})
+		assert.NoError(t, err)
+		assert.Equal(t, map[string]int64{"prod/db": 3}, sizes)
+
+		_, err = secretRepository.UpsertSecrets(ctx, "testUser", []model.Secret{
+			{Name: "archive/db", Type: model.TextSecretType, Content: []byte("1")},
+			{Name: "archive/cache", Type: model.TextSecretType},
+		})
+		assert.Error(t, err)
+
+		_, err = secretRepository.FindSecret(ctx, "testUser", "archive/db")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("UpdateSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "otp/github", Type: model.OTPSecretType, Content: []byte("1")})
+		assert.NoError(t, err)
+
+		err = secretRepository.UpdateSecret(ctx, "testUser", model.Secret{Name: "otp/github", Type: model.OTPSecretType, Content: []byte("2"), Version: 0})
+		assert.NoError(t, err)
+
+		err = secretRepository.UpdateSecret(ctx, "testUser", model.Secret{Name: "otp/github", Type: model.OTPSecretType, Content: []byte("3"), Version: 0})
+		assert.ErrorIs(t, err, model.ErrSecretVersionConflict)
+
+		result, err := secretRepository.FindSecret(ctx, "testUser", "otp/github")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("2"), result.Content)
+		assert.Equal(t, int64(1), result.Version)
+
+		err = secretRepository.UpdateSecret(ctx, "testUser", model.Secret{Name: "otp/gitlab", Type: model.OTPSecretType, Content: []byte("1")})
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("RenameSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		for _, name := range []string{"prod/db", "stage/db"} {
+			err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: name, Type: model.TextSecretType, Content: []byte(name), Tags: []string{"db"}})
+			assert.NoError(t, err)
+		}
+
+		err := secretRepository.RenameSecret(ctx, "testUser", "prod/db", "stage/db")
+		assert.ErrorIs(t, err, model.ErrSecretExistToCurrentUser)
+
+		err = secretRepository.RenameSecret(ctx, "testUser", "prod/db", "archive/db")
+		assert.NoError(t, err)
+
+		result, err := secretRepository.FindSecret(ctx, "testUser", "archive/db")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("prod/db"), result.Content)
+		assert.Equal(t, []string{"db"}, result.Tags)
+		assert.Equal(t, int64(0), result.Version)
+
+		err = secretRepository.RenameSecret(ctx, "testUser", "prod/db", "archive/db2")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("MoveFolder", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		for _, name := range []string{"prod/db/postgres", "prod/db/mysql", "prod/dbx", "stage/cache"} {
+			err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: name, Type: model.TextSecretType, Content: []byte("1")})
+			assert.NoError(t, err)
+		}
+
+		moved, err := secretRepository.MoveFolder(ctx, "testUser", "prod/db", "stage/db")
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), moved)
+
+		result, err := secretRepository.FindAllSecrets(ctx, "testUser", model.SecretFilter{Folder: "stage"})
+		assert.NoError(t, err)
+		assert.Len(t, result, 3)
+
+		_, err = secretRepository.MoveFolder(ctx, "testUser", "stage/db", "prod")
+		assert.NoError(t, err)
+		err = secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "stage/db/postgres", Type: model.TextSecretType, Content: []byte("1")})
+		assert.NoError(t, err)
+
+		_, err = secretRepository.MoveFolder(ctx, "testUser", "stage/db", "prod")
+		assert.ErrorIs(t, err, model.ErrSecretExistToCurrentUser)
+	})
+
+	t.Run("TrashSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "prod/db", Type: model.TextSecretType, Content: []byte("1"), Tags: []string{"db"}})
+		assert.NoError(t, err)
+
+		err = secretRepository.TrashSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		_, err = secretRepository.FindSecret(ctx, "testUser", "prod/db")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+		trash, err := secretRepository.FindTrash(ctx, "testUser")
+		assert.NoError(t, err)
+		assert.Len(t, trash, 1)
+		assert.Equal(t, []string{"db"}, trash[0].Tags)
+		assert.NotNil(t, trash[0].DeletedAt)
+
+		err = secretRepository.TrashSecret(ctx, "testUser", "prod/db")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("RestoreSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "prod/db", Type: model.TextSecretType, Content: []byte("old")})
+		assert.NoError(t, err)
+		err = secretRepository.TrashSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		err = secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "prod/db", Type: model.TextSecretType, Content: []byte("new")})
+		assert.NoError(t, err)
+
+		err = secretRepository.RestoreSecret(ctx, "testUser", "prod/db")
+		assert.ErrorIs(t, err, model.ErrSecretExistToCurrentUser)
+
+		err = secretRepository.TrashSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		err = secretRepository.RestoreSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		result, err := secretRepository.FindSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+		assert.Equal(t, []byte("new"), result.Content)
+
+		trash, err := secretRepository.FindTrash(ctx, "testUser")
+		assert.NoError(t, err)
+		assert.Len(t, trash, 1)
+
+		err = secretRepository.RestoreSecret(ctx, "testUser", "stage/db")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("PurgeSecret", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: "prod/db", Type: model.TextSecretType, Content: []byte("1")})
+		assert.NoError(t, err)
+		err = secretRepository.TrashSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		err = secretRepository.PurgeSecret(ctx, "testUser", "prod/db")
+		assert.NoError(t, err)
+
+		_, err = secretRepository.FindTrash(ctx, "testUser")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+
+		err = secretRepository.PurgeSecret(ctx, "testUser", "prod/db")
+		assert.ErrorIs(t, err, pgx.ErrNoRows)
+	})
+
+	t.Run("PurgeTrashBefore", func(t *testing.T) {
+		t.Cleanup(func() {
+			if err := utils.ClearTables(ctx, pool); err != nil {
+				t.Fatalf("failed to clear tables: %s", err)
+			}
+		})
+		for _, name := range []string{"prod/db", "stage/db"} {
+			err := secretRepository.CreateSecret(ctx, "testUser", model.Secret{Name: name, Type: model.TextSecretType, Content: []byte("1")})
+			assert.NoError(t, err)
+			err = secretRepository.TrashSecret(ctx, "testUser", name)
+			assert.NoError(t, err)
+		}
+
+		purged, err := secretRepository.PurgeTrashBefore(ctx, time.Now().Add(-time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(0), purged)
+
+		purged, err = secretRepository.PurgeTrashBefore(ctx, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), purged)
+	})
+}
+
+func TestNamePatternToLike(t *testing.T) {
+	tests := []struct {
+		pattern  string
+		expected string
+	}{
+		{pattern: "prod", expected: "prod%"},
+		{pattern: "prod/*", expected: "prod/%"},
+		{pattern: "db-?", expected: "db-_"},
+		{pattern: "100%_", expected: "100\\%\\_%"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			assert.Equal(t, tt.expected, namePatternToLike(tt.pattern))
+		})
+	}
 }
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE gophkeeper.secret
+    ADD COLUMN notes            BYTEA,
+    ADD COLUMN tags             TEXT[]      NOT NULL DEFAULT '{}',
+    ADD COLUMN url              TEXT        NOT NULL DEFAULT '',
+    ADD COLUMN created_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN updated_at       TIMESTAMPTZ NOT NULL DEFAULT now(),
+    ADD COLUMN last_accessed_at TIMESTAMPTZ;
+
+CREATE INDEX secret_tags_idx ON gophkeeper.secret USING GIN (tags);
+
+-- +goose Down
+DROP INDEX gophkeeper.secret_tags_idx;
+
+ALTER TABLE gophkeeper.secret
+    DROP COLUMN notes,
+    DROP COLUMN tags,
+    DROP COLUMN url,
+    DROP COLUMN created_at,
+    DROP COLUMN updated_at,
+    DROP COLUMN last_accessed_at;
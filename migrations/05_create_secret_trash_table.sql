@@ -0,0 +1,22 @@
+-- +goose Up
+CREATE TABLE gophkeeper.secret_trash
+(
+    name             VARCHAR(255) NOT NULL,
+    username         VARCHAR(255) NOT NULL,
+    content          BYTEA        NOT NULL,
+    type             VARCHAR(30)  NOT NULL,
+    opt_lock         BIGINT       NOT NULL,
+    notes            BYTEA,
+    tags             TEXT[]       NOT NULL DEFAULT '{}',
+    url              TEXT         NOT NULL DEFAULT '',
+    created_at       TIMESTAMPTZ  NOT NULL,
+    updated_at       TIMESTAMPTZ  NOT NULL,
+    last_accessed_at TIMESTAMPTZ,
+    deleted_at       TIMESTAMPTZ  NOT NULL DEFAULT now(),
+    PRIMARY KEY (username, name, deleted_at)
+);
+
+CREATE INDEX secret_trash_deleted_at_idx ON gophkeeper.secret_trash (deleted_at);
+
+-- +goose Down
+DROP TABLE gophkeeper.secret_trash;
@@ -0,0 +1,21 @@
+-- +goose Up
+ALTER TABLE gophkeeper.secret
+    ADD COLUMN expires_at         TIMESTAMPTZ,
+    ADD COLUMN rotate_after       TIMESTAMPTZ,
+    ADD COLUMN burn_after_reading BOOLEAN NOT NULL DEFAULT false;
+
+ALTER TABLE gophkeeper.secret_trash
+    ADD COLUMN expires_at         TIMESTAMPTZ,
+    ADD COLUMN rotate_after       TIMESTAMPTZ,
+    ADD COLUMN burn_after_reading BOOLEAN NOT NULL DEFAULT false;
+
+-- +goose Down
+ALTER TABLE gophkeeper.secret_trash
+    DROP COLUMN expires_at,
+    DROP COLUMN rotate_after,
+    DROP COLUMN burn_after_reading;
+
+ALTER TABLE gophkeeper.secret
+    DROP COLUMN expires_at,
+    DROP COLUMN rotate_after,
+    DROP COLUMN burn_after_reading;
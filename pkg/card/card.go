@@ -0,0 +1,198 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	VisaBrand       = "VISA"
+	MastercardBrand = "MASTERCARD"
+	AmexBrand       = "AMEX"
+	DiscoverBrand   = "DISCOVER"
+	JCBBrand        = "JCB"
+	DinersBrand     = "DINERS"
+	UnionPayBrand   = "UNIONPAY"
+	MirBrand        = "MIR"
+	MaestroBrand    = "MAESTRO"
+	UnknownBrand    = "UNKNOWN"
+)
+
+// Card number prefix range of brand, both bounds are inclusive
+type prefixRange struct {
+	from int
+	to   int
+}
+
+// Number prefixes and lengths of payment system
+type brandRule struct {
+	brand    string
+	prefixes []prefixRange
+	lengths  []int
+	// Length of card verification code
+	codeLength int
+}
+
+// Brands are checked in order, more specific prefixes go first
+var brandRules = []brandRule{
+	{brand: AmexBrand, prefixes: []prefixRange{{34, 34}, {37, 37}}, lengths: []int{15}, codeLength: 4},
+	{brand: DinersBrand, prefixes: []prefixRange{{300, 305}, {36, 36}, {38, 39}}, lengths: []int{14, 16, 17, 18, 19}, codeLength: 3},
+	{brand: DiscoverBrand, prefixes: []prefixRange{{6011, 6011}, {644, 649}, {65, 65}}, lengths: []int{16, 17, 18, 19}, codeLength: 3},
+	{brand: JCBBrand, prefixes: []prefixRange{{3528, 3589}}, lengths: []int{16, 17, 18, 19}, codeLength: 3},
+	{brand: MirBrand, prefixes: []prefixRange{{2200, 2204}}, lengths: []int{16, 17, 18, 19}, codeLength: 3},
+	{brand: MastercardBrand, prefixes: []prefixRange{{51, 55}, {2221, 2720}}, lengths: []int{16}, codeLength: 3},
+	{brand: UnionPayBrand, prefixes: []prefixRange{{62, 62}}, lengths: []int{16, 17, 18, 19}, codeLength: 3},
+	{brand: MaestroBrand, prefixes: []prefixRange{{50, 50}, {56, 58}, {63, 63}, {67, 67}}, lengths: []int{12, 13, 14, 15, 16, 17, 18, 19}, codeLength: 3},
+	{brand: VisaBrand, prefixes: []prefixRange{{4, 4}}, lengths: []int{13, 16, 19}, codeLength: 3},
+}
+
+// Remove spaces and dashes used to group card number digits
+func Normalize(number string) string {
+	return strings.NewReplacer(" ", "", "-", "").Replace(strings.TrimSpace(number))
+}
+
+// Check number checksum by Luhn algorithm
+func Luhn(number string) bool {
+	if number == "" {
+		return false
+	}
+
+	sum := 0
+	double := false
+	for i := len(number) - 1; i >= 0; i-- {
+		digit := int(number[i] - '0')
+		if digit < 0 || digit > 9 {
+			return false
+		}
+		if double {
+			digit *= 2
+			if digit > 9 {
+				digit -= 9
+			}
+		}
+		sum += digit
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// Detect payment system by number prefix, UnknownBrand is returned when no brand matches
+func DetectBrand(number string) string {
+	if rule, ok := findBrandRule(Normalize(number)); ok {
+		return rule.brand
+	}
+	return UnknownBrand
+}
+
+func findBrandRule(number string) (brandRule, bool) {
+	for _, rule := range brandRules {
+		for _, prefix := range rule.prefixes {
+			digits := len(strconv.Itoa(prefix.from))
+			if len(number) < digits {
+				continue
+			}
+			value, err := strconv.Atoi(number[:digits])
+			if err == nil && value >= prefix.from && value <= prefix.to {
+				return rule, true
+			}
+		}
+	}
+	return brandRule{}, false
+}
+
+// Validate card number and return its brand. Numbers of unknown brands are accepted when checksum is valid
+func ValidateNumber(number string) (string, error) {
+	number = Normalize(number)
+	if len(number) < 12 || len(number) > 19 {
+		return "", errors.New("card number should have from 12 to 19 digits")
+	}
+	if !Luhn(number) {
+		return "", errors.New("card number is invalid, checksum does not match")
+	}
+
+	rule, ok := findBrandRule(number)
+	if !ok {
+		return UnknownBrand, nil
+	}
+	for _, length := range rule.lengths {
+		if len(number) == length {
+			return rule.brand, nil
+		}
+	}
+	return "", fmt.Errorf("%s card number can`t have %d digits", rule.brand, len(number))
+}
+
+// Validate card verification code length of brand
+func ValidateCode(brand string, code string) error {
+	code = strings.TrimSpace(code)
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return errors.New("card code should contain digits only")
+		}
+	}
+
+	for _, rule := range brandRules {
+		if rule.brand == brand {
+			if len(code) != rule.codeLength {
+				return fmt.Errorf("%s card code should have %d digits", brand, rule.codeLength)
+			}
+			return nil
+		}
+	}
+	if len(code) != 3 && len(code) != 4 {
+		return errors.New("card code should have 3 or 4 digits")
+	}
+	return nil
+}
+
+// Month and year when card expires
+type Expiry struct {
+	Month int
+	Year  int
+}
+
+// Parse expiry date in MM/YY or MM/YYYY format
+func ParseExpiry(value string) (Expiry, error) {
+	month, year, ok := strings.Cut(strings.TrimSpace(value), "/")
+	if !ok || len(month) != 2 || len(year) != 2 && len(year) != 4 {
+		return Expiry{}, fmt.Errorf("card date \"%s\" should be in MM/YY format", value)
+	}
+
+	parsedMonth, err := strconv.Atoi(month)
+	if err != nil || parsedMonth < 1 || parsedMonth > 12 {
+		return Expiry{}, fmt.Errorf("card month \"%s\" should be from 01 to 12", month)
+	}
+	parsedYear, err := strconv.Atoi(year)
+	if err != nil || parsedYear < 0 {
+		return Expiry{}, fmt.Errorf("card year \"%s\" is invalid", year)
+	}
+	if len(year) == 2 {
+		parsedYear += 2000
+	}
+	return Expiry{Month: parsedMonth, Year: parsedYear}, nil
+}
+
+// Card is valid until the end of expiry month
+func (e Expiry) End(location *time.Location) time.Time {
+	return time.Date(e.Year, time.Month(e.Month)+1, 1, 0, 0, 0, 0, location)
+}
+
+func (e Expiry) Expired(now time.Time) bool {
+	return !now.Before(e.End(now.Location()))
+}
+
+func (e Expiry) String() string {
+	return fmt.Sprintf("%02d/%02d", e.Month, e.Year%100)
+}
+
+// Hide card number except last four digits, e.g. **** 4242
+func Mask(number string) string {
+	number = Normalize(number)
+	if len(number) <= 4 {
+		return "****"
+	}
+	return "**** " + number[len(number)-4:]
+}
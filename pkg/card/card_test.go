@@ -0,0 +1,89 @@
+package card
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestLuhn(t *testing.T) {
+	assert.True(t, Luhn("4242424242424242"))
+	assert.True(t, Luhn("79927398713"))
+	assert.False(t, Luhn("4242424242424241"))
+	assert.False(t, Luhn("4242x24242424242"))
+	assert.False(t, Luhn(""))
+}
+
+func TestValidateNumber(t *testing.T) {
+	testCases := []struct {
+		number string
+		brand  string
+	}{
+		{number: "4242 4242 4242 4242", brand: VisaBrand},
+		{number: "5555-5555-5555-4444", brand: MastercardBrand},
+		{number: "2223003122003222", brand: MastercardBrand},
+		{number: "378282246310005", brand: AmexBrand},
+		{number: "6011111111111117", brand: DiscoverBrand},
+		{number: "3530111333300000", brand: JCBBrand},
+		{number: "36227206271667", brand: DinersBrand},
+		{number: "6200000000000005", brand: UnionPayBrand},
+		{number: "2200000000000004", brand: MirBrand},
+		{number: "6759649826438453", brand: MaestroBrand},
+		{number: "9999999999999995", brand: UnknownBrand},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.number, func(t *testing.T) {
+			brand, err := ValidateNumber(tc.number)
+			require.NoError(t, err)
+			assert.Equal(t, tc.brand, brand)
+			assert.Equal(t, tc.brand, DetectBrand(tc.number))
+		})
+	}
+
+	_, err := ValidateNumber("4242424242424241")
+	assert.Error(t, err)
+	_, err = ValidateNumber("4242")
+	assert.Error(t, err)
+	// Luhn valid, but Amex has 15 digits
+	_, err = ValidateNumber("3400000000000009")
+	assert.Error(t, err)
+}
+
+func TestValidateCode(t *testing.T) {
+	assert.NoError(t, ValidateCode(VisaBrand, "123"))
+	assert.Error(t, ValidateCode(VisaBrand, "1234"))
+	assert.NoError(t, ValidateCode(AmexBrand, "1234"))
+	assert.Error(t, ValidateCode(AmexBrand, "123"))
+	assert.NoError(t, ValidateCode(UnknownBrand, "1234"))
+	assert.Error(t, ValidateCode(UnknownBrand, "12"))
+	assert.Error(t, ValidateCode(VisaBrand, "12a"))
+}
+
+func TestParseExpiry(t *testing.T) {
+	expiry, err := ParseExpiry("02/28")
+	require.NoError(t, err)
+	assert.Equal(t, Expiry{Month: 2, Year: 2028}, expiry)
+	assert.Equal(t, "02/28", expiry.String())
+
+	expiry, err = ParseExpiry("12/2030")
+	require.NoError(t, err)
+	assert.Equal(t, Expiry{Month: 12, Year: 2030}, expiry)
+	assert.Equal(t, time.Date(2031, 1, 1, 0, 0, 0, 0, time.UTC), expiry.End(time.UTC))
+
+	for _, value := range []string{"13/28", "00/28", "2/28", "02-28", "02/2", "ab/cd"} {
+		_, err := ParseExpiry(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestExpiry_Expired(t *testing.T) {
+	expiry := Expiry{Month: 5, Year: 2024}
+	assert.False(t, expiry.Expired(time.Date(2024, 5, 31, 23, 59, 0, 0, time.UTC)))
+	assert.True(t, expiry.Expired(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestMask(t *testing.T) {
+	assert.Equal(t, "**** 4242", Mask("4242 4242 4242 4242"))
+	assert.Equal(t, "****", Mask("42"))
+}
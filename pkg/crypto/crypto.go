@@ -5,9 +5,13 @@ import (
 	"crypto/cipher"
 	"crypto/rand"
 	"fmt"
+	"golang.org/x/crypto/argon2"
 	"io"
 )
 
+// Размер ключа AES-256
+const KeySize = 32
+
 // Шифрование с использованием AES
 func EncryptData(data []byte, key []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
@@ -39,9 +43,14 @@ func DecryptData(data []byte, key []byte) ([]byte, error) {
 	}
 
 	iv := data[:aes.BlockSize]
-	data = data[aes.BlockSize:]
+	plaintext := make([]byte, len(data)-aes.BlockSize)
 
 	stream := cipher.NewCFBDecrypter(block, iv)
-	stream.XORKeyStream(data, data)
-	return data, nil
+	stream.XORKeyStream(plaintext, data[aes.BlockSize:])
+	return plaintext, nil
+}
+
+// Получение ключа из пароля с использованием Argon2id
+func DeriveKey(passphrase []byte, salt []byte) []byte {
+	return argon2.IDKey(passphrase, salt, 1, 64*1024, 4, KeySize)
 }
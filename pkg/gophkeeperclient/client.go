@@ -0,0 +1,342 @@
+// Package gophkeeperclient is a typed client of GophKeeper server API.
+//
+// Secret content is sent as is, it should be encrypted by caller.
+package gophkeeperclient
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTimeout      = 10 * time.Second
+	defaultRetries      = 3
+	defaultRetryWait    = 200 * time.Millisecond
+	defaultRetryMaxWait = 2 * time.Second
+)
+
+// Client of server API. Idempotent requests are retried with exponential backoff
+// on transport errors and temporary server errors. Read of one secret is not retried,
+// because secret burned after reading is deleted by the first request
+type Client struct {
+	http         *resty.Client
+	token        string
+	retries      int
+	retryWait    time.Duration
+	retryMaxWait time.Duration
+}
+
+// Create client of server with base address, e.g. http://localhost:8080
+func New(address string) *Client {
+	return &Client{
+		http:         resty.New().SetBaseURL(strings.TrimRight(address, "/")).SetTimeout(defaultTimeout),
+		retries:      defaultRetries,
+		retryWait:    defaultRetryWait,
+		retryMaxWait: defaultRetryMaxWait,
+	}
+}
+
+// Set auth token sent with requests of user API
+func (c *Client) WithToken(token string) *Client {
+	c.token = token
+	return c
+}
+
+// Set timeout of one request attempt
+func (c *Client) WithTimeout(timeout time.Duration) *Client {
+	c.http.SetTimeout(timeout)
+	return c
+}
+
+// Set count of retries and backoff bounds, zero count disables retries
+func (c *Client) WithRetries(count int, wait time.Duration, maxWait time.Duration) *Client {
+	c.retries = count
+	c.retryWait = wait
+	c.retryMaxWait = maxWait
+	return c
+}
+
+// Set TLS settings of requests, e.g. CA certificates of server
+func (c *Client) WithTLSConfig(config *tls.Config) *Client {
+	c.http.SetTLSClientConfig(config)
+	return c
+}
+
+// Set transport of requests, e.g. with custom TLS config
+func (c *Client) WithTransport(transport http.RoundTripper) *Client {
+	c.http.SetTransport(transport)
+	return c
+}
+
+// Register new user and return auth token
+func (c *Client) Register(ctx context.Context, user User) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/api/user/register", nil, user, nil)
+	if err != nil {
+		return "", err
+	}
+	return authToken(resp)
+}
+
+// Login user and return auth token
+func (c *Client) Login(ctx context.Context, user User) (string, error) {
+	resp, err := c.do(ctx, http.MethodPost, "/api/user/login", nil, user, nil)
+	if err != nil {
+		return "", err
+	}
+	return authToken(resp)
+}
+
+// Create new secret, ErrConflict is returned if secret already exists
+func (c *Client) CreateSecret(ctx context.Context, secret Secret) error {
+	_, err := c.do(ctx, http.MethodPost, "/api/user/secret", nil, secret, nil)
+	return err
+}
+
+// Update secret read with secret.Version, ErrConflict is returned if secret was changed since it was read.
+// ErrAmbiguous is returned when conflict follows retry, because secret may be changed by the first attempt
+func (c *Client) UpdateSecret(ctx context.Context, secret Secret) error {
+	_, err := c.do(ctx, http.MethodPut, "/api/user/secret/"+escapePath(secret.Name), nil, secret, nil)
+	return err
+}
+
+// Create or update secrets in one transaction
+func (c *Client) UpsertSecrets(ctx context.Context, secrets []Secret) (SecretBatchResult, error) {
+	var result SecretBatchResult
+	_, err := c.do(ctx, http.MethodPut, "/api/user/secret", nil, secrets, &result)
+	return result, err
+}
+
+// Read one secret by name
+func (c *Client) FindSecret(ctx context.Context, name string) (Secret, error) {
+	var secret Secret
+	_, err := c.send(ctx, false, http.MethodGet, "/api/user/secret/"+escapePath(name), nil, nil, &secret)
+	return secret, err
+}
+
+// Read one page of secrets matching filter
+func (c *Client) FindSecrets(ctx context.Context, filter SecretFilter) (SecretPage, error) {
+	var page SecretPage
+	resp, err := c.do(ctx, http.MethodGet, "/api/user/secret", filterParams(filter), nil, &page)
+	if err != nil {
+		return SecretPage{}, err
+	}
+	if resp.StatusCode() == http.StatusNoContent {
+		return SecretPage{}, nil
+	}
+	return page, nil
+}
+
+// Read secrets matching filter from all pages
+func (c *Client) FindAllSecrets(ctx context.Context, filter SecretFilter) ([]Secret, error) {
+	var secrets []Secret
+	for {
+		page, err := c.FindSecrets(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		secrets = append(secrets, page.Secrets...)
+
+		if page.NextCursor == "" {
+			return secrets, nil
+		}
+		filter.Cursor = page.NextCursor
+	}
+}
+
+// Move secret to trash, ErrAmbiguous is returned when secret is not found after retry
+func (c *Client) DeleteSecret(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/user/secret/"+escapePath(name), nil, nil, nil)
+	return err
+}
+
+// Rename secret, name may move secret to other folder
+func (c *Client) RenameSecret(ctx context.Context, from string, to string) error {
+	_, err := c.do(ctx, http.MethodPost, "/api/user/secret/"+escapePath(from)+"/rename", nil, secretRename{To: to}, nil)
+	return err
+}
+
+// Read direct subfolders and secrets of folder, empty path is root folder
+func (c *Client) ListFolder(ctx context.Context, path string) (Folder, error) {
+	var folder Folder
+	_, err := c.do(ctx, http.MethodGet, "/api/user/folder/"+escapePath(path), nil, nil, &folder)
+	return folder, err
+}
+
+// Move folder with all nested secrets
+func (c *Client) MoveFolder(ctx context.Context, from string, to string) error {
+	_, err := c.do(ctx, http.MethodPost, "/api/user/folder/move", nil, folderMove{From: from, To: to}, nil)
+	return err
+}
+
+// Read secrets in trash
+func (c *Client) FindTrash(ctx context.Context) ([]Secret, error) {
+	var secrets []Secret
+	resp, err := c.do(ctx, http.MethodGet, "/api/user/trash", nil, nil, &secrets)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode() == http.StatusNoContent {
+		return nil, nil
+	}
+	return secrets, nil
+}
+
+// Restore secret from trash
+func (c *Client) RestoreSecret(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodPost, "/api/user/trash/"+escapePath(name), nil, nil, nil)
+	return err
+}
+
+// Permanently delete secret from trash
+func (c *Client) PurgeSecret(ctx context.Context, name string) error {
+	_, err := c.do(ctx, http.MethodDelete, "/api/user/trash/"+escapePath(name), nil, nil, nil)
+	return err
+}
+
+// Read storage usage and quota of user
+func (c *Client) FindUsage(ctx context.Context) (Usage, error) {
+	var usage Usage
+	_, err := c.do(ctx, http.MethodGet, "/api/user/usage", nil, nil, &usage)
+	return usage, err
+}
+
+// Send request, idempotent requests are retried
+func (c *Client) do(ctx context.Context, method string, path string, params map[string]string, body interface{}, result interface{}) (*resty.Response, error) {
+	return c.send(ctx, isIdempotent(method), method, path, params, body, result)
+}
+
+// Send request with retries, response with status other than 200 or 204 is returned as *Error
+func (c *Client) send(ctx context.Context, retry bool, method string, path string, params map[string]string, body interface{}, result interface{}) (*resty.Response, error) {
+	for attempt := 0; ; attempt++ {
+		request := c.http.R().SetContext(ctx).SetHeader("Content-Type", "application/json")
+		if c.token != "" {
+			request.SetHeader("Authorization", "Bearer "+c.token)
+		}
+		if params != nil {
+			request.SetQueryParams(params)
+		}
+		if body != nil {
+			request.SetBody(body)
+		}
+		if result != nil {
+			request.SetResult(result).ForceContentType("application/json")
+		}
+
+		resp, err := request.Execute(method, path)
+		if attempt < c.retries && retry && ctx.Err() == nil && isTemporary(resp, err) {
+			if err := sleep(ctx, c.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("error during send request: %w", err)
+		}
+		if resp.StatusCode() != http.StatusOK && resp.StatusCode() != http.StatusNoContent {
+			apiError := &Error{StatusCode: resp.StatusCode(), Message: strings.TrimSpace(resp.String())}
+			if attempt > 0 && isRepeatedWrite(method, resp.StatusCode()) {
+				return nil, fmt.Errorf("%w: %s", ErrAmbiguous, apiError.Error())
+			}
+			return nil, apiError
+		}
+		return resp, nil
+	}
+}
+
+// Wait before retry, doubled with each attempt up to max wait
+func (c *Client) backoff(attempt int) time.Duration {
+	wait := c.retryWait
+	for i := 0; i < attempt && wait < c.retryMaxWait; i++ {
+		wait *= 2
+	}
+	if wait > c.retryMaxWait {
+		wait = c.retryMaxWait
+	}
+	return wait
+}
+
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func isIdempotent(method string) bool {
+	return method == http.MethodGet || method == http.MethodPut || method == http.MethodDelete
+}
+
+// Response of retried write, which may be caused by previous attempt applied by server
+// with lost response: version conflict of update or absence of deleted secret
+func isRepeatedWrite(method string, statusCode int) bool {
+	return method == http.MethodPut && statusCode == http.StatusConflict ||
+		method == http.MethodDelete && statusCode == http.StatusNotFound
+}
+
+func isTemporary(resp *resty.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	switch resp.StatusCode() {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func authToken(resp *resty.Response) (string, error) {
+	token, ok := strings.CutPrefix(resp.Header().Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return "", errors.New("authorization token missing from response")
+	}
+	return token, nil
+}
+
+func filterParams(filter SecretFilter) map[string]string {
+	params := map[string]string{}
+	if filter.Name != "" {
+		params["name"] = filter.Name
+	}
+	if filter.Type != "" {
+		params["type"] = filter.Type
+	}
+	if filter.Tag != "" {
+		params["tag"] = filter.Tag
+	}
+	if filter.Folder != "" {
+		params["folder"] = filter.Folder
+	}
+	if filter.DueBefore != nil {
+		params["due_before"] = filter.DueBefore.UTC().Format(time.RFC3339)
+	}
+	if filter.Cursor != "" {
+		params["cursor"] = filter.Cursor
+	}
+	if filter.Limit > 0 {
+		params["limit"] = strconv.Itoa(filter.Limit)
+	}
+	return params
+}
+
+// Escape path-style secret name or folder path to use in URL, slashes are kept
+func escapePath(path string) string {
+	segments := strings.Split(strings.Trim(path, PathSeparator), PathSeparator)
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, PathSeparator)
+}
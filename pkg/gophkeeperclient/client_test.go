@@ -0,0 +1,204 @@
+package gophkeeperclient
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestEscapePath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{path: "prod/db/postgres", expected: "prod/db/postgres"},
+		{path: "/prod/db/", expected: "prod/db"},
+		{path: "Small text", expected: "Small%20text"},
+		{path: "cards/visa#1", expected: "cards/visa%231"},
+		{path: "", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, escapePath(tt.path))
+		})
+	}
+}
+
+func TestClient_Login(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "/api/user/login", request.URL.Path)
+		var user User
+		require.NoError(t, json.NewDecoder(request.Body).Decode(&user))
+		if user.Password != "secret" {
+			http.Error(writer, "Invalid username or password", http.StatusUnauthorized)
+			return
+		}
+		writer.Header().Set("Authorization", "Bearer token")
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	token, err := client.Login(context.Background(), User{Username: "ivan", Password: "secret"})
+	require.NoError(t, err)
+	assert.Equal(t, "token", token)
+
+	_, err = client.Login(context.Background(), User{Username: "ivan", Password: "wrong"})
+	assert.ErrorIs(t, err, ErrUnauthorized)
+	assert.EqualError(t, err, "Invalid username or password")
+}
+
+func TestClient_FindSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+		if request.URL.EscapedPath() != "/api/user/secret/cards/visa%231" {
+			http.Error(writer, "Secret was not found", http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(writer).Encode(Secret{Name: "cards/visa#1", Type: CardSecretType})
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithToken("token")
+	secret, err := client.FindSecret(context.Background(), "cards/visa#1")
+	require.NoError(t, err)
+	assert.Equal(t, "cards/visa#1", secret.Name)
+
+	_, err = client.FindSecret(context.Background(), "unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+	var apiError *Error
+	require.ErrorAs(t, err, &apiError)
+	assert.Equal(t, http.StatusNotFound, apiError.StatusCode)
+}
+
+func TestClient_FindAllSecrets(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		assert.Equal(t, "prod", request.URL.Query().Get("tag"))
+		switch request.URL.Query().Get("cursor") {
+		case "":
+			json.NewEncoder(writer).Encode(SecretPage{Secrets: []Secret{{Name: "a"}}, NextCursor: "next"})
+		case "next":
+			json.NewEncoder(writer).Encode(SecretPage{Secrets: []Secret{{Name: "b"}}})
+		}
+	}))
+	defer server.Close()
+
+	secrets, err := New(server.URL).FindAllSecrets(context.Background(), SecretFilter{Tag: "prod"})
+	require.NoError(t, err)
+	assert.Equal(t, []Secret{{Name: "a"}, {Name: "b"}}, secrets)
+}
+
+func TestClient_NoContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	client := New(server.URL)
+	page, err := client.FindSecrets(context.Background(), SecretFilter{})
+	require.NoError(t, err)
+	assert.Empty(t, page.Secrets)
+
+	secrets, err := client.FindTrash(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, secrets)
+}
+
+func TestClient_Retries(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if calls.Add(1) < 3 {
+			http.Error(writer, "Service unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		json.NewEncoder(writer).Encode(Usage{Secrets: 5})
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithRetries(3, time.Millisecond, 5*time.Millisecond)
+	usage, err := client.FindUsage(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), usage.Secrets)
+	assert.Equal(t, int32(3), calls.Load())
+
+	calls.Store(0)
+	err = client.CreateSecret(context.Background(), Secret{Name: "a"})
+	assert.EqualError(t, err, "Service unavailable")
+	assert.Equal(t, int32(1), calls.Load(), "not idempotent request should not be retried")
+
+	calls.Store(-10)
+	_, err = client.WithRetries(1, time.Millisecond, time.Millisecond).FindUsage(context.Background())
+	assert.EqualError(t, err, "Service unavailable")
+	assert.Equal(t, int32(-8), calls.Load())
+}
+
+func TestClient_FindSecretNotRetried(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		calls.Add(1)
+		http.Error(writer, "Service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := New(server.URL).WithRetries(3, time.Millisecond, time.Millisecond).FindSecret(context.Background(), "burned")
+	assert.EqualError(t, err, "Service unavailable")
+	assert.Equal(t, int32(1), calls.Load(), "read of one secret may burn it and should not be retried")
+}
+
+func TestClient_RetriedWriteAmbiguous(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		if calls.Add(1) == 1 {
+			// The first attempt is applied, but its response is lost
+			http.Error(writer, "Bad gateway", http.StatusBadGateway)
+			return
+		}
+		switch request.Method {
+		case http.MethodPut:
+			http.Error(writer, "Secret was changed", http.StatusConflict)
+		case http.MethodDelete:
+			http.Error(writer, "Secret was not found", http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	client := New(server.URL).WithRetries(3, time.Millisecond, time.Millisecond)
+	err := client.UpdateSecret(context.Background(), Secret{Name: "a", Version: 1})
+	assert.ErrorIs(t, err, ErrAmbiguous)
+	assert.NotErrorIs(t, err, ErrConflict)
+
+	calls.Store(0)
+	err = client.DeleteSecret(context.Background(), "a")
+	assert.ErrorIs(t, err, ErrAmbiguous)
+	assert.NotErrorIs(t, err, ErrNotFound)
+
+	calls.Store(1)
+	err = client.UpdateSecret(context.Background(), Secret{Name: "a", Version: 1})
+	assert.ErrorIs(t, err, ErrConflict, "conflict of the first attempt is not ambiguous")
+}
+
+func TestClient_RetriesCanceled(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		http.Error(writer, "Service unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := New(server.URL).WithRetries(10, time.Second, time.Second).FindUsage(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestClient_Backoff(t *testing.T) {
+	client := New("").WithRetries(5, 100*time.Millisecond, time.Second)
+	assert.Equal(t, 100*time.Millisecond, client.backoff(0))
+	assert.Equal(t, 200*time.Millisecond, client.backoff(1))
+	assert.Equal(t, 800*time.Millisecond, client.backoff(3))
+	assert.Equal(t, time.Second, client.backoff(4))
+}
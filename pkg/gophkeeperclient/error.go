@@ -0,0 +1,51 @@
+package gophkeeperclient
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	ErrBadRequest   = errors.New("request is not valid")
+	ErrUnauthorized = errors.New("user is not authorized")
+	ErrForbidden    = errors.New("access is denied")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("already exists")
+	ErrTooLarge     = errors.New("request is too large")
+	// Request was retried and server responded with error that may be caused by the previous attempt,
+	// so it is unknown whether request was applied
+	ErrAmbiguous = errors.New("result of retried request is unknown")
+)
+
+// Error response of server, matches sentinel errors by status code with errors.Is
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("server responded with status %d", e.StatusCode)
+	}
+	return e.Message
+}
+
+func (e *Error) Is(target error) bool {
+	switch e.StatusCode {
+	case http.StatusBadRequest:
+		return target == ErrBadRequest
+	case http.StatusUnauthorized:
+		return target == ErrUnauthorized
+	case http.StatusForbidden:
+		return target == ErrForbidden
+	case http.StatusNotFound:
+		return target == ErrNotFound
+	case http.StatusConflict:
+		return target == ErrConflict
+	case http.StatusRequestEntityTooLarge:
+		return target == ErrTooLarge
+	default:
+		return false
+	}
+}
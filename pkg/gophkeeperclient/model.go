@@ -0,0 +1,101 @@
+package gophkeeperclient
+
+import "time"
+
+// Types of secret content
+const (
+	CredentialsSecretType = "CREDENTIALS"
+	TextSecretType        = "TEXT"
+	CardSecretType        = "CARD"
+	BinarySecretType      = "BINARY"
+	OTPSecretType         = "OTP"
+	SSHKeySecretType      = "SSH_KEY"
+)
+
+// Separator of folders in path-style secret names, e.g. prod/db/postgres
+const PathSeparator = "/"
+
+// Credentials of user to register or login
+type User struct {
+	Username string `json:"login"`
+	Password string `json:"password"`
+}
+
+// Secret of user. Content and notes are sent as is, they should be encrypted by caller
+type Secret struct {
+	Name    string `json:"name"`
+	Content []byte `json:"content"`
+	Type    string `json:"type"`
+	// Version of read secret, checked by UpdateSecret
+	Version int64 `json:"version,omitempty"`
+
+	Notes []byte   `json:"notes,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+	URL   string   `json:"url,omitempty"`
+
+	// Secret lifetime, informational only: expired secrets are still readable
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	RotateAfter *time.Time `json:"rotate_after,omitempty"`
+	// Secret is deleted by server right after the first successful read
+	BurnAfterReading bool `json:"burn_after_reading,omitempty"`
+
+	// Managed by server, ignored on upload
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	LastAccessedAt *time.Time `json:"last_accessed_at,omitempty"`
+	// Filled for secrets in trash only
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+}
+
+// Filter to search user secrets
+type SecretFilter struct {
+	// Name prefix or glob pattern with '*' and '?' wildcards
+	Name string
+	// Folder containing secrets, including subfolders
+	Folder string
+	Type   string
+	Tag    string
+	// Secrets expiring or requiring rotation before time
+	DueBefore *time.Time
+	// Name of the last secret from the previous page
+	Cursor string
+	Limit  int
+}
+
+// Page of user secrets ordered by name
+type SecretPage struct {
+	Secrets    []Secret `json:"secrets"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
+
+// Result of batch secrets upload
+type SecretBatchResult struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
+
+// Content of folder: direct subfolders and secrets
+type Folder struct {
+	Path    string   `json:"path"`
+	Folders []string `json:"folders"`
+	Secrets []Secret `json:"secrets"`
+}
+
+// Storage usage of user with configured limits. Secrets in trash are counted until purged, zero limit means unlimited
+type Usage struct {
+	Secrets       int64 `json:"secrets"`
+	TrashSecrets  int64 `json:"trash_secrets"`
+	TotalSize     int64 `json:"total_size"`
+	MaxSecrets    int64 `json:"max_secrets"`
+	MaxSecretSize int64 `json:"max_secret_size"`
+	MaxTotalSize  int64 `json:"max_total_size"`
+}
+
+type secretRename struct {
+	To string `json:"to"`
+}
+
+type folderMove struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
@@ -0,0 +1,222 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Time based one-time password, RFC 6238
+	TOTPType = "totp"
+	// Counter based one-time password, RFC 4226
+	HOTPType = "hotp"
+
+	SHA1Algorithm   = "SHA1"
+	SHA256Algorithm = "SHA256"
+	SHA512Algorithm = "SHA512"
+
+	DefaultDigits = 6
+	DefaultPeriod = 30
+)
+
+// Parameters of one-time password generator
+type Key struct {
+	Type      string `json:"type"`
+	Secret    string `json:"secret"`
+	Issuer    string `json:"issuer,omitempty"`
+	Account   string `json:"account,omitempty"`
+	Algorithm string `json:"algorithm"`
+	Digits    int    `json:"digits"`
+	Period    int    `json:"period,omitempty"`
+	Counter   uint64 `json:"counter,omitempty"`
+}
+
+// Parse key from otpauth://TYPE/LABEL?PARAMETERS URI, missing parameters are filled by defaults
+func ParseURI(value string) (Key, error) {
+	uri, err := url.Parse(strings.TrimSpace(value))
+	if err != nil {
+		return Key{}, fmt.Errorf("invalid otpauth uri: %w", err)
+	}
+	if uri.Scheme != "otpauth" {
+		return Key{}, errors.New("otpauth uri should start with otpauth://")
+	}
+
+	query := uri.Query()
+	key := Key{
+		Type:      strings.ToLower(uri.Host),
+		Secret:    query.Get("secret"),
+		Issuer:    query.Get("issuer"),
+		Algorithm: SHA1Algorithm,
+		Digits:    DefaultDigits,
+	}
+
+	label := strings.TrimPrefix(uri.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		if key.Issuer == "" {
+			key.Issuer = strings.TrimSpace(issuer)
+		}
+		key.Account = strings.TrimSpace(account)
+	} else {
+		key.Account = label
+	}
+
+	if algorithm := query.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+	}
+	if digits := query.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil {
+			return Key{}, fmt.Errorf("invalid digits \"%s\"", digits)
+		}
+	}
+	switch key.Type {
+	case TOTPType:
+		key.Period = DefaultPeriod
+		if period := query.Get("period"); period != "" {
+			if key.Period, err = strconv.Atoi(period); err != nil {
+				return Key{}, fmt.Errorf("invalid period \"%s\"", period)
+			}
+		}
+	case HOTPType:
+		counter := query.Get("counter")
+		if counter == "" {
+			return Key{}, errors.New("hotp uri should contain counter")
+		}
+		if key.Counter, err = strconv.ParseUint(counter, 10, 64); err != nil {
+			return Key{}, fmt.Errorf("invalid counter \"%s\"", counter)
+		}
+	}
+
+	if err := key.Validate(); err != nil {
+		return Key{}, err
+	}
+	return key, nil
+}
+
+// Check key parameters
+func (k Key) Validate() error {
+	if k.Type != TOTPType && k.Type != HOTPType {
+		return fmt.Errorf("unknown otp type \"%s\", should be totp or hotp", k.Type)
+	}
+	if _, err := k.secretBytes(); err != nil {
+		return err
+	}
+	if _, err := newHash(k.Algorithm); err != nil {
+		return err
+	}
+	if k.Digits < 6 || k.Digits > 10 {
+		return errors.New("digits should be from 6 to 10")
+	}
+	if k.Type == TOTPType && k.Period < 1 {
+		return errors.New("period should be a positive number of seconds")
+	}
+	return nil
+}
+
+// Format key as otpauth URI
+func (k Key) URI() string {
+	query := url.Values{}
+	query.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		query.Set("issuer", k.Issuer)
+	}
+	query.Set("algorithm", k.Algorithm)
+	query.Set("digits", strconv.Itoa(k.Digits))
+	if k.Type == TOTPType {
+		query.Set("period", strconv.Itoa(k.Period))
+	} else {
+		query.Set("counter", strconv.FormatUint(k.Counter, 10))
+	}
+
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+	uri := url.URL{Scheme: "otpauth", Host: k.Type, Path: "/" + label, RawQuery: query.Encode()}
+	return uri.String()
+}
+
+// Generate code for given moment, HOTP code is generated for current counter
+func (k Key) Code(now time.Time) (string, error) {
+	secret, err := k.secretBytes()
+	if err != nil {
+		return "", err
+	}
+
+	counter := k.Counter
+	if k.Type == TOTPType {
+		if k.Period < 1 {
+			return "", errors.New("period should be a positive number of seconds")
+		}
+		counter = uint64(now.Unix()) / uint64(k.Period)
+	}
+	return HOTP(secret, counter, k.Digits, k.Algorithm)
+}
+
+// Time until current TOTP code expires
+func (k Key) Remaining(now time.Time) time.Duration {
+	if k.Type != TOTPType || k.Period < 1 {
+		return 0
+	}
+	period := int64(k.Period)
+	return time.Duration(period-now.Unix()%period) * time.Second
+}
+
+// Generate HOTP code as defined in RFC 4226
+func HOTP(secret []byte, counter uint64, digits int, algorithm string) (string, error) {
+	newHashFunc, err := newHash(algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	message := make([]byte, 8)
+	binary.BigEndian.PutUint64(message, counter)
+	mac := hmac.New(newHashFunc, secret)
+	mac.Write(message)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := uint64(binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff)
+	modulo := uint64(1)
+	for i := 0; i < digits; i++ {
+		modulo *= 10
+	}
+	return fmt.Sprintf("%0*d", digits, value%modulo), nil
+}
+
+// Decode base32 secret, padding, spaces and lower case are allowed
+func (k Key) secretBytes() ([]byte, error) {
+	secret := strings.ToUpper(strings.ReplaceAll(k.Secret, " ", ""))
+	secret = strings.TrimRight(secret, "=")
+	if secret == "" {
+		return nil, errors.New("otp secret is required")
+	}
+	decoded, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		return nil, errors.New("otp secret should be base32 encoded")
+	}
+	return decoded, nil
+}
+
+func newHash(algorithm string) (func() hash.Hash, error) {
+	switch strings.ToUpper(algorithm) {
+	case SHA1Algorithm:
+		return sha1.New, nil
+	case SHA256Algorithm:
+		return sha256.New, nil
+	case SHA512Algorithm:
+		return sha512.New, nil
+	default:
+		return nil, fmt.Errorf("unknown otp algorithm \"%s\", should be SHA1, SHA256 or SHA512", algorithm)
+	}
+}
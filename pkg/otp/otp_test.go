@@ -0,0 +1,111 @@
+package otp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestHOTP(t *testing.T) {
+	// RFC 4226 Appendix D
+	secret := []byte("12345678901234567890")
+	expected := []string{"755224", "287082", "359152", "969429", "338314", "254676", "287922", "162583", "399871", "520489"}
+	for counter, code := range expected {
+		actual, err := HOTP(secret, uint64(counter), 6, SHA1Algorithm)
+		require.NoError(t, err)
+		assert.Equal(t, code, actual)
+	}
+}
+
+func TestKey_Code(t *testing.T) {
+	// RFC 6238 Appendix B
+	testCases := []struct {
+		name      string
+		algorithm string
+		secret    string
+		unix      int64
+		code      string
+	}{
+		{name: "SHA1 59", algorithm: SHA1Algorithm, secret: "12345678901234567890", unix: 59, code: "94287082"},
+		{name: "SHA256 59", algorithm: SHA256Algorithm, secret: "12345678901234567890123456789012", unix: 59, code: "46119246"},
+		{name: "SHA512 59", algorithm: SHA512Algorithm, secret: "1234567890123456789012345678901234567890123456789012345678901234", unix: 59, code: "90693936"},
+		{name: "SHA1 1111111109", algorithm: SHA1Algorithm, secret: "12345678901234567890", unix: 1111111109, code: "07081804"},
+		{name: "SHA256 2000000000", algorithm: SHA256Algorithm, secret: "12345678901234567890123456789012", unix: 2000000000, code: "90698825"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key := Key{
+				Type:      TOTPType,
+				Secret:    base32.StdEncoding.EncodeToString([]byte(tc.secret)),
+				Algorithm: tc.algorithm,
+				Digits:    8,
+				Period:    30,
+			}
+			require.NoError(t, key.Validate())
+
+			code, err := key.Code(time.Unix(tc.unix, 0))
+			require.NoError(t, err)
+			assert.Equal(t, tc.code, code)
+		})
+	}
+}
+
+func TestKey_Remaining(t *testing.T) {
+	key := Key{Type: TOTPType, Period: 30}
+	assert.Equal(t, 1*time.Second, key.Remaining(time.Unix(59, 0)))
+	assert.Equal(t, 30*time.Second, key.Remaining(time.Unix(60, 0)))
+	assert.Equal(t, time.Duration(0), Key{Type: HOTPType}.Remaining(time.Unix(60, 0)))
+}
+
+func TestParseURI(t *testing.T) {
+	testCases := []struct {
+		name      string
+		uri       string
+		expected  Key
+		wantError bool
+	}{
+		{
+			name: "TOTP with defaults",
+			uri:  "otpauth://totp/Example:alice@google.com?secret=JBSWY3DPEHPK3PXP&issuer=Example",
+			expected: Key{Type: TOTPType, Secret: "JBSWY3DPEHPK3PXP", Issuer: "Example", Account: "alice@google.com",
+				Algorithm: SHA1Algorithm, Digits: 6, Period: 30},
+		},
+		{
+			name: "TOTP with parameters",
+			uri:  "otpauth://totp/ACME%20Co:john@example.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&algorithm=sha256&digits=8&period=60",
+			expected: Key{Type: TOTPType, Secret: "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ", Issuer: "ACME Co", Account: "john@example.com",
+				Algorithm: SHA256Algorithm, Digits: 8, Period: 60},
+		},
+		{
+			name:     "HOTP",
+			uri:      "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP&counter=7",
+			expected: Key{Type: HOTPType, Secret: "JBSWY3DPEHPK3PXP", Account: "alice", Algorithm: SHA1Algorithm, Digits: 6, Counter: 7},
+		},
+		{name: "Wrong scheme", uri: "https://totp/alice?secret=JBSWY3DPEHPK3PXP", wantError: true},
+		{name: "Unknown type", uri: "otpauth://motp/alice?secret=JBSWY3DPEHPK3PXP", wantError: true},
+		{name: "Missing secret", uri: "otpauth://totp/alice", wantError: true},
+		{name: "Invalid secret", uri: "otpauth://totp/alice?secret=not-base32!", wantError: true},
+		{name: "HOTP without counter", uri: "otpauth://hotp/alice?secret=JBSWY3DPEHPK3PXP", wantError: true},
+		{name: "Unknown algorithm", uri: "otpauth://totp/alice?secret=JBSWY3DPEHPK3PXP&algorithm=MD5", wantError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			key, err := ParseURI(tc.uri)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, key)
+
+			parsed, err := ParseURI(key.URI())
+			require.NoError(t, err)
+			assert.Equal(t, key, parsed)
+		})
+	}
+}
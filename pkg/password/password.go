@@ -0,0 +1,170 @@
+package password
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+const (
+	lowerChars  = "abcdefghijklmnopqrstuvwxyz"
+	upperChars  = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars  = "0123456789"
+	symbolChars = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	// Characters which are easy to confuse when password is read or typed by hand
+	ambiguousChars = "0O1lI|`'\";:,."
+)
+
+// EFF large wordlist for diceware passphrases, https://www.eff.org/dice
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordlist string
+
+var wordlist = sync.OnceValue(func() []string {
+	var words []string
+	for _, line := range strings.Split(effLargeWordlist, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			words = append(words, fields[1])
+		}
+	}
+	return words
+})
+
+// Policy of random password
+type Policy struct {
+	Length           int
+	Lower            bool
+	Upper            bool
+	Digits           bool
+	Symbols          bool
+	ExcludeAmbiguous bool
+}
+
+// Policy of diceware passphrase
+type PassphrasePolicy struct {
+	Words     int
+	Separator string
+}
+
+// Generate random password with at least one character of each enabled charset.
+// Returns password and estimated entropy in bits
+func Generate(policy Policy) (string, float64, error) {
+	charsets := policy.charsets()
+	if len(charsets) == 0 {
+		return "", 0, errors.New("at least one charset should be enabled")
+	}
+	if policy.Length < len(charsets) {
+		return "", 0, fmt.Errorf("password length should be at least %d", len(charsets))
+	}
+
+	alphabet := strings.Join(charsets, "")
+	password := make([]byte, 0, policy.Length)
+	for _, charset := range charsets {
+		char, err := randomChar(charset)
+		if err != nil {
+			return "", 0, err
+		}
+		password = append(password, char)
+	}
+	for len(password) < policy.Length {
+		char, err := randomChar(alphabet)
+		if err != nil {
+			return "", 0, err
+		}
+		password = append(password, char)
+	}
+	if err := shuffle(password); err != nil {
+		return "", 0, err
+	}
+
+	return string(password), Entropy(len(alphabet), policy.Length), nil
+}
+
+// Generate diceware passphrase from bundled wordlist. Returns passphrase and entropy in bits
+func GeneratePassphrase(policy PassphrasePolicy) (string, float64, error) {
+	if policy.Words < 1 {
+		return "", 0, errors.New("passphrase should contain at least one word")
+	}
+
+	words := wordlist()
+	passphrase := make([]string, 0, policy.Words)
+	for i := 0; i < policy.Words; i++ {
+		index, err := randomInt(len(words))
+		if err != nil {
+			return "", 0, err
+		}
+		passphrase = append(passphrase, words[index])
+	}
+
+	return strings.Join(passphrase, policy.Separator), Entropy(len(words), policy.Words), nil
+}
+
+// Entropy in bits of value built from count symbols chosen uniformly from alphabet of given size
+func Entropy(alphabetSize int, count int) float64 {
+	if alphabetSize < 2 || count < 1 {
+		return 0
+	}
+	return float64(count) * math.Log2(float64(alphabetSize))
+}
+
+func (p Policy) charsets() []string {
+	var charsets []string
+	for _, charset := range []struct {
+		enabled bool
+		chars   string
+	}{
+		{p.Lower, lowerChars},
+		{p.Upper, upperChars},
+		{p.Digits, digitChars},
+		{p.Symbols, symbolChars},
+	} {
+		if !charset.enabled {
+			continue
+		}
+		chars := charset.chars
+		if p.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(ambiguousChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		charsets = append(charsets, chars)
+	}
+	return charsets
+}
+
+func randomChar(chars string) (byte, error) {
+	index, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[index], nil
+}
+
+func randomInt(max int) (int, error) {
+	value, err := rand.Int(rand.Reader, big.NewInt(int64(max)))
+	if err != nil {
+		return 0, fmt.Errorf("error during read random: %w", err)
+	}
+	return int(value.Int64()), nil
+}
+
+// Fisher-Yates shuffle, so required characters are not always at the beginning
+func shuffle(value []byte) error {
+	for i := len(value) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return err
+		}
+		value[i], value[j] = value[j], value[i]
+	}
+	return nil
+}
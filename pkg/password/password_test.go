@@ -0,0 +1,78 @@
+package password
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		policy    Policy
+		allowed   string
+		required  []string
+		wantError bool
+	}{
+		{
+			name:     "All charsets",
+			policy:   Policy{Length: 32, Lower: true, Upper: true, Digits: true, Symbols: true},
+			allowed:  lowerChars + upperChars + digitChars + symbolChars,
+			required: []string{lowerChars, upperChars, digitChars, symbolChars},
+		},
+		{
+			name:     "Digits only",
+			policy:   Policy{Length: 6, Digits: true},
+			allowed:  digitChars,
+			required: []string{digitChars},
+		},
+		{
+			name:    "Exclude ambiguous",
+			policy:  Policy{Length: 64, Lower: true, Upper: true, Digits: true, ExcludeAmbiguous: true},
+			allowed: "abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789",
+		},
+		{
+			name:      "No charsets",
+			policy:    Policy{Length: 16},
+			wantError: true,
+		},
+		{
+			name:      "Too short for required charsets",
+			policy:    Policy{Length: 2, Lower: true, Upper: true, Digits: true},
+			wantError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			password, entropy, err := Generate(tc.policy)
+			if tc.wantError {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Len(t, password, tc.policy.Length)
+			assert.Equal(t, Entropy(len(tc.allowed), tc.policy.Length), entropy)
+			for _, char := range password {
+				assert.Contains(t, tc.allowed, string(char))
+			}
+			for _, charset := range tc.required {
+				assert.True(t, strings.ContainsAny(password, charset), "password %s has no chars of %s", password, charset)
+			}
+		})
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	assert.Len(t, wordlist(), 7776)
+
+	passphrase, entropy, err := GeneratePassphrase(PassphrasePolicy{Words: 6, Separator: " "})
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(passphrase, " "), 6)
+	assert.InDelta(t, 77.5, entropy, 0.1)
+
+	_, _, err = GeneratePassphrase(PassphrasePolicy{Words: 0})
+	assert.Error(t, err)
+}
@@ -53,7 +53,7 @@ func InitPostgresIntegrationTest(t *testing.T, ctx context.Context, logger *zap.
 }
 
 func ClearTables(ctx context.Context, pool *pgxpool.Pool) error {
-	tables := []string{"user", "secret"}
+	tables := []string{"user", "secret", "secret_trash"}
 	for _, table := range tables {
 		query := fmt.Sprintf("TRUNCATE TABLE gophkeeper.%s CASCADE", table)
 		if _, err := pool.Exec(ctx, query); err != nil {